@@ -15,4 +15,10 @@ type DataHandler interface {
 
 	// HandleRegistration handles the registration data from the device.
 	HandleRegistration(deviceID string, js JSON) error
+
+	// HandleStream handles the data of the additional device stream.
+	//
+	// Parameters:
+	//  - measurement - where the stream data should be stored.
+	HandleStream(deviceID string, measurement string, js JSON) error
 }
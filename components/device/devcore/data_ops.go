@@ -0,0 +1,154 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+func parseJSON(buf []byte) (JSON, error) {
+	var js JSON
+
+	if err := json.Unmarshal(buf, &js); err != nil {
+		return nil, err
+	}
+
+	return js, nil
+}
+
+func lookupPath(js JSON, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+
+	var value any = map[string]any(js)
+
+	for _, key := range keys {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		value, ok = obj[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// FlattenJSON flattens the nested objects and arrays of the device data.
+//
+// Remarks:
+//   - {"sensors": {"soil": {"moisture": 42}}, "values": [1, 2]} is flattened to
+//     the "sensors_soil_moisture", "values_0" and "values_1" keys, if the separator is "_".
+//   - Keys are flattened in the sorted order, so the colliding keys are resolved
+//     in the same way each time.
+//   - null values are skipped.
+func FlattenJSON(js JSON, separator string) map[string]any {
+	fields := make(map[string]any)
+
+	flattenValue(fields, separator, "", map[string]any(js))
+
+	return fields
+}
+
+func flattenValue(fields map[string]any, separator string, key string, value any) {
+	join := func(k string) string {
+		if key == "" {
+			return k
+		}
+
+		return key + separator + k
+	}
+
+	switch v := value.(type) {
+	case nil:
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			flattenValue(fields, separator, join(k), v[k])
+		}
+	case []any:
+		for n, item := range v {
+			flattenValue(fields, separator, join(strconv.Itoa(n)), item)
+		}
+	default:
+		fields[key] = value
+	}
+}
+
+func parseDeviceID(js JSON, schema DataSchema) (string, error) {
+	id, ok := lookupPath(js, schema.IDPath)
+	if !ok {
+		return "", fmt.Errorf("missing %s field", schema.IDPath)
+	}
+
+	deviceID, ok := id.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid type for %s", schema.IDPath)
+	}
+
+	return deviceID, nil
+}
+
+// parseTimestamp returns the UNIX timestamp in seconds.
+//
+// Remarks:
+//   - The timestamp is stored in seconds in the "timestamp" field, to be consistently
+//     handled by the data handlers, regardless of the device data schema.
+func parseTimestamp(js JSON, schema DataSchema) (int64, error) {
+	ts, ok := lookupPath(js, schema.TimestampPath)
+	if !ok {
+		return -1, fmt.Errorf("missing %s field", schema.TimestampPath)
+	}
+
+	value, ok := ts.(float64)
+	if !ok {
+		return -1, fmt.Errorf("invalid type for %s", schema.TimestampPath)
+	}
+
+	timestamp := int64(value)
+
+	if timestamp > 0 {
+		timestamp = schema.toSeconds(timestamp)
+	}
+
+	js["timestamp"] = float64(timestamp)
+
+	return timestamp, nil
+}
+
+// verifyTimestamp ensures the device UNIX time is valid, and starts the device time
+// synchronization if it's not.
+func verifyTimestamp(
+	timestamp int64,
+	verifier TimeVerifier,
+	synchronizer TimeSynchronizer,
+	deviceID string,
+) error {
+	if verifier.VerifyTime(timestamp) {
+		return nil
+	}
+
+	syscore.LogInf.Printf("start syncing time for device: ID=%v", deviceID)
+
+	if err := synchronizer.SyncTime(); err != nil {
+		return fmt.Errorf("%w: failed to sync device time: %v", ErrInvalidTimestamp, err)
+	}
+
+	return ErrInvalidTimestamp
+}
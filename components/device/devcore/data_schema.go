@@ -0,0 +1,105 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TimestampUnit is a unit of the UNIX timestamp reported by the device.
+type TimestampUnit int
+
+const (
+	// TimestampUnitSecond - timestamp is in seconds.
+	TimestampUnitSecond TimestampUnit = iota
+
+	// TimestampUnitMillisecond - timestamp is in milliseconds.
+	TimestampUnitMillisecond
+
+	// TimestampUnitMicrosecond - timestamp is in microseconds.
+	TimestampUnitMicrosecond
+)
+
+// ParseTimestampUnit parses the timestamp unit from its short name: s, ms or us.
+func ParseTimestampUnit(s string) (TimestampUnit, error) {
+	switch s {
+	case "", "s":
+		return TimestampUnitSecond, nil
+	case "ms":
+		return TimestampUnitMillisecond, nil
+	case "us":
+		return TimestampUnitMicrosecond, nil
+	default:
+		return TimestampUnitSecond, fmt.Errorf("unknown timestamp unit: %s", s)
+	}
+}
+
+// DataSchema describes where the well-known fields are located in the device data.
+//
+// Remarks:
+//   - Paths are dot-separated, e.g. "meta.device.id" for the nested JSON objects.
+type DataSchema struct {
+	// IDPath - path to the device ID in the registration data.
+	IDPath string
+
+	// TimestampPath - path to the UNIX timestamp in the registration and telemetry data.
+	TimestampPath string
+
+	// TimestampUnit - unit of the UNIX timestamp.
+	TimestampUnit TimestampUnit
+}
+
+// DefaultDataSchema returns the schema used by the control-components firmware.
+func DefaultDataSchema() DataSchema {
+	return DataSchema{
+		IDPath:        "device_id",
+		TimestampPath: "timestamp",
+		TimestampUnit: TimestampUnitSecond,
+	}
+}
+
+// Validate ensures the schema is correctly configured.
+func (s DataSchema) Validate() error {
+	if err := validatePath(s.IDPath); err != nil {
+		return fmt.Errorf("invalid ID path: %w", err)
+	}
+
+	if err := validatePath(s.TimestampPath); err != nil {
+		return fmt.Errorf("invalid timestamp path: %w", err)
+	}
+
+	if s.TimestampUnit < TimestampUnitSecond || s.TimestampUnit > TimestampUnitMicrosecond {
+		return fmt.Errorf("invalid timestamp unit: %d", s.TimestampUnit)
+	}
+
+	return nil
+}
+
+func validatePath(path string) error {
+	if path == "" {
+		return fmt.Errorf("empty path")
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		if key == "" {
+			return fmt.Errorf("empty key: path=%s", path)
+		}
+	}
+
+	return nil
+}
+
+func (s DataSchema) toSeconds(timestamp int64) int64 {
+	switch s.TimestampUnit {
+	case TimestampUnitMillisecond:
+		return timestamp / 1000
+	case TimestampUnitMicrosecond:
+		return timestamp / 1000000
+	default:
+		return timestamp
+	}
+}
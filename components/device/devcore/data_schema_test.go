@@ -0,0 +1,57 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataSchemaValidate(t *testing.T) {
+	require.Nil(t, DefaultDataSchema().Validate())
+
+	tests := []struct {
+		name   string
+		schema DataSchema
+	}{
+		{"empty ID path", DataSchema{TimestampPath: "timestamp"}},
+		{"empty timestamp path", DataSchema{IDPath: "device_id"}},
+		{"empty key", DataSchema{IDPath: "meta..id", TimestampPath: "timestamp"}},
+		{"invalid unit", DataSchema{
+			IDPath:        "device_id",
+			TimestampPath: "timestamp",
+			TimestampUnit: TimestampUnit(42),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NotNil(t, test.schema.Validate())
+		})
+	}
+}
+
+func TestParseTimestampUnit(t *testing.T) {
+	tests := []struct {
+		str  string
+		unit TimestampUnit
+	}{
+		{"", TimestampUnitSecond},
+		{"s", TimestampUnitSecond},
+		{"ms", TimestampUnitMillisecond},
+		{"us", TimestampUnitMicrosecond},
+	}
+
+	for _, test := range tests {
+		unit, err := ParseTimestampUnit(test.str)
+		require.Nil(t, err)
+		require.Equal(t, test.unit, unit)
+	}
+
+	_, err := ParseTimestampUnit("ns")
+	require.NotNil(t, err)
+}
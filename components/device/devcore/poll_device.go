@@ -6,11 +6,12 @@
 package devcore
 
 import (
-	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/tendry-lab/device-hub/components/status"
 	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
 )
 
 // PollDevice actively fetches telemetry and registration data.
@@ -21,7 +22,32 @@ type PollDevice struct {
 	dataHandler         DataHandler
 	timeSynchronizer    TimeSynchronizer
 	timeVerifier        TimeVerifier
+	schema              DataSchema
 	deviceID            string
+
+	registrationClock    syscore.MonotonicClock
+	registrationInterval time.Duration
+	registrationTime     time.Time
+	registered           bool
+
+	registrationMetrics pollDeviceMetrics
+	telemetryMetrics    pollDeviceMetrics
+}
+
+type pollDeviceMetrics struct {
+	duration *sysmetrics.Summary
+	errors   *sysmetrics.Counter
+}
+
+func newPollDeviceMetrics(registry *sysmetrics.Registry, kind string) pollDeviceMetrics {
+	label := sysmetrics.Label{Name: "kind", Value: kind}
+
+	return pollDeviceMetrics{
+		duration: registry.Summary("device_hub_device_fetch_duration_seconds",
+			"Duration of the device data fetching.", label),
+		errors: registry.Counter("device_hub_device_fetch_errors",
+			"Number of the failed device data fetches.", label),
+	}
 }
 
 // NewPollDevice initializes polling device.
@@ -47,53 +73,126 @@ func NewPollDevice(
 		dataHandler:         dataHandler,
 		timeSynchronizer:    timeSynchronizer,
 		timeVerifier:        timeVerifier,
+		schema:              DefaultDataSchema(),
 	}
 }
 
+// SetDataSchema sets the schema of the device data.
+//
+// Remarks:
+//   - DefaultDataSchema() is used if not set.
+//   - Should be called before Run().
+func (d *PollDevice) SetDataSchema(schema DataSchema) {
+	d.schema = schema
+}
+
+// SetRegistrationInterval sets how often to fetch the registration data.
+//
+// Parameters:
+//   - clock to measure time since the last registration.
+//   - interval - registration fetch interval, registration is fetched on each Run() if zero.
+//
+// Remarks:
+//   - Registration is always fetched after any Run() failure.
+//   - Should be called before Run().
+func (d *PollDevice) SetRegistrationInterval(
+	clock syscore.MonotonicClock,
+	interval time.Duration,
+) {
+	d.registrationClock = clock
+	d.registrationInterval = interval
+}
+
+// SetMetrics sets the registry to report the fetch latencies and failures.
+//
+// Remarks:
+//   - Metrics aren't reported if not set.
+//   - Should be called before Run().
+func (d *PollDevice) SetMetrics(registry *sysmetrics.Registry) {
+	d.registrationMetrics = newPollDeviceMetrics(registry, "registration")
+	d.telemetryMetrics = newPollDeviceMetrics(registry, "telemetry")
+}
+
 // Run fetches telemetry and registration data and pass them to the underlying handlers.
 func (d *PollDevice) Run() error {
-	registrationData, err := d.fetchRegistration()
-	if err != nil {
-		syscore.LogErr.Printf("fetch registration failed: %v", err)
+	if err := d.run(); err != nil {
+		d.registered = false
 
-		return status.StatusError
+		return err
+	}
+
+	return nil
+}
+
+func (d *PollDevice) run() error {
+	needRegistration := d.needRegistration()
+
+	var registrationData JSON
+
+	if needRegistration {
+		js, err := d.fetchRegistration()
+		if err != nil {
+			return fmt.Errorf("%w: failed to fetch registration: %w", status.StatusError, err)
+		}
+
+		registrationData = js
 	}
 
 	telemetryData, err := d.fetchTelemetry()
 	if err != nil {
-		syscore.LogErr.Printf("fetch telemetry failed: %v", err)
-
-		return status.StatusError
+		return fmt.Errorf("%w: failed to fetch telemetry: %w", status.StatusError, err)
 	}
 
-	if err := d.dataHandler.HandleRegistration(d.deviceID, registrationData); err != nil {
-		syscore.LogErr.Printf("handle registration failed: %v", err)
+	if needRegistration {
+		if err := d.dataHandler.HandleRegistration(d.deviceID, registrationData); err != nil {
+			return fmt.Errorf("%w: failed to handle registration: %w", status.StatusError, err)
+		}
+
+		d.registered = true
 
-		return status.StatusError
+		if d.registrationClock != nil {
+			d.registrationTime = d.registrationClock.Now()
+		}
 	}
 
 	if err := d.dataHandler.HandleTelemetry(d.deviceID, telemetryData); err != nil {
-		syscore.LogErr.Printf("handle telemetry failed: %v", err)
-
-		return status.StatusError
+		return fmt.Errorf("%w: failed to handle telemetry: %w", status.StatusError, err)
 	}
 
 	return nil
 }
 
+func (d *PollDevice) needRegistration() bool {
+	if !d.registered || d.registrationInterval == 0 || d.registrationClock == nil {
+		return true
+	}
+
+	return d.registrationClock.Now().Sub(d.registrationTime) >= d.registrationInterval
+}
+
 func (d *PollDevice) fetchRegistration() (JSON, error) {
+	js, err := d.doFetchRegistration()
+	if err != nil {
+		d.registrationMetrics.errors.Inc()
+	}
+
+	return js, err
+}
+
+func (d *PollDevice) doFetchRegistration() (JSON, error) {
+	start := time.Now()
 	buf, err := d.registrationFetcher.Fetch()
+	d.registrationMetrics.duration.ObserveDuration(time.Since(start))
 	if err != nil {
 		return nil, err
 	}
 
-	var js JSON
-	err = json.Unmarshal(buf, &js)
+	js, err := parseJSON(buf)
 	if err != nil {
 		return nil, err
 	}
 
-	err = d.parseDeviceID(js)
+	err = d.updateDeviceID(js)
 	if err != nil {
 		return nil, err
 	}
@@ -108,14 +207,23 @@ func (d *PollDevice) fetchRegistration() (JSON, error) {
 }
 
 func (d *PollDevice) fetchTelemetry() (JSON, error) {
+	js, err := d.doFetchTelemetry()
+	if err != nil {
+		d.telemetryMetrics.errors.Inc()
+	}
+
+	return js, err
+}
+
+func (d *PollDevice) doFetchTelemetry() (JSON, error) {
+	start := time.Now()
 	buf, err := d.telemetryFetcher.Fetch()
+	d.telemetryMetrics.duration.ObserveDuration(time.Since(start))
 	if err != nil {
 		return nil, err
 	}
 
-	var js JSON
-
-	err = json.Unmarshal(buf, &js)
+	js, err := parseJSON(buf)
 	if err != nil {
 		return nil, err
 	}
@@ -128,40 +236,24 @@ func (d *PollDevice) fetchTelemetry() (JSON, error) {
 }
 
 func (d *PollDevice) validateTimestamp(js JSON) error {
-	ts, ok := js["timestamp"]
-	if !ok {
-		return fmt.Errorf("poll-device: failed to fetch data: missing timestamp field")
+	timestamp, err := parseTimestamp(js, d.schema)
+	if err != nil {
+		return fmt.Errorf("poll-device: failed to fetch data: %w", err)
 	}
 
-	timestamp, ok := ts.(float64)
-	if !ok {
-		return fmt.Errorf("poll-device: failed to fetch data: invalid type for timestamp")
-	}
-
-	if !d.timeVerifier.VerifyTime(int64(timestamp)) {
-		syscore.LogInf.Printf("start syncing time for device: ID=%v", d.deviceID)
-
-		if err := d.timeSynchronizer.SyncTime(); err != nil {
-			return fmt.Errorf("failed to sync device time: %v", err)
-		}
-
-		return fmt.Errorf("failed to fetch data: invalid timestamp")
+	if err := verifyTimestamp(
+		timestamp, d.timeVerifier, d.timeSynchronizer, d.deviceID,
+	); err != nil {
+		return fmt.Errorf("poll-device: failed to fetch data: %w", err)
 	}
 
 	return nil
 }
 
-func (d *PollDevice) parseDeviceID(js JSON) error {
-	id, ok := js["device_id"]
-	if !ok {
-		return fmt.Errorf(
-			"poll-device: failed to fetch registration: missing device_id field")
-	}
-
-	deviceID, ok := id.(string)
-	if !ok {
-		return fmt.Errorf(
-			"poll-device: failed to fetch registration: invalid type for device_id")
+func (d *PollDevice) updateDeviceID(js JSON) error {
+	deviceID, err := parseDeviceID(js, d.schema)
+	if err != nil {
+		return fmt.Errorf("poll-device: failed to fetch registration: %w", err)
 	}
 
 	if d.deviceID != "" && d.deviceID != deviceID {
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -16,11 +17,14 @@ import (
 )
 
 type testFetcher[T any] struct {
-	data T
-	err  error
+	data      T
+	err       error
+	callCount int
 }
 
 func (f *testFetcher[T]) Fetch() ([]byte, error) {
+	f.callCount++
+
 	if f.err != nil {
 		return nil, f.err
 	}
@@ -47,6 +51,7 @@ type testTelemetryData struct {
 type testDataHandler struct {
 	telemetry    testTelemetryData
 	registration testRegistrationData
+	streams      map[string]JSON
 	err          error
 }
 
@@ -84,6 +89,20 @@ func (d *testDataHandler) HandleRegistration(_ string, js JSON) error {
 	return nil
 }
 
+func (d *testDataHandler) HandleStream(_ string, measurement string, js JSON) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	if d.streams == nil {
+		d.streams = make(map[string]JSON)
+	}
+
+	d.streams[measurement] = js
+
+	return nil
+}
+
 type testTimeSynchronizer struct {
 	err       error
 	callCount int
@@ -99,6 +118,14 @@ func (s *testTimeSynchronizer) SyncTime() error {
 	return nil
 }
 
+type testMonotonicClock struct {
+	now time.Time
+}
+
+func (c *testMonotonicClock) Now() time.Time {
+	return c.now
+}
+
 func TestPollDeviceRun(t *testing.T) {
 	deviceID := "0xABCD"
 	testTimestamp := 13
@@ -684,3 +711,145 @@ func TestPollDeviceSynchronizeTimeError(t *testing.T) {
 	require.Equal(t, float64(0), dataHandler.registration.Timestamp)
 	require.Equal(t, float64(0), dataHandler.telemetry.Timestamp)
 }
+
+func TestPollDeviceRunDataSchema(t *testing.T) {
+	deviceID := "0xABCD"
+
+	registrationFetcher := testFetcher[JSON]{
+		data: JSON{
+			"meta": map[string]any{
+				"id": deviceID,
+				"ts": float64(13000),
+			},
+		},
+	}
+
+	telemetryFetcher := testFetcher[JSON]{
+		data: JSON{
+			"meta": map[string]any{
+				"ts": float64(14999),
+			},
+			"temperature": float64(42.135),
+		},
+	}
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	idHolder := NewIDHolder()
+
+	device := NewPollDevice(
+		&registrationFetcher,
+		&telemetryFetcher,
+		idHolder,
+		&dataHandler,
+		&timeSynchronizer,
+		&BasicTimeVerifier{},
+	)
+	device.SetDataSchema(DataSchema{
+		IDPath:        "meta.id",
+		TimestampPath: "meta.ts",
+		TimestampUnit: TimestampUnitMillisecond,
+	})
+
+	require.Nil(t, device.Run())
+	require.Equal(t, deviceID, idHolder.Get())
+	require.Equal(t, float64(13), dataHandler.registration.Timestamp)
+	require.Equal(t, float64(14), dataHandler.telemetry.Timestamp)
+	require.Equal(t, 42.135, dataHandler.telemetry.Temperature)
+	require.Equal(t, 0, timeSynchronizer.callCount)
+}
+
+func TestPollDeviceRunDataSchemaInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data JSON
+	}{
+		{"missed nested object", JSON{"id": "0xABCD", "ts": float64(13000)}},
+		{"nested field isn't object", JSON{"meta": "0xABCD"}},
+		{"missed nested ID", JSON{"meta": map[string]any{"ts": float64(13000)}}},
+		{"invalid nested ID", JSON{"meta": map[string]any{"id": 13, "ts": float64(13000)}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dataHandler := testDataHandler{}
+
+			device := NewPollDevice(
+				&testFetcher[JSON]{data: test.data},
+				&testFetcher[JSON]{data: JSON{"meta": map[string]any{"ts": float64(13000)}}},
+				NewIDHolder(),
+				&dataHandler,
+				&testTimeSynchronizer{},
+				&BasicTimeVerifier{},
+			)
+			device.SetDataSchema(DataSchema{
+				IDPath:        "meta.id",
+				TimestampPath: "meta.ts",
+				TimestampUnit: TimestampUnitMillisecond,
+			})
+
+			require.NotNil(t, device.Run())
+			require.Empty(t, dataHandler.registration.DeviceID)
+		})
+	}
+}
+
+func TestPollDeviceRunRegistrationInterval(t *testing.T) {
+	deviceID := "0xABCD"
+	testTimestamp := 13
+
+	registrationFetcher := testFetcher[testRegistrationData]{
+		data: testRegistrationData{
+			DeviceID:  deviceID,
+			Timestamp: float64(testTimestamp),
+		},
+	}
+
+	telemetryFetcher := testFetcher[testTelemetryData]{
+		data: testTelemetryData{
+			Timestamp:   float64(testTimestamp),
+			Temperature: float64(42.135),
+		},
+	}
+
+	dataHandler := testDataHandler{}
+	clock := testMonotonicClock{now: time.Now()}
+
+	device := NewPollDevice(
+		&registrationFetcher,
+		&telemetryFetcher,
+		NewIDHolder(),
+		&dataHandler,
+		&testTimeSynchronizer{},
+		&BasicTimeVerifier{},
+	)
+	device.SetRegistrationInterval(&clock, time.Minute*10)
+
+	require.Nil(t, device.Run())
+	require.Equal(t, 1, registrationFetcher.callCount)
+	require.Equal(t, 1, telemetryFetcher.callCount)
+
+	for n := 0; n < 3; n++ {
+		clock.now = clock.now.Add(time.Minute)
+
+		require.Nil(t, device.Run())
+		require.Equal(t, 1, registrationFetcher.callCount)
+		require.Equal(t, 2+n, telemetryFetcher.callCount)
+	}
+
+	clock.now = clock.now.Add(time.Minute * 10)
+
+	require.Nil(t, device.Run())
+	require.Equal(t, 2, registrationFetcher.callCount)
+	require.Equal(t, 5, telemetryFetcher.callCount)
+
+	// Registration should be re-fetched after the failure.
+	telemetryFetcher.err = errors.New("failed to fetch")
+	require.NotNil(t, device.Run())
+	require.Equal(t, 2, registrationFetcher.callCount)
+
+	telemetryFetcher.err = nil
+	require.Nil(t, device.Run())
+	require.Equal(t, 3, registrationFetcher.callCount)
+	require.Equal(t, deviceID, dataHandler.registration.DeviceID)
+}
@@ -0,0 +1,95 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"fmt"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// PollStream actively fetches data of the additional device stream.
+//
+// Remarks:
+//   - Data is fetched only when the device ID is known, e.g. after the device
+//     registration data is fetched by PollDevice.
+//   - Device time isn't synchronized, data with the invalid timestamp is rejected.
+type PollStream struct {
+	fetcher      Fetcher
+	idHolder     *IDHolder
+	dataHandler  DataHandler
+	timeVerifier TimeVerifier
+	measurement  string
+	schema       DataSchema
+}
+
+// NewPollStream initializes polling stream.
+//
+// Parameters:
+//   - fetcher to fetch the stream data.
+//   - idHolder to get the device ID.
+//   - dataHandler to handle fetched stream data.
+//   - timeVerifier to verify the UNIX time of the fetched data.
+//   - measurement - where the stream data should be stored.
+func NewPollStream(
+	fetcher Fetcher,
+	idHolder *IDHolder,
+	dataHandler DataHandler,
+	timeVerifier TimeVerifier,
+	measurement string,
+) *PollStream {
+	return &PollStream{
+		fetcher:      fetcher,
+		idHolder:     idHolder,
+		dataHandler:  dataHandler,
+		timeVerifier: timeVerifier,
+		measurement:  measurement,
+		schema:       DefaultDataSchema(),
+	}
+}
+
+// SetDataSchema sets the schema of the stream data.
+//
+// Remarks:
+//   - DefaultDataSchema() is used if not set.
+//   - Should be called before Run().
+func (s *PollStream) SetDataSchema(schema DataSchema) {
+	s.schema = schema
+}
+
+// Run fetches the stream data and passes it to the underlying handler.
+func (s *PollStream) Run() error {
+	deviceID := s.idHolder.Get()
+	if deviceID == "" {
+		return fmt.Errorf("poll-stream: unknown device ID: measurement=%s err=%w",
+			s.measurement, status.StatusInvalidState)
+	}
+
+	buf, err := s.fetcher.Fetch()
+	if err != nil {
+		return fmt.Errorf("poll-stream: failed to fetch data: measurement=%s err=%w",
+			s.measurement, err)
+	}
+
+	js, err := parseJSON(buf)
+	if err != nil {
+		return fmt.Errorf("poll-stream: failed to parse data: measurement=%s err=%w",
+			s.measurement, err)
+	}
+
+	timestamp, err := parseTimestamp(js, s.schema)
+	if err != nil {
+		return fmt.Errorf("poll-stream: failed to parse data: measurement=%s err=%w",
+			s.measurement, err)
+	}
+
+	if !s.timeVerifier.VerifyTime(timestamp) {
+		return fmt.Errorf("poll-stream: failed to verify data: measurement=%s err=%w",
+			s.measurement, ErrInvalidTimestamp)
+	}
+
+	return s.dataHandler.HandleStream(deviceID, s.measurement, js)
+}
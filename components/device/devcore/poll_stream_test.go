@@ -0,0 +1,96 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+func TestPollStreamRun(t *testing.T) {
+	deviceID := "0xABCD"
+
+	fetcher := testFetcher[JSON]{
+		data: JSON{
+			"timestamp": float64(13),
+			"rssi":      float64(-42),
+		},
+	}
+
+	dataHandler := testDataHandler{}
+	idHolder := NewIDHolder()
+
+	stream := NewPollStream(&fetcher, idHolder, &dataHandler, &BasicTimeVerifier{}, "network")
+
+	err := stream.Run()
+	require.True(t, errors.Is(err, status.StatusInvalidState))
+	require.Equal(t, 0, fetcher.callCount)
+	require.Empty(t, dataHandler.streams)
+
+	idHolder.Set(deviceID)
+
+	require.Nil(t, stream.Run())
+	require.Equal(t, 1, fetcher.callCount)
+	require.Equal(t, fetcher.data, dataHandler.streams["network"])
+}
+
+func TestPollStreamRunInvalidTimestamp(t *testing.T) {
+	for _, timestamp := range []any{float64(-1), "13", nil} {
+		dataHandler := testDataHandler{}
+		idHolder := NewIDHolder()
+		idHolder.Set("0xABCD")
+
+		stream := NewPollStream(
+			&testFetcher[JSON]{data: JSON{"timestamp": timestamp}},
+			idHolder,
+			&dataHandler,
+			&BasicTimeVerifier{},
+			"network",
+		)
+
+		require.NotNil(t, stream.Run())
+		require.Empty(t, dataHandler.streams)
+	}
+
+	idHolder := NewIDHolder()
+	idHolder.Set("0xABCD")
+
+	stream := NewPollStream(
+		&testFetcher[JSON]{data: JSON{"timestamp": float64(-1)}},
+		idHolder,
+		&testDataHandler{},
+		&BasicTimeVerifier{},
+		"network",
+	)
+
+	require.True(t, errors.Is(stream.Run(), ErrInvalidTimestamp))
+}
+
+func TestPollStreamRunDataSchema(t *testing.T) {
+	dataHandler := testDataHandler{}
+	idHolder := NewIDHolder()
+	idHolder.Set("0xABCD")
+
+	stream := NewPollStream(
+		&testFetcher[JSON]{data: JSON{"meta": map[string]any{"ts": float64(13000)}}},
+		idHolder,
+		&dataHandler,
+		&BasicTimeVerifier{},
+		"diagnostics",
+	)
+	stream.SetDataSchema(DataSchema{
+		IDPath:        "meta.id",
+		TimestampPath: "meta.ts",
+		TimestampUnit: TimestampUnitMillisecond,
+	})
+
+	require.Nil(t, stream.Run())
+	require.Equal(t, float64(13), dataHandler.streams["diagnostics"]["timestamp"])
+}
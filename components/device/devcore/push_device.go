@@ -0,0 +1,147 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// PushDevice handles telemetry and registration data pushed by the device.
+//
+// Remarks:
+//   - Registration data should be pushed first, since the device ID is unknown until then,
+//     unless the device ID is known in advance and is set in the ID holder.
+type PushDevice struct {
+	idHolder         *IDHolder
+	dataHandler      DataHandler
+	timeSynchronizer TimeSynchronizer
+	timeVerifier     TimeVerifier
+	schema           DataSchema
+
+	mu       sync.Mutex
+	deviceID string
+}
+
+// NewPushDevice initializes pushing device.
+//
+// Parameters:
+//   - idHolder to update the device ID, if it already contains the ID, registration
+//     data is expected to have the same ID.
+//   - dataHandler to handle pushed telemetry and registration data.
+//   - timeSynchronizer to synchronize the UNIX time for a device.
+//   - timeVerifier to verify the UNIX time of the pushed data.
+func NewPushDevice(
+	idHolder *IDHolder,
+	dataHandler DataHandler,
+	timeSynchronizer TimeSynchronizer,
+	timeVerifier TimeVerifier,
+) *PushDevice {
+	return &PushDevice{
+		idHolder:         idHolder,
+		dataHandler:      dataHandler,
+		timeSynchronizer: timeSynchronizer,
+		timeVerifier:     timeVerifier,
+		schema:           DefaultDataSchema(),
+		deviceID:         idHolder.Get(),
+	}
+}
+
+// SetDataSchema sets the schema of the device data.
+//
+// Remarks:
+//   - DefaultDataSchema() is used if not set.
+//   - Should be called before the data is handled.
+func (d *PushDevice) SetDataSchema(schema DataSchema) {
+	d.schema = schema
+}
+
+// HandleRegistration validates the registration data and passes it to the underlying handler.
+//
+// Remarks:
+//   - Can be used by multiple goroutines.
+func (d *PushDevice) HandleRegistration(buf []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	js, err := parseJSON(buf)
+	if err != nil {
+		return fmt.Errorf("push-device: failed to parse registration: %w", err)
+	}
+
+	deviceID, err := parseDeviceID(js, d.schema)
+	if err != nil {
+		return fmt.Errorf("push-device: failed to parse registration: %w", err)
+	}
+
+	if deviceID == "" {
+		return fmt.Errorf("push-device: failed to parse registration: empty device_id")
+	}
+
+	if d.deviceID != "" && d.deviceID != deviceID {
+		return fmt.Errorf(
+			"push-device: failed to parse registration: device ID mismatch: want=%s got=%s",
+			d.deviceID, deviceID,
+		)
+	}
+
+	if d.deviceID == "" {
+		syscore.LogInf.Printf("device ID received: %s", deviceID)
+
+		d.deviceID = deviceID
+		d.idHolder.Set(deviceID)
+	}
+
+	if err := d.validateTimestamp(js); err != nil {
+		return err
+	}
+
+	return d.dataHandler.HandleRegistration(d.deviceID, js)
+}
+
+// HandleTelemetry validates the telemetry data and passes it to the underlying handler.
+//
+// Remarks:
+//   - Can be used by multiple goroutines.
+//   - status.StatusInvalidState is returned if the registration data wasn't received yet.
+func (d *PushDevice) HandleTelemetry(buf []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.deviceID == "" {
+		return fmt.Errorf("push-device: failed to handle telemetry: unknown device ID: %w",
+			status.StatusInvalidState)
+	}
+
+	js, err := parseJSON(buf)
+	if err != nil {
+		return fmt.Errorf("push-device: failed to parse telemetry: %w", err)
+	}
+
+	if err := d.validateTimestamp(js); err != nil {
+		return err
+	}
+
+	return d.dataHandler.HandleTelemetry(d.deviceID, js)
+}
+
+func (d *PushDevice) validateTimestamp(js JSON) error {
+	timestamp, err := parseTimestamp(js, d.schema)
+	if err != nil {
+		return fmt.Errorf("push-device: failed to handle data: %w", err)
+	}
+
+	if err := verifyTimestamp(
+		timestamp, d.timeVerifier, d.timeSynchronizer, d.deviceID,
+	); err != nil {
+		return fmt.Errorf("push-device: failed to handle data: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devcore
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+func makeTestPushDeviceData(t *testing.T, data any) []byte {
+	buf, err := json.Marshal(data)
+	require.Nil(t, err)
+
+	return buf
+}
+
+func TestPushDeviceHandle(t *testing.T) {
+	deviceID := "0xABCD"
+	testTimestamp := float64(13)
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	idHolder := NewIDHolder()
+
+	device := NewPushDevice(idHolder, &dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	require.Nil(t, device.HandleRegistration(makeTestPushDeviceData(t, testRegistrationData{
+		DeviceID:  deviceID,
+		Timestamp: testTimestamp,
+	})))
+	require.Equal(t, deviceID, idHolder.Get())
+	require.Equal(t, deviceID, dataHandler.registration.DeviceID)
+	require.Equal(t, testTimestamp, dataHandler.registration.Timestamp)
+
+	require.Nil(t, device.HandleTelemetry(makeTestPushDeviceData(t, testTelemetryData{
+		Timestamp:   testTimestamp,
+		Temperature: 42.135,
+		Status:      "foo",
+	})))
+	require.Equal(t, testTimestamp, dataHandler.telemetry.Timestamp)
+	require.Equal(t, 42.135, dataHandler.telemetry.Temperature)
+	require.Equal(t, "foo", dataHandler.telemetry.Status)
+
+	require.Equal(t, 0, timeSynchronizer.callCount)
+}
+
+func TestPushDeviceHandleTelemetryNoRegistration(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+
+	device := NewPushDevice(
+		NewIDHolder(), &dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	err := device.HandleTelemetry(makeTestPushDeviceData(t, testTelemetryData{
+		Timestamp: 13,
+	}))
+	require.True(t, errors.Is(err, status.StatusInvalidState))
+	require.Equal(t, float64(0), dataHandler.telemetry.Timestamp)
+}
+
+func TestPushDeviceHandleRegistrationInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"invalid JSON", []byte("{")},
+		{"missed device ID", []byte(`{"timestamp":13}`)},
+		{"empty device ID", []byte(`{"timestamp":13,"device_id":""}`)},
+		{"invalid device ID", []byte(`{"timestamp":13,"device_id":13}`)},
+		{"missed timestamp", []byte(`{"device_id":"0xABCD"}`)},
+		{"invalid timestamp", []byte(`{"timestamp":"13","device_id":"0xABCD"}`)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dataHandler := testDataHandler{}
+			timeSynchronizer := testTimeSynchronizer{}
+
+			device := NewPushDevice(
+				NewIDHolder(), &dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+			require.NotNil(t, device.HandleRegistration(test.data))
+			require.Empty(t, dataHandler.registration.DeviceID)
+			require.Equal(t, 0, timeSynchronizer.callCount)
+		})
+	}
+}
+
+func TestPushDeviceHandleRegistrationDeviceIDChanged(t *testing.T) {
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+	idHolder := NewIDHolder()
+
+	device := NewPushDevice(idHolder, &dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	require.Nil(t, device.HandleRegistration(makeTestPushDeviceData(t, testRegistrationData{
+		DeviceID:  "0xABCD",
+		Timestamp: 13,
+	})))
+
+	require.NotNil(t, device.HandleRegistration(makeTestPushDeviceData(t, testRegistrationData{
+		DeviceID:  "0xDCBA",
+		Timestamp: 13,
+	})))
+	require.Equal(t, "0xABCD", idHolder.Get())
+	require.Equal(t, "0xABCD", dataHandler.registration.DeviceID)
+}
+
+func TestPushDeviceSynchronizeTime(t *testing.T) {
+	deviceID := "0xABCD"
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+
+	device := NewPushDevice(
+		NewIDHolder(), &dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	require.NotNil(t, device.HandleRegistration(makeTestPushDeviceData(t, testRegistrationData{
+		DeviceID:  deviceID,
+		Timestamp: -1,
+	})))
+	require.Equal(t, 1, timeSynchronizer.callCount)
+	require.Empty(t, dataHandler.registration.DeviceID)
+
+	require.NotNil(t, device.HandleTelemetry(makeTestPushDeviceData(t, testTelemetryData{
+		Timestamp: -1,
+	})))
+	require.Equal(t, 2, timeSynchronizer.callCount)
+
+	require.Nil(t, device.HandleTelemetry(makeTestPushDeviceData(t, testTelemetryData{
+		Timestamp: 13,
+	})))
+	require.Equal(t, 2, timeSynchronizer.callCount)
+	require.Equal(t, float64(13), dataHandler.telemetry.Timestamp)
+}
+
+func TestPushDeviceKnownDeviceID(t *testing.T) {
+	deviceID := "0xABCD"
+
+	dataHandler := testDataHandler{}
+	timeSynchronizer := testTimeSynchronizer{}
+
+	idHolder := NewIDHolder()
+	idHolder.Set(deviceID)
+
+	device := NewPushDevice(idHolder, &dataHandler, &timeSynchronizer, &BasicTimeVerifier{})
+
+	require.Nil(t, device.HandleTelemetry(makeTestPushDeviceData(t, testTelemetryData{
+		Timestamp: 13,
+	})))
+	require.Equal(t, float64(13), dataHandler.telemetry.Timestamp)
+
+	require.NotNil(t, device.HandleRegistration(makeTestPushDeviceData(t, testRegistrationData{
+		DeviceID:  "0xDCBA",
+		Timestamp: 13,
+	})))
+
+	err := device.HandleTelemetry(makeTestPushDeviceData(t, testTelemetryData{
+		Timestamp: -1,
+	}))
+	require.True(t, errors.Is(err, ErrInvalidTimestamp))
+}
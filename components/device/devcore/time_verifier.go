@@ -5,6 +5,11 @@
 
 package devcore
 
+import "errors"
+
+// ErrInvalidTimestamp is returned if the device UNIX timestamp isn't valid.
+var ErrInvalidTimestamp = errors.New("invalid timestamp")
+
 // TimeVerifier to verify the UNIX timestamp of the device.
 type TimeVerifier interface {
 	// VerifyTime returns true if the provided UNIX timestamp is valid.
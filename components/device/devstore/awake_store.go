@@ -22,8 +22,8 @@ func NewAwakeStore(a syssched.Awakener, s Store) *AwakeStore {
 }
 
 // Add adds the device and notifies the awakener.
-func (s *AwakeStore) Add(uri string, typ string, desc string) error {
-	err := s.store.Add(uri, typ, desc)
+func (s *AwakeStore) Add(uri string, typ string, desc string, opts DeviceOptions) error {
+	err := s.store.Add(uri, typ, desc, opts)
 	if err == nil {
 		s.awakener.Awake()
 	}
@@ -36,6 +36,16 @@ func (s *AwakeStore) Remove(uri string) error {
 	return s.store.Remove(uri)
 }
 
+// Update updates the device and notifies the awakener.
+func (s *AwakeStore) Update(uri string, newURI string, typ string, desc string) error {
+	err := s.store.Update(uri, newURI, typ, desc)
+	if err == nil {
+		s.awakener.Awake()
+	}
+
+	return err
+}
+
 // GetDesc returns descriptions for registered devices.
 func (s *AwakeStore) GetDesc() []StoreItem {
 	return s.store.GetDesc()
@@ -7,17 +7,24 @@ package devstore
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/url"
+	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/tendry-lab/device-hub/components/device/devcore"
 	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/mqtt/mqcore"
 	"github.com/tendry-lab/device-hub/components/status"
 	"github.com/tendry-lab/device-hub/components/storage/stcore"
 	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
 	"github.com/tendry-lab/device-hub/components/system/sysnet"
 	"github.com/tendry-lab/device-hub/components/system/syssched"
 )
@@ -30,6 +37,43 @@ type CacheStoreParams struct {
 
 		// FetchTimeout - how long to wait for the response from the device.
 		FetchTimeout time.Duration
+
+		// RegistrationInterval - how often to fetch the registration data.
+		//
+		// Remarks:
+		//  - Registration is fetched with the telemetry if zero.
+		//  - Registration is always fetched after the fetch failure.
+		//  - Can be overridden by the device profile.
+		RegistrationInterval time.Duration
+
+		// MaxBackoffInterval - maximum fetch interval after the consecutive failures,
+		// fetch interval isn't changed on failures if zero.
+		MaxBackoffInterval time.Duration
+
+		// StartJitter - maximum random delay before the first fetch.
+		StartJitter time.Duration
+
+		// Jitter - maximum random delay added to each fetch interval.
+		Jitter time.Duration
+
+		// BreakerThreshold - number of consecutive failures to open the circuit breaker,
+		// circuit breaker is disabled if zero.
+		//
+		// Remarks:
+		//  - Circuit breaker of the .local device is reset once its host is resolved
+		//    over mDNS.
+		BreakerThreshold int
+
+		// BreakerInterval - how often to fetch data while the circuit breaker is open.
+		BreakerInterval time.Duration
+
+		// PausedInterval - how often to fetch data while the device is paused, to
+		// detect that the device is active again.
+		//
+		// Remarks:
+		//  - Paused device isn't fetched until it's resumed if zero.
+		//  - Only the device data is fetched, the device streams aren't fetched.
+		PausedInterval time.Duration
 	}
 
 	TimeSync struct {
@@ -46,6 +90,51 @@ type CacheStoreParams struct {
 		// How often to perform the timestamp restoring procedure.
 		RestoreInterval time.Duration
 	}
+
+	MQTT struct {
+		// Timeout - how long to wait for the MQTT broker to acknowledge the operation.
+		Timeout time.Duration
+	}
+
+	// Profiles - device profiles keyed by the device type.
+	//
+	// Remarks:
+	//  - DefaultDeviceProfile() is used for all devices if nil.
+	Profiles *ProfileRegistry
+
+	// Metrics - registry to report the device processing metrics, not reported if nil.
+	Metrics *sysmetrics.Registry
+
+	// Scheduler - scheduler to run the device tasks on the shared pool of goroutines.
+	//
+	// Remarks:
+	//  - Each device task is run in the standalone goroutine if nil.
+	Scheduler *syssched.Scheduler
+
+	// Watchdog - watchdog to interrupt and report the stalled runs of the polling tasks.
+	//
+	// Remarks:
+	//  - Runs aren't limited if nil.
+	Watchdog *syssched.Watchdog
+
+	// StopTimeout - how long to wait for a single device task to stop.
+	//
+	// Remarks:
+	//  - Device tasks are waited forever if zero.
+	//  - Task which doesn't stop in time is left behind, so it doesn't block the store.
+	StopTimeout time.Duration
+
+	// Cipher - cipher to encrypt the persisted device credentials.
+	//
+	// Remarks:
+	//  - Devices with credentials can't be added if nil.
+	Cipher stcore.Cipher
+
+	Diagnostics struct {
+		// HistorySize - how many payloads, errors and time synchronizations to keep
+		// for each device, nothing is kept if zero.
+		HistorySize int
+	}
 }
 
 // CacheStore allows to cache information about the added devices in the persistent storage.
@@ -99,6 +188,9 @@ func NewCacheStore(
 }
 
 // SetAliveMonitor sets the device inactivity monitor.
+//
+// Remarks:
+//   - Should be called before Start().
 func (s *CacheStore) SetAliveMonitor(monitor AliveMonitor) {
 	s.aliveMonitor = monitor
 }
@@ -134,7 +226,14 @@ func (s *CacheStore) Stop() error {
 }
 
 // Add caches the device information in the persistent storage.
-func (s *CacheStore) Add(uri string, typ string, desc string) error {
+//
+// Remarks:
+//   - status.StatusInvalidArg is returned if the TLS settings are set for
+//     the non-HTTPS device, or can't be loaded.
+//   - status.StatusInvalidArg is returned if the credentials are set for
+//     the non-HTTP device, or are inconsistent.
+//   - Credentials are persisted encrypted, and aren't logged.
+func (s *CacheStore) Add(uri string, typ string, desc string, opts DeviceOptions) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -144,15 +243,14 @@ func (s *CacheStore) Add(uri string, typ string, desc string) error {
 
 	now := time.Now()
 
-	node, err := s.makeNode(uri, typ, desc, now)
+	node, err := s.makeNode(uri, typ, desc, opts, now)
 	if err != nil {
 		return err
 	}
 
-	item := StorageItem{
-		Desc:      desc,
-		Timestamp: now.Unix(),
-		Type:      typ,
+	item, err := s.makeStorageItem(typ, desc, opts, now)
+	if err != nil {
+		return err
 	}
 
 	buf, err := item.MarshalBinary()
@@ -200,6 +298,294 @@ func (s *CacheStore) Remove(uri string) error {
 	return nil
 }
 
+// Update changes the URI, type and description of the device associated with
+// the provided URI.
+//
+// Remarks:
+//   - Only the changed parts of the device are restarted: the data handler is
+//     rebuilt if only the type or description is changed, the device data fetching
+//     is restarted if the URI or the device profile is changed.
+//   - Device ID, restored UNIX time and diagnostics are kept, unless the ID
+//     of the push device is changed.
+//   - ErrDeviceExist is returned if the new URI is used by another device.
+func (s *CacheStore) Update(uri string, newURI string, typ string, desc string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, ok := s.nodes[uri]
+	if !ok {
+		return status.StatusNoData
+	}
+
+	if newURI != uri {
+		if _, ok := s.nodes[newURI]; ok {
+			return ErrDeviceExist
+		}
+	}
+
+	if newURI == uri && typ == node.typ && desc == node.desc {
+		return nil
+	}
+
+	prevItem, err := s.makeStorageItem(node.typ, node.desc, node.opts, node.createdAt)
+	if err != nil {
+		return err
+	}
+
+	item, err := s.makeStorageItem(typ, desc, node.opts, node.createdAt)
+	if err != nil {
+		return err
+	}
+
+	if err := s.persistItem(uri, newURI, item); err != nil {
+		return err
+	}
+
+	updatedNode, err := s.updateNode(node, newURI, typ, desc)
+	if err != nil {
+		if restoreErr := s.persistItem(newURI, uri, prevItem); restoreErr != nil {
+			syscore.LogErr.Printf("failed to restore device information: uri=%s err=%v",
+				uri, restoreErr)
+		}
+
+		return err
+	}
+
+	delete(s.nodes, uri)
+	s.nodes[newURI] = updatedNode
+
+	syscore.LogInf.Printf("device updated: uri=%s new_uri=%s type=%s desc=%s",
+		uri, newURI, typ, desc)
+
+	return nil
+}
+
+// GetDiagnostics returns the runtime diagnostics of the device.
+//
+// Parameters:
+//   - device - device URI or ID.
+func (s *CacheStore) GetDiagnostics(device string) (DeviceDiagnostics, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, node := range s.nodes {
+		if node.uri != device && node.holder.Get() != device {
+			continue
+		}
+
+		diag := node.diag.get()
+		diag.URI = node.uri
+		diag.ID = node.holder.Get()
+
+		if hostname := node.device.hostname; hostname != "" {
+			if addr, err := s.resolveStore.Lookup(hostname); err == nil {
+				diag.ResolvedAddr = addr.String()
+			}
+		}
+
+		return diag, nil
+	}
+
+	return DeviceDiagnostics{}, status.StatusNoData
+}
+
+// HandleResolve wakes up the failing devices once their host is resolved over mDNS.
+//
+// Remarks:
+//   - Backoff interval and circuit breaker of the device are reset, and the device
+//     data is fetched immediately.
+func (s *CacheStore) HandleResolve(hostname string, _ net.Addr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, node := range s.nodes {
+		if node.device.hostname != hostname || node.diag.getFailures() == 0 {
+			continue
+		}
+
+		node.device.awake()
+	}
+}
+
+// Probe fetches the registration, telemetry and UNIX time of the device once,
+// and validates them in the same way as for the added device.
+//
+// Remarks:
+//   - Only HTTP devices can be probed.
+//   - Device time isn't synchronized during probing.
+//   - TLS settings and credentials of the options are used for probing, but
+//     aren't persisted.
+func (s *CacheStore) Probe(
+	ctx context.Context,
+	uri string,
+	typ string,
+	opts DeviceOptions,
+) (ProbeReport, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ProbeReport{}, err
+	}
+
+	if parseDeviceType(u.Scheme) != deviceTypeHTTP {
+		return ProbeReport{}, status.StatusNotSupported
+	}
+
+	if u.Port() == "" {
+		return ProbeReport{}, fmt.Errorf("%w: HTTP port is missed", status.StatusInvalidArg)
+	}
+
+	if err := validateDeviceOptions(u, opts); err != nil {
+		return ProbeReport{}, err
+	}
+
+	stopper := s.newStopper()
+	defer func() {
+		if err := stopper.Stop(); err != nil {
+			syscore.LogErr.Printf("failed to stop probing: uri=%s err=%v", uri, err)
+		}
+	}()
+
+	profile := s.params.Profiles.Get(typ)
+
+	tlsConfig, err := s.makeTLSConfig(u, opts.TLS.merge(profile.TLS))
+	if err != nil {
+		return ProbeReport{}, err
+	}
+
+	diag := newDeviceDiagnostics(1)
+	idHolder := devcore.NewIDHolder()
+	client := s.makeHTTPClient(stopper, uri, "probe", u.Hostname(), tlsConfig)
+
+	if !opts.Credentials.IsZero() {
+		client.SetHeader(opts.Credentials.header())
+	}
+
+	device := devcore.NewPollDevice(
+		&diagnosticsFetcher{
+			diag: diag,
+			kind: diagnosticsKindRegistration,
+			fetcher: htcore.NewURLFetcher(
+				ctx,
+				client,
+				profile.HTTP.Registration.Method,
+				uri+profile.HTTP.Registration.Path,
+				s.params.HTTP.FetchTimeout,
+			),
+		},
+		&diagnosticsFetcher{
+			diag: diag,
+			kind: diagnosticsKindTelemetry,
+			fetcher: htcore.NewURLFetcher(
+				ctx,
+				client,
+				profile.HTTP.Telemetry.Method,
+				uri+profile.HTTP.Telemetry.Path,
+				s.params.HTTP.FetchTimeout,
+			),
+		},
+		idHolder,
+		probeDataHandler{},
+		devcore.FuncSynchronizer(func() error {
+			return fmt.Errorf("device time isn't synchronized during probing")
+		}),
+		s.makeTimeVerifier(),
+	)
+	device.SetDataSchema(profile.Schema)
+
+	report := ProbeReport{
+		URI:    uri,
+		Errors: []string{},
+	}
+
+	if err := device.Run(); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	if profile.HTTP.TimePath != "" {
+		drift, err := s.probeDrift(ctx, client, uri+profile.HTTP.TimePath)
+		if err != nil {
+			report.Errors = append(report.Errors,
+				fmt.Sprintf("failed to read device time: %v", err))
+		} else {
+			report.Drift = &drift
+		}
+	}
+
+	res := diag.get()
+
+	report.DeviceID = idHolder.Get()
+	report.RegistrationFields = probeFields(res.Registration)
+	report.TelemetryFields = probeFields(res.Telemetry)
+
+	return report, nil
+}
+
+func (s *CacheStore) probeDrift(
+	ctx context.Context,
+	client *htcore.HTTPClient,
+	timeURL string,
+) (int64, error) {
+	remoteTs, err := htcore.NewSystemClock(
+		ctx, client, timeURL, s.params.HTTP.FetchTimeout).GetTimestamp()
+	if err != nil {
+		return 0, err
+	}
+
+	localTs, err := s.localClock.GetTimestamp()
+	if err != nil {
+		return 0, err
+	}
+
+	return localTs - remoteTs, nil
+}
+
+// Pause pauses the data fetching for the device associated with the provided URI.
+//
+// Remarks:
+//   - Only HTTP devices are polled, data pushed by other devices is still handled.
+//   - Paused device is still fetched once per HTTP.PausedInterval, if it's set.
+func (s *CacheStore) Pause(uri string) error {
+	return s.setPaused(uri, true)
+}
+
+// Resume resumes the data fetching for the device associated with the provided URI.
+func (s *CacheStore) Resume(uri string) error {
+	return s.setPaused(uri, false)
+}
+
+func (s *CacheStore) setPaused(uri string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	node, ok := s.nodes[uri]
+	if !ok {
+		return status.StatusNoData
+	}
+
+	if node.paused.Swap(paused) != paused {
+		syscore.LogInf.Printf("device data fetching changed: uri=%s paused=%v", uri, paused)
+	}
+
+	return nil
+}
+
+// GetPushHandler returns the handler for the data pushed by the device.
+//
+// Remarks:
+//   - Only devices added with the push:// URI are resolved.
+func (s *CacheStore) GetPushHandler(deviceID string) (PushHandler, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, node := range s.nodes {
+		if node.device.pushHandler != nil && node.holder.Get() == deviceID {
+			return node.device.pushHandler, nil
+		}
+	}
+
+	return nil, status.StatusNoData
+}
+
 // GetDesc returns descriptions for registered devices.
 func (s *CacheStore) GetDesc() []StoreItem {
 	s.mu.Lock()
@@ -208,13 +594,25 @@ func (s *CacheStore) GetDesc() []StoreItem {
 	var items []StoreItem
 
 	for _, node := range s.nodes {
-		items = append(items, StoreItem{
+		item := StoreItem{
 			URI:       node.uri,
 			Type:      node.typ,
 			Desc:      node.desc,
 			ID:        node.holder.Get(),
-			CreatedAt: node.createdAt,
-		})
+			CreatedAt: node.createdAt.Format(time.RFC1123),
+		}
+
+		if !node.opts.TLS.IsZero() {
+			deviceTLS := node.opts.TLS
+			item.TLS = &deviceTLS
+		}
+
+		if !node.opts.Credentials.IsZero() {
+			credentials := node.opts.Credentials.redact()
+			item.Credentials = &credentials
+		}
+
+		items = append(items, item)
 	}
 
 	return items
@@ -256,7 +654,26 @@ func (s *CacheStore) restoreNode(uri string, buf []byte) error {
 		return err
 	}
 
-	node, err := s.makeNode(uri, item.Type, item.Desc, time.Unix(item.Timestamp, 0))
+	opts := DeviceOptions{
+		TLS: DeviceTLS{
+			CAPath:      item.TLSCAPath,
+			Fingerprint: item.TLSFingerprint,
+			CertPath:    item.TLSCertPath,
+			KeyPath:     item.TLSKeyPath,
+			ServerName:  item.TLSServerName,
+		},
+	}
+
+	if len(item.Credentials) != 0 {
+		credentials, err := s.decryptCredentials(item.Credentials)
+		if err != nil {
+			return err
+		}
+
+		opts.Credentials = credentials
+	}
+
+	node, err := s.makeNode(uri, item.Type, item.Desc, opts, time.Unix(item.Timestamp, 0))
 	if err != nil {
 		return err
 	}
@@ -272,152 +689,651 @@ func (s *CacheStore) makeNode(
 	uri string,
 	typ string,
 	desc string,
-	now time.Time,
+	opts DeviceOptions,
+	createdAt time.Time,
 ) (*storeNode, error) {
 	u, err := url.Parse(uri)
 	if err != nil {
+		return nil, fmt.Errorf("%w: %v", status.StatusInvalidArg, err)
+	}
+
+	if err := validateDeviceOptions(u, opts); err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(s.ctx)
+
+	node := &storeNode{
+		uri:        uri,
+		typ:        typ,
+		desc:       desc,
+		opts:       opts,
+		createdAt:  createdAt,
+		holder:     devcore.NewIDHolder(),
+		paused:     &atomic.Bool{},
+		diag:       newDeviceDiagnostics(s.params.Diagnostics.HistorySize),
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+		stopper:    s.newStopper(),
+		starter:    s.newStarter(cancelFunc),
+	}
+
+	node.clockRestorer = s.makeClockRestorer(
+		ctx, node.starter, node.stopper, node.holder, uri)
+	node.dataHandler = newDataHandler(node.clockRestorer, s.handlerBuilder, typ, desc)
+	node.errorHandler = &logErrorHandler{uri: uri, typ: typ, desc: desc, diag: node.diag}
+
+	device, err := s.makeNodeDevice(node, u, uri, typ, desc)
+	if err != nil {
+		cancelFunc()
+
 		return nil, err
 	}
 
-	deviceType := parseDeviceType(u.Scheme)
+	node.device = device
+
+	return node, nil
+}
 
-	switch deviceType {
+// makeNodeDevice builds the part of the node which depends on the device URI
+// and profile.
+//
+// Remarks:
+//   - ID holder, clock restorer and diagnostics of the node are reused.
+func (s *CacheStore) makeNodeDevice(
+	node *storeNode,
+	u *url.URL,
+	uri string,
+	typ string,
+	desc string,
+) (*storeNodeDevice, error) {
+	switch parseDeviceType(u.Scheme) {
 	case deviceTypeHTTP:
-		return s.makeNodeHTTP(u, uri, typ, desc, now)
+		return s.makeNodeHTTP(node, u, uri, typ, desc)
+	case deviceTypeMQTT:
+		return s.makeNodeMQTT(node, u, uri, typ)
+	case deviceTypePush:
+		return s.makeNodePush(node, u, uri, typ)
 	default:
 		return nil, status.StatusNotSupported
 	}
 }
 
 func (s *CacheStore) makeNodeHTTP(
+	node *storeNode,
 	u *url.URL,
 	uri string,
 	typ string,
 	desc string,
-	now time.Time,
-) (*storeNode, error) {
+) (*storeNodeDevice, error) {
 	if u.Port() == "" {
-		return nil, fmt.Errorf("HTTP port is missed")
+		return nil, fmt.Errorf("%w: HTTP port is missed", status.StatusInvalidArg)
 	}
 
-	ctx, cancelFunc := context.WithCancel(s.ctx)
+	profile := s.params.Profiles.Get(typ)
 
-	stopper := &syssched.FanoutStopper{}
-	starter := &syssched.FanoutStarter{}
+	tlsConfig, err := s.makeTLSConfig(u, node.opts.TLS.merge(profile.TLS))
+	if err != nil {
+		return nil, err
+	}
 
-	idHolder := devcore.NewIDHolder()
+	device := s.newStoreNodeDevice(node.ctx)
+	device.hostname = s.getResolvedHostname(uri, u.Hostname())
 
-	clockReader := newSystemClockReader(idHolder, s.readerBuilder)
-	clockRestorer := stcore.NewSystemClockRestorer(ctx, clockReader)
+	client := s.makeHTTPClient(device.stopper, uri, desc, u.Hostname(), tlsConfig)
 
-	clockRestorerRunner := syssched.NewAsyncTaskRunner(
-		ctx,
-		clockRestorer,
-		clockRestorer,
-		syssched.AsyncTaskRunnerParams{
-			UpdateInterval: s.params.TimeSync.RestoreInterval,
-			ExitOnSuccess:  true,
-		},
-	)
+	if !node.opts.Credentials.IsZero() {
+		client.SetHeader(node.opts.Credentials.header())
+	}
 
-	starter.Add(clockRestorerRunner)
-	stopper.Add(uri+"-clock-restorer", clockRestorerRunner)
+	deviceTask := s.watchTask(device.ctx, uri+"-device-http",
+		func(ctx context.Context) syssched.Task {
+			return s.newHTTPDevice(
+				ctx,
+				client,
+				profile,
+				node.holder,
+				node.dataHandler,
+				node.diag,
+				node.clockRestorer,
+				uri,
+			)
+		})
 
-	deviceRunner := syssched.NewAsyncTaskRunner(
-		ctx,
-		s.newHTTPDevice(
-			ctx,
-			stopper,
-			idHolder,
-			newDataHandler(clockRestorer, s.handlerBuilder),
-			s.localClock,
-			clockRestorer,
-			uri,
-			desc,
-			u.Hostname(),
-		),
-		&logErrorHandler{uri: uri, typ: typ, desc: desc},
-		syssched.AsyncTaskRunnerParams{
-			UpdateInterval: s.params.HTTP.FetchInterval,
-		},
+	deviceRunner := s.newTaskRunner(
+		device.ctx,
+		newPausableTask(node.paused, deviceTask, s.params.HTTP.PausedInterval),
+		node.errorHandler,
+		s.makeHTTPRunnerParams(s.params.HTTP.FetchInterval),
 	)
+	deviceRunner.SetMetrics(s.params.Metrics, "device-http")
 
-	starter.Add(deviceRunner)
-	stopper.Add(uri+"-device-http", deviceRunner)
+	device.starter.Add(deviceRunner)
+	device.stopper.Add(uri+"-device-http", deviceRunner)
+	device.awakeners = append(device.awakeners, deviceRunner)
 
-	return &storeNode{
-		uri:        uri,
-		typ:        typ,
-		desc:       desc,
-		createdAt:  now.Format(time.RFC1123),
-		holder:     idHolder,
-		cancelFunc: cancelFunc,
-		stopper:    stopper,
-		starter:    starter,
-	}, nil
+	for _, stream := range profile.HTTP.Streams {
+		updateInterval := stream.Interval
+		if updateInterval == 0 {
+			updateInterval = s.params.HTTP.FetchInterval
+		}
+
+		streamTask := s.watchTask(device.ctx, uri+"-stream-"+stream.Name,
+			func(ctx context.Context) syssched.Task {
+				return s.newHTTPStream(
+					ctx,
+					client,
+					profile,
+					stream,
+					node.holder,
+					node.dataHandler,
+					uri,
+				)
+			})
+
+		streamRunner := s.newTaskRunner(
+			device.ctx,
+			newPausableTask(node.paused, streamTask, 0),
+			node.errorHandler,
+			s.makeHTTPRunnerParams(updateInterval),
+		)
+		streamRunner.SetMetrics(s.params.Metrics, "device-stream")
+
+		device.starter.Add(streamRunner)
+		device.stopper.Add(uri+"-stream-"+stream.Name, streamRunner)
+		device.awakeners = append(device.awakeners, streamRunner)
+	}
+
+	return device, nil
 }
 
 func (s *CacheStore) newHTTPDevice(
 	ctx context.Context,
-	stopper *syssched.FanoutStopper,
+	client *htcore.HTTPClient,
+	profile DeviceProfile,
 	idHolder *devcore.IDHolder,
 	dataHandler devcore.DataHandler,
-	localClock syscore.SystemClock,
+	diag *deviceDiagnostics,
 	remoteLastClock syscore.SystemClock,
 	uri string,
-	desc string,
-	hostname string,
 ) syssched.Task {
 	var clockSynchronizer devcore.TimeSynchronizer
-	if s.params.TimeSync.Disable {
-		clockSynchronizer = devcore.FuncSynchronizer(func() error {
-			return status.StatusNotSupported
-		})
+	if s.params.TimeSync.Disable || profile.HTTP.TimePath == "" {
+		clockSynchronizer = newDisabledTimeSynchronizer()
 	} else {
 		remoteCurrClock := htcore.NewSystemClock(
 			ctx,
-			s.makeHTTPClient(stopper, uri, desc, hostname),
-			uri+"/system/time",
+			client,
+			uri+profile.HTTP.TimePath,
 			s.params.HTTP.FetchTimeout,
 		)
 
-		clockSynchronizer = syscore.NewSystemClockSynchronizer(
-			localClock, remoteLastClock, remoteCurrClock)
-	}
+		synchronizer := syscore.NewSystemClockSynchronizer(
+			s.localClock, remoteLastClock, remoteCurrClock)
+		synchronizer.SetMetrics(s.params.Metrics)
 
-	var clockVerifier devcore.TimeVerifier
-	if maxDriftInterval := s.params.TimeSync.MaxDriftInterval; maxDriftInterval == 0 {
-		clockVerifier = &devcore.BasicTimeVerifier{}
-	} else {
-		clockVerifier = devcore.NewDriftTimeVerifier(localClock, maxDriftInterval)
+		clockSynchronizer = &diagnosticsTimeSynchronizer{
+			diag:         diag,
+			synchronizer: synchronizer,
+		}
 	}
 
 	task := devcore.NewPollDevice(
-		htcore.NewURLFetcher(
-			ctx,
-			s.makeHTTPClient(stopper, uri, desc, hostname),
-			uri+"/registration",
-			s.params.HTTP.FetchTimeout,
-		),
-		htcore.NewURLFetcher(
-			ctx,
-			s.makeHTTPClient(stopper, uri, desc, hostname),
-			uri+"/telemetry",
-			s.params.HTTP.FetchTimeout,
+		&diagnosticsFetcher{
+			diag: diag,
+			kind: diagnosticsKindRegistration,
+			fetcher: htcore.NewURLFetcher(
+				ctx,
+				client,
+				profile.HTTP.Registration.Method,
+				uri+profile.HTTP.Registration.Path,
+				s.params.HTTP.FetchTimeout,
+			),
+		},
+		&diagnosticsFetcher{
+			diag: diag,
+			kind: diagnosticsKindTelemetry,
+			fetcher: htcore.NewURLFetcher(
+				ctx,
+				client,
+				profile.HTTP.Telemetry.Method,
+				uri+profile.HTTP.Telemetry.Path,
+				s.params.HTTP.FetchTimeout,
+			),
+		},
+		idHolder,
+		dataHandler,
+		clockSynchronizer,
+		s.makeDiagnosticsTimeVerifier(diag),
+	)
+	task.SetDataSchema(profile.Schema)
+	task.SetMetrics(s.params.Metrics)
+
+	registrationInterval := profile.HTTP.RegistrationInterval
+	if registrationInterval == 0 {
+		registrationInterval = s.params.HTTP.RegistrationInterval
+	}
+	task.SetRegistrationInterval(&syscore.LocalMonotonicClock{}, registrationInterval)
+
+	return syssched.NewTaskAliveNotifier(&diagnosticsTask{
+		diag: diag,
+		task: task,
+	}, &cacheStoreAliveNotifier{
+		store: s,
+		uri:   uri,
+	})
+}
+
+func (s *CacheStore) newHTTPStream(
+	ctx context.Context,
+	client *htcore.HTTPClient,
+	profile DeviceProfile,
+	stream HTTPStream,
+	idHolder *devcore.IDHolder,
+	dataHandler devcore.DataHandler,
+	uri string,
+) syssched.Task {
+	task := devcore.NewPollStream(
+		htcore.NewURLFetcher(
+			ctx,
+			client,
+			stream.Method,
+			uri+stream.Path,
+			s.params.HTTP.FetchTimeout,
 		),
 		idHolder,
 		dataHandler,
+		s.makeTimeVerifier(),
+		stream.GetMeasurement(),
+	)
+	task.SetDataSchema(profile.Schema)
+
+	return syssched.NewTaskAliveNotifier(task, &cacheStoreAliveNotifier{
+		store: s,
+		uri:   uri,
+	})
+}
+
+func (s *CacheStore) makeNodeMQTT(
+	node *storeNode,
+	u *url.URL,
+	uri string,
+	typ string,
+) (*storeNodeDevice, error) {
+	if u.Port() == "" {
+		return nil, fmt.Errorf("%w: MQTT port is missed", status.StatusInvalidArg)
+	}
+
+	topicPrefix := strings.Trim(u.Path, "/")
+	if topicPrefix == "" {
+		return nil, fmt.Errorf("%w: MQTT topic prefix is missed", status.StatusInvalidArg)
+	}
+
+	client, err := mqcore.NewClient(mqcore.ClientParams{
+		BrokerURL: "tcp://" + u.Host,
+		Timeout:   s.params.MQTT.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var clockSynchronizer devcore.TimeSynchronizer
+	if s.params.TimeSync.Disable {
+		clockSynchronizer = newDisabledTimeSynchronizer()
+	} else {
+		synchronizer := syscore.NewSystemClockSynchronizer(
+			s.localClock,
+			node.clockRestorer,
+			mqcore.NewSystemClock(client, topicPrefix+"/system/time"),
+		)
+		synchronizer.SetMetrics(s.params.Metrics)
+
+		clockSynchronizer = &diagnosticsTimeSynchronizer{
+			diag:         node.diag,
+			synchronizer: synchronizer,
+		}
+	}
+
+	pushDevice := devcore.NewPushDevice(
+		node.holder,
+		node.dataHandler,
 		clockSynchronizer,
-		clockVerifier,
+		s.makeDiagnosticsTimeVerifier(node.diag),
+	)
+	pushDevice.SetDataSchema(s.params.Profiles.Get(typ).Schema)
+
+	handler := &mqttDeviceHandler{
+		handler: &pushDeviceHandler{
+			device:   pushDevice,
+			notifier: &cacheStoreAliveNotifier{store: s, uri: uri},
+			diag:     node.diag,
+		},
+		errorHandler:      node.errorHandler,
+		registrationTopic: topicPrefix + "/registration",
+		telemetryTopic:    topicPrefix + "/telemetry",
+	}
+
+	for _, topic := range []string{handler.registrationTopic, handler.telemetryTopic} {
+		if err := client.Subscribe(topic, handler); err != nil {
+			if err := client.Stop(); err != nil {
+				syscore.LogErr.Printf("failed to stop MQTT client: uri=%s err=%v", uri, err)
+			}
+
+			return nil, err
+		}
+	}
+
+	device := s.newStoreNodeDevice(node.ctx)
+
+	device.starter.Add(client)
+	device.stopper.Add(uri+"-device-mqtt", client)
+
+	return device, nil
+}
+
+func (s *CacheStore) makeNodePush(
+	node *storeNode,
+	u *url.URL,
+	uri string,
+	typ string,
+) (*storeNodeDevice, error) {
+	deviceID := u.Host
+	if deviceID == "" {
+		return nil, fmt.Errorf("%w: push device ID is missed", status.StatusInvalidArg)
+	}
+
+	if u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return nil, fmt.Errorf("%w: push device URI should contain only device ID",
+			status.StatusInvalidArg)
+	}
+
+	node.holder.Set(deviceID)
+
+	// The device time can't be set by the hub, the device is expected to correct
+	// its time from the hub response.
+	pushDevice := devcore.NewPushDevice(
+		node.holder,
+		node.dataHandler,
+		newDisabledTimeSynchronizer(),
+		s.makeDiagnosticsTimeVerifier(node.diag),
+	)
+	pushDevice.SetDataSchema(s.params.Profiles.Get(typ).Schema)
+
+	device := s.newStoreNodeDevice(node.ctx)
+	device.pushHandler = &pushDeviceHandler{
+		device:   pushDevice,
+		notifier: &cacheStoreAliveNotifier{store: s, uri: uri},
+		diag:     node.diag,
+	}
+
+	return device, nil
+}
+
+func (s *CacheStore) updateNode(
+	node *storeNode,
+	uri string,
+	typ string,
+	desc string,
+) (*storeNode, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", status.StatusInvalidArg, err)
+	}
+
+	// Push device is identified by its URI.
+	if uri != node.uri && (isPushDevice(uri) || isPushDevice(node.uri)) {
+		return s.replaceNode(node, uri, typ, desc)
+	}
+
+	if uri == node.uri &&
+		reflect.DeepEqual(s.params.Profiles.Get(typ), s.params.Profiles.Get(node.typ)) {
+		node.update(uri, typ, desc)
+
+		return node, nil
+	}
+
+	device, err := s.makeNodeDevice(node, u, uri, typ, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := node.device.stop(); err != nil {
+		return nil, fmt.Errorf("failed to stop device: uri=%s err=%v", node.uri, err)
+	}
+
+	node.update(uri, typ, desc)
+	node.device = device
+
+	if err := device.start(); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// replaceNode replaces the node with the new one, since the device ID is changed.
+func (s *CacheStore) replaceNode(
+	node *storeNode,
+	uri string,
+	typ string,
+	desc string,
+) (*storeNode, error) {
+	newNode, err := s.makeNode(uri, typ, desc, node.opts, node.createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	newNode.paused.Store(node.paused.Load())
+
+	if err := node.stop(); err != nil {
+		newNode.cancelFunc()
+
+		return nil, fmt.Errorf("failed to stop device: uri=%s err=%v", node.uri, err)
+	}
+
+	if err := newNode.start(); err != nil {
+		return nil, err
+	}
+
+	return newNode, nil
+}
+
+func (s *CacheStore) makeStorageItem(
+	typ string,
+	desc string,
+	opts DeviceOptions,
+	createdAt time.Time,
+) (StorageItem, error) {
+	item := StorageItem{
+		Desc:           desc,
+		Timestamp:      createdAt.Unix(),
+		Type:           typ,
+		TLSCAPath:      opts.TLS.CAPath,
+		TLSFingerprint: opts.TLS.Fingerprint,
+		TLSCertPath:    opts.TLS.CertPath,
+		TLSKeyPath:     opts.TLS.KeyPath,
+		TLSServerName:  opts.TLS.ServerName,
+	}
+
+	if !opts.Credentials.IsZero() {
+		buf, err := s.encryptCredentials(opts.Credentials)
+		if err != nil {
+			return StorageItem{}, err
+		}
+
+		item.Credentials = buf
+	}
+
+	return item, nil
+}
+
+func (s *CacheStore) encryptCredentials(credentials DeviceCredentials) ([]byte, error) {
+	if s.params.Cipher == nil {
+		return nil, fmt.Errorf("%w: credentials can't be encrypted, cipher isn't configured",
+			status.StatusInvalidState)
+	}
+
+	buf, err := json.Marshal(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.params.Cipher.Encrypt(buf)
+}
+
+func (s *CacheStore) decryptCredentials(data []byte) (DeviceCredentials, error) {
+	if s.params.Cipher == nil {
+		return DeviceCredentials{}, fmt.Errorf(
+			"%w: credentials can't be decrypted, cipher isn't configured",
+			status.StatusInvalidState)
+	}
+
+	buf, err := s.params.Cipher.Decrypt(data)
+	if err != nil {
+		return DeviceCredentials{}, fmt.Errorf("failed to decrypt credentials: %w", err)
+	}
+
+	var credentials DeviceCredentials
+	if err := json.Unmarshal(buf, &credentials); err != nil {
+		return DeviceCredentials{}, err
+	}
+
+	return credentials, nil
+}
+
+// persistItem persists the device information under the new URI, and removes
+// the information persisted under the previous URI.
+func (s *CacheStore) persistItem(uri string, newURI string, item StorageItem) error {
+	buf, err := item.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Write(newURI, buf); err != nil {
+		return fmt.Errorf("failed to persist device information: uri=%s err=%v", newURI, err)
+	}
+
+	if newURI != uri {
+		if err := s.db.Remove(uri); err != nil {
+			return fmt.Errorf("failed to remove device information: uri=%s err=%v", uri, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *CacheStore) makeClockRestorer(
+	ctx context.Context,
+	starter *syssched.FanoutStarter,
+	stopper *syssched.FanoutStopper,
+	idHolder *devcore.IDHolder,
+	uri string,
+) *stcore.SystemClockRestorer {
+	clockReader := newSystemClockReader(idHolder, s.readerBuilder)
+	clockRestorer := stcore.NewSystemClockRestorer(ctx, clockReader)
+
+	clockRestorerRunner := s.newTaskRunner(
+		ctx,
+		clockRestorer,
+		clockRestorer,
+		syssched.AsyncTaskRunnerParams{
+			UpdateInterval: s.params.TimeSync.RestoreInterval,
+			ExitOnSuccess:  true,
+		},
 	)
+	clockRestorerRunner.SetMetrics(s.params.Metrics, "clock-restorer")
+
+	starter.Add(clockRestorerRunner)
+	stopper.Add(uri+"-clock-restorer", clockRestorerRunner)
 
-	if s.aliveMonitor != nil {
-		notifier := s.aliveMonitor.Monitor(uri)
+	return clockRestorer
+}
+
+func (s *CacheStore) makeHTTPRunnerParams(
+	updateInterval time.Duration,
+) syssched.AsyncTaskRunnerParams {
+	return syssched.AsyncTaskRunnerParams{
+		UpdateInterval:     updateInterval,
+		MaxBackoffInterval: s.params.HTTP.MaxBackoffInterval,
+		StartJitter:        s.params.HTTP.StartJitter,
+		Jitter:             s.params.HTTP.Jitter,
+		BreakerThreshold:   s.params.HTTP.BreakerThreshold,
+		BreakerInterval:    s.params.HTTP.BreakerInterval,
+	}
+}
+
+// newStopper returns the stopper which doesn't wait for the device task forever,
+// since the device tasks are stopped while the store is locked.
+func (s *CacheStore) newStopper() *syssched.FanoutStopper {
+	stopper := &syssched.FanoutStopper{}
+	stopper.SetTimeout(s.params.StopTimeout)
+
+	return stopper
+}
+
+// newStarter returns the starter which cancels the device context and stops the
+// started device tasks if any task fails to start.
+func (s *CacheStore) newStarter(cancel context.CancelFunc) *syssched.FanoutStarter {
+	starter := &syssched.FanoutStarter{}
+	starter.SetCancel(cancel)
+	starter.SetStopTimeout(s.params.StopTimeout)
+
+	return starter
+}
 
-		return syssched.NewTaskAliveNotifier(task, notifier)
+// newTaskRunner returns the runner to run the device task periodically.
+func (s *CacheStore) newTaskRunner(
+	ctx context.Context,
+	task syssched.Task,
+	handler syssched.ErrorHandler,
+	params syssched.AsyncTaskRunnerParams,
+) syssched.TaskRunner {
+	if s.params.Scheduler != nil {
+		return s.params.Scheduler.NewRunner(ctx, task, handler, params)
 	}
 
-	return task
+	return syssched.NewAsyncTaskRunner(ctx, task, handler, params)
+}
+
+// watchTask builds the device task, which is run with the deadline if the watchdog
+// is configured.
+func (s *CacheStore) watchTask(
+	ctx context.Context,
+	name string,
+	build func(ctx context.Context) syssched.Task,
+) syssched.Task {
+	if s.params.Watchdog != nil {
+		return s.params.Watchdog.Watch(ctx, name, build)
+	}
+
+	return build(ctx)
+}
+
+func (s *CacheStore) makeTimeVerifier() devcore.TimeVerifier {
+	if maxDriftInterval := s.params.TimeSync.MaxDriftInterval; maxDriftInterval != 0 {
+		return devcore.NewDriftTimeVerifier(s.localClock, maxDriftInterval)
+	}
+
+	return &devcore.BasicTimeVerifier{}
+}
+
+func (s *CacheStore) makeDiagnosticsTimeVerifier(
+	diag *deviceDiagnostics,
+) devcore.TimeVerifier {
+	return &diagnosticsTimeVerifier{
+		diag:     diag,
+		clock:    s.localClock,
+		verifier: s.makeTimeVerifier(),
+	}
+}
+
+// getResolvedHostname returns the hostname resolved over mDNS, if any.
+func (*CacheStore) getResolvedHostname(uri string, hostname string) string {
+	if !strings.Contains(uri, ".local") {
+		return ""
+	}
+
+	return hostname
 }
 
 func (s *CacheStore) makeHTTPClient(
@@ -425,8 +1341,13 @@ func (s *CacheStore) makeHTTPClient(
 	uri string,
 	desc string,
 	hostname string,
+	tlsConfig *tls.Config,
 ) *htcore.HTTPClient {
 	if !strings.Contains(uri, ".local") {
+		if tlsConfig != nil {
+			return htcore.NewTLSClient(tlsConfig)
+		}
+
 		return htcore.NewDefaultClient()
 	}
 
@@ -438,7 +1359,91 @@ func (s *CacheStore) makeHTTPClient(
 		return nil
 	}))
 
-	return htcore.NewResolveClient(s.resolveStore)
+	return htcore.NewResolveClient(s.resolveStore, tlsConfig)
+}
+
+// makeTLSConfig returns the TLS configuration for the https:// device, nil for
+// the plain HTTP device.
+//
+// Remarks:
+//   - Device hostname is verified by default, even if the device address is
+//     resolved over mDNS.
+func (*CacheStore) makeTLSConfig(u *url.URL, deviceTLS DeviceTLS) (*tls.Config, error) {
+	if u.Scheme != "https" {
+		return nil, nil
+	}
+
+	serverName := deviceTLS.ServerName
+	if serverName == "" {
+		serverName = u.Hostname()
+	}
+
+	return htcore.NewClientTLSConfig(htcore.ClientTLSParams{
+		CAPath:      deviceTLS.CAPath,
+		Fingerprint: deviceTLS.Fingerprint,
+		CertPath:    deviceTLS.CertPath,
+		KeyPath:     deviceTLS.KeyPath,
+		ServerName:  serverName,
+	})
+}
+
+// cacheStoreAliveNotifier resolves the alive monitor on each notification, since
+// the nodes restored during the store initialization are built before the monitor is set.
+type cacheStoreAliveNotifier struct {
+	store *CacheStore
+	uri   string
+}
+
+func (n *cacheStoreAliveNotifier) NotifyAlive() {
+	if n.store.aliveMonitor != nil {
+		n.store.aliveMonitor.Monitor(n.uri).NotifyAlive()
+	}
+}
+
+// pausableTask skips the task runs while the device is paused.
+//
+// Remarks:
+//   - Task is run once per interval while the device is paused, so the device
+//     can be resumed once it's active again. Task isn't run if interval is zero.
+type pausableTask struct {
+	clock    syscore.MonotonicClock
+	paused   *atomic.Bool
+	task     syssched.Task
+	interval time.Duration
+	lastRun  time.Time
+}
+
+func newPausableTask(
+	paused *atomic.Bool,
+	task syssched.Task,
+	interval time.Duration,
+) *pausableTask {
+	return &pausableTask{
+		clock:    &syscore.LocalMonotonicClock{},
+		paused:   paused,
+		task:     task,
+		interval: interval,
+	}
+}
+
+func (t *pausableTask) Run() error {
+	now := t.clock.Now()
+
+	if t.paused.Load() {
+		if t.interval <= 0 || now.Sub(t.lastRun) < t.interval {
+			return nil
+		}
+	}
+
+	t.lastRun = now
+
+	return t.task.Run()
+}
+
+func newDisabledTimeSynchronizer() devcore.TimeSynchronizer {
+	return devcore.FuncSynchronizer(func() error {
+		return status.StatusNotSupported
+	})
 }
 
 type deviceType int
@@ -446,33 +1451,135 @@ type deviceType int
 const (
 	deviceTypeUnsupported deviceType = iota
 	deviceTypeHTTP
+	deviceTypeMQTT
+	deviceTypePush
 )
 
+// validateDeviceOptions returns status.StatusInvalidArg if the options can't be
+// applied to the device.
+func validateDeviceOptions(u *url.URL, opts DeviceOptions) error {
+	if !opts.TLS.IsZero() && u.Scheme != "https" {
+		return fmt.Errorf("%w: TLS settings require https:// device URI",
+			status.StatusInvalidArg)
+	}
+
+	if !opts.Credentials.IsZero() {
+		if parseDeviceType(u.Scheme) != deviceTypeHTTP {
+			return fmt.Errorf("%w: credentials require HTTP device",
+				status.StatusInvalidArg)
+		}
+
+		if err := opts.Credentials.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isPushDevice(uri string) bool {
+	u, err := url.Parse(uri)
+
+	return err == nil && parseDeviceType(u.Scheme) == deviceTypePush
+}
+
 func parseDeviceType(scheme string) deviceType {
 	if scheme == "http" || scheme == "https" {
 		return deviceTypeHTTP
 	}
 
+	if scheme == "mqtt" {
+		return deviceTypeMQTT
+	}
+
+	if scheme == "push" {
+		return deviceTypePush
+	}
+
 	return deviceTypeUnsupported
 }
 
 type storeNode struct {
-	uri        string
-	typ        string
-	desc       string
-	createdAt  string
-	holder     *devcore.IDHolder
-	cancelFunc context.CancelFunc
-	stopper    *syssched.FanoutStopper
-	starter    *syssched.FanoutStarter
+	uri           string
+	typ           string
+	desc          string
+	opts          DeviceOptions
+	createdAt     time.Time
+	holder        *devcore.IDHolder
+	paused        *atomic.Bool
+	diag          *deviceDiagnostics
+	clockRestorer *stcore.SystemClockRestorer
+	dataHandler   *dataHandler
+	errorHandler  *logErrorHandler
+	ctx           context.Context
+	cancelFunc    context.CancelFunc
+	stopper       *syssched.FanoutStopper
+	starter       *syssched.FanoutStarter
+	device        *storeNodeDevice
 }
 
 func (s *storeNode) start() error {
-	return s.starter.Start()
+	if err := s.starter.Start(); err != nil {
+		return err
+	}
+
+	return s.device.start()
+}
+
+func (s *storeNode) update(uri string, typ string, desc string) {
+	s.uri = uri
+	s.typ = typ
+	s.desc = desc
+
+	s.dataHandler.update(typ, desc)
+	s.errorHandler.update(uri, typ, desc)
 }
 
 func (s *storeNode) stop() error {
+	if err := s.device.stop(); err != nil {
+		return err
+	}
+
 	s.cancelFunc()
 
 	return s.stopper.Stop()
 }
+
+// storeNodeDevice is the part of the node which is rebuilt when the device URI
+// or profile is changed.
+type storeNodeDevice struct {
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+	stopper     *syssched.FanoutStopper
+	starter     *syssched.FanoutStarter
+	hostname    string
+	pushHandler PushHandler
+	awakeners   []syssched.Awakener
+}
+
+func (s *CacheStore) newStoreNodeDevice(ctx context.Context) *storeNodeDevice {
+	ctx, cancelFunc := context.WithCancel(ctx)
+
+	return &storeNodeDevice{
+		ctx:        ctx,
+		cancelFunc: cancelFunc,
+		stopper:    s.newStopper(),
+		starter:    s.newStarter(cancelFunc),
+	}
+}
+
+func (d *storeNodeDevice) awake() {
+	for _, awakener := range d.awakeners {
+		awakener.Awake()
+	}
+}
+
+func (d *storeNodeDevice) start() error {
+	return d.starter.Start()
+}
+
+func (d *storeNodeDevice) stop() error {
+	d.cancelFunc()
+
+	return d.stopper.Stop()
+}
@@ -7,21 +7,29 @@ package devstore
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"maps"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/internal/mqtest"
+	"github.com/tendry-lab/device-hub/components/mqtt/mqcore"
 	"github.com/tendry-lab/device-hub/components/status"
 	"github.com/tendry-lab/device-hub/components/storage/stcore"
 	"github.com/tendry-lab/device-hub/components/system/syscore"
 	"github.com/tendry-lab/device-hub/components/system/sysnet"
+	"github.com/tendry-lab/device-hub/components/system/syssched"
 )
 
 type testCacheStoreDB struct {
@@ -81,6 +89,12 @@ type testCacheStoreDataHandler struct {
 	deviceID     string
 	telemetry    chan devcore.JSON
 	registration chan devcore.JSON
+	stream       chan testCacheStoreStreamData
+}
+
+type testCacheStoreStreamData struct {
+	measurement string
+	js          devcore.JSON
 }
 
 func newTestCacheStoreDataHandler(t *testing.T, deviceID string) *testCacheStoreDataHandler {
@@ -89,6 +103,7 @@ func newTestCacheStoreDataHandler(t *testing.T, deviceID string) *testCacheStore
 		deviceID:     deviceID,
 		telemetry:    make(chan devcore.JSON),
 		registration: make(chan devcore.JSON),
+		stream:       make(chan testCacheStoreStreamData),
 	}
 }
 
@@ -117,6 +132,21 @@ func (h *testCacheStoreDataHandler) HandleRegistration(
 	return nil
 }
 
+func (h *testCacheStoreDataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	require.Equal(h.t, h.deviceID, deviceID)
+
+	select {
+	case h.stream <- testCacheStoreStreamData{measurement: measurement, js: maps.Clone(js)}:
+	default:
+	}
+
+	return nil
+}
+
 type testCacheStoreClock struct {
 	timestamp int64
 }
@@ -182,6 +212,8 @@ func newTestDataHandlerBuilder(t *testing.T) *testDataHandlerBuilder {
 func (b *testDataHandlerBuilder) BuildHandler(
 	_ syscore.SystemClock,
 	deviceID string,
+	_ string,
+	_ string,
 ) devcore.DataHandler {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -322,6 +354,32 @@ func TestCacheStoreRemoveNoAdd(t *testing.T) {
 	require.Equal(t, status.StatusNoData, store.Remove("foo-bar-baz"))
 }
 
+func TestCacheStorePauseNoAdd(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Equal(t, status.StatusNoData, store.Pause("foo-bar-baz"))
+	require.Equal(t, status.StatusNoData, store.Resume("foo-bar-baz"))
+}
+
 func TestCacheStoreAddURIUnsupportedScheme(t *testing.T) {
 	db := newTestCacheStoreDB()
 	clock := &testCacheStoreClock{}
@@ -345,7 +403,7 @@ func TestCacheStoreAddURIUnsupportedScheme(t *testing.T) {
 	}()
 
 	require.Equal(t, status.StatusNotSupported,
-		store.Add("foo-bar-baz", "test-type", "foo-bar-baz"))
+		store.Add("foo-bar-baz", "test-type", "foo-bar-baz", DeviceOptions{}))
 }
 
 func TestCacheStoreAddRemoveResourceNoResponse(t *testing.T) {
@@ -389,7 +447,7 @@ func TestCacheStoreAddRemoveResourceNoResponse(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		require.Nil(t, store.Add(test.uri, test.typ, test.desc))
+		require.Nil(t, store.Add(test.uri, test.typ, test.desc, DeviceOptions{}))
 	}
 
 	<-ctx.Done()
@@ -460,7 +518,7 @@ func TestCacheStoreAddRemove(t *testing.T) {
 	server := httptest.NewServer(mux)
 	defer server.Close()
 
-	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz"))
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
 
 	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Millisecond*200)
 	defer cancelFunc()
@@ -522,7 +580,7 @@ func TestCacheStoreRestore(t *testing.T) {
 	deviceDesc := "foo-bar-baz"
 	deviceType := "test-type"
 
-	require.Nil(t, store1.Add(deviceURI, deviceType, deviceDesc))
+	require.Nil(t, store1.Add(deviceURI, deviceType, deviceDesc, DeviceOptions{}))
 
 	ctx1, cancelFunc1 := context.WithTimeout(context.Background(), time.Millisecond*200)
 	defer cancelFunc1()
@@ -547,7 +605,7 @@ func TestCacheStoreRestore(t *testing.T) {
 
 	require.Nil(t, store2.Start())
 
-	require.NotNil(t, store2.Add(deviceURI, deviceType, deviceDesc))
+	require.NotNil(t, store2.Add(deviceURI, deviceType, deviceDesc, DeviceOptions{}))
 
 	ctx2, cancelFunc2 := context.WithTimeout(context.Background(), time.Millisecond*200)
 	defer cancelFunc2()
@@ -562,7 +620,7 @@ func TestCacheStoreRestore(t *testing.T) {
 	handlerBuilder3 := newTestDataHandlerBuilder(t)
 	store3 := makeStore(db, handlerBuilder3)
 
-	require.Nil(t, store3.Add(deviceURI, deviceType, deviceDesc))
+	require.Nil(t, store3.Add(deviceURI, deviceType, deviceDesc, DeviceOptions{}))
 
 	ctx3, cancelFunc3 := context.WithTimeout(context.Background(), time.Millisecond*200)
 	defer cancelFunc3()
@@ -595,10 +653,11 @@ func TestCacheStoreAddSameDevice(t *testing.T) {
 		require.Nil(t, store.Stop())
 	}()
 
-	require.Nil(t, store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com"))
+	require.Nil(t,
+		store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com", DeviceOptions{}))
 
 	require.Equal(t, ErrDeviceExist,
-		store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com"))
+		store.Add("http://foo.bar.com:123", "test-type", "foo-bar-com", DeviceOptions{}))
 }
 
 func TestCacheStoreNoopDB(t *testing.T) {
@@ -627,7 +686,7 @@ func TestCacheStoreNoopDB(t *testing.T) {
 	deviceDesc := "foo-bar-com"
 	deviceType := "test-type"
 
-	require.Nil(t, store.Add(deviceURI, deviceType, deviceDesc))
+	require.Nil(t, store.Add(deviceURI, deviceType, deviceDesc, DeviceOptions{}))
 	require.Nil(t, store.Remove(deviceURI))
 }
 
@@ -704,3 +763,1203 @@ func TestCacheStoreRestoreUnsupportedScheme(t *testing.T) {
 	_, err = db.Read(deviceURI)
 	require.Equal(t, status.StatusNoData, err)
 }
+
+type testCacheStoreAliveNotifier struct {
+	uri     string
+	aliveCh chan string
+}
+
+func (n *testCacheStoreAliveNotifier) NotifyAlive() {
+	select {
+	case n.aliveCh <- n.uri:
+	default:
+	}
+}
+
+type testCacheStoreAliveMonitor struct {
+	aliveCh chan string
+}
+
+func (m *testCacheStoreAliveMonitor) Monitor(uri string) syssched.AliveNotifier {
+	return &testCacheStoreAliveNotifier{
+		uri:     uri,
+		aliveCh: m.aliveCh,
+	}
+}
+
+func TestCacheStoreRestoreAliveMonitor(t *testing.T) {
+	deviceID := "0xABCD"
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	storageItem := StorageItem{
+		Desc:      "foo-bar-baz",
+		Timestamp: time.Now().Unix(),
+		Type:      "test-type",
+	}
+	buf, err := storageItem.MarshalBinary()
+	require.Nil(t, err)
+
+	db := newTestCacheStoreDB()
+	require.Nil(t, db.Write(server.URL, buf))
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		&testCacheStoreClock{},
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	monitor := &testCacheStoreAliveMonitor{
+		aliveCh: make(chan string, 1),
+	}
+	store.SetAliveMonitor(monitor)
+
+	require.Nil(t, store.Start())
+
+	select {
+	case uri := <-monitor.aliveCh:
+		require.Equal(t, server.URL, uri)
+
+	case <-time.After(time.Second):
+		require.Fail(t, "restored device isn't monitored")
+	}
+}
+
+// publishTestCacheStoreMQTT publishes the message until it's received by the handler,
+// since the handler drops the data if nobody is waiting for it.
+func publishTestCacheStoreMQTT(
+	ctx context.Context,
+	t *testing.T,
+	publisher *mqcore.Client,
+	topic string,
+	buf []byte,
+	dataCh chan devcore.JSON,
+) devcore.JSON {
+	for {
+		require.Nil(t, publisher.Publish(topic, buf))
+
+		select {
+		case js := <-dataCh:
+			return js
+		case <-time.After(time.Millisecond * 50):
+		case <-ctx.Done():
+			require.FailNow(t, "data isn't received within timeout")
+		}
+	}
+}
+
+func TestCacheStoreAddRemoveMQTT(t *testing.T) {
+	broker, err := mqtest.NewBroker(mqtest.BrokerParams{Host: "127.0.0.1"})
+	require.Nil(t, err)
+	require.Nil(t, broker.Start())
+	defer func() {
+		require.Nil(t, broker.Stop())
+	}()
+
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.MQTT.Timeout = time.Second
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceID := "0xABCD"
+	uri := fmt.Sprintf("mqtt://127.0.0.1:%d/bonsai/%s", broker.Port(), deviceID)
+
+	require.Nil(t, store.Add(uri, "test-type", "foo-bar-baz", DeviceOptions{}))
+	require.Equal(t, 1, db.count())
+
+	publisher, err := mqcore.NewClient(mqcore.ClientParams{
+		BrokerURL: broker.URL(),
+		Timeout:   time.Second,
+	})
+	require.Nil(t, err)
+	require.Nil(t, publisher.Start())
+	defer func() {
+		require.Nil(t, publisher.Stop())
+	}()
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+	telemetryData["temperature"] = float64(123.222)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	telemetryBuf, err := json.Marshal(telemetryData)
+	require.Nil(t, err)
+
+	registrationBuf, err := json.Marshal(registrationData)
+	require.Nil(t, err)
+
+	// The device subscription is performed asynchronously, so keep publishing
+	// until the registration is received.
+	require.Eventually(t, func() bool {
+		_ = publisher.Publish("bonsai/"+deviceID+"/registration", registrationBuf)
+
+		for _, desc := range store.GetDesc() {
+			if desc.ID == deviceID {
+				return true
+			}
+		}
+
+		return false
+	}, time.Second*5, time.Millisecond*50)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	handler := handlerBuilder.getHandler(ctx, deviceID)
+
+	require.True(t, maps.Equal(registrationData, publishTestCacheStoreMQTT(
+		ctx, t, publisher, "bonsai/"+deviceID+"/registration", registrationBuf,
+		handler.registration)))
+
+	require.True(t, maps.Equal(telemetryData, publishTestCacheStoreMQTT(
+		ctx, t, publisher, "bonsai/"+deviceID+"/telemetry", telemetryBuf,
+		handler.telemetry)))
+
+	require.Nil(t, store.Remove(uri))
+	require.Equal(t, 0, db.count())
+	require.Empty(t, store.GetDesc())
+}
+
+func TestCacheStoreAddInvalidMQTT(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.MQTT.Timeout = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	for _, uri := range []string{
+		"mqtt://127.0.0.1/bonsai/0xABCD",
+		"mqtt://127.0.0.1:1883",
+		"mqtt://127.0.0.1:1883/",
+	} {
+		require.NotNil(t, store.Add(uri, "test-type", "foo-bar-baz", DeviceOptions{}))
+	}
+
+	require.Equal(t, 0, db.count())
+}
+
+func TestCacheStoreAddInvalidPush(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	for _, uri := range []string{
+		"push://",
+		"push://0xABCD/api/v1",
+		"push://0xABCD?foo=bar",
+		"push://user@0xABCD",
+	} {
+		require.NotNil(t, store.Add(uri, "test-type", "foo-bar-baz", DeviceOptions{}))
+	}
+
+	require.Equal(t, 0, db.count())
+}
+
+func TestCacheStoreAddProfile(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	profile := DefaultDeviceProfile()
+	profile.HTTP.Registration = HTTPEndpoint{Path: "/api/info", Method: http.MethodPost}
+	profile.HTTP.Telemetry = HTTPEndpoint{Path: "/api/data", Method: http.MethodGet}
+	profile.HTTP.TimePath = ""
+	profile.Schema = devcore.DataSchema{
+		IDPath:        "meta.id",
+		TimestampPath: "meta.ts",
+		TimestampUnit: devcore.TimestampUnitMillisecond,
+	}
+
+	profiles := NewProfileRegistry()
+	require.Nil(t, profiles.Register("third-party", profile))
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.Profiles = profiles
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceID := "0xABCD"
+
+	registrationHandler := newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"meta": map[string]any{"id": deviceID, "ts": float64(123000)},
+	})
+	telemetryHandler := newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"meta":        map[string]any{"ts": float64(124500)},
+		"temperature": float64(123.222),
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /api/info", registrationHandler)
+	mux.Handle("GET /api/data", telemetryHandler)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "third-party", "foo-bar-baz", DeviceOptions{}))
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	handler := handlerBuilder.getHandler(ctx, deviceID)
+
+	telemetry := <-handler.telemetry
+	require.Equal(t, float64(124), telemetry["timestamp"])
+	require.Equal(t, float64(123.222), telemetry["temperature"])
+
+	registration := <-handler.registration
+	require.Equal(t, float64(123), registration["timestamp"])
+}
+
+func TestCacheStoreAddStreams(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	profile := DefaultDeviceProfile()
+	profile.HTTP.Streams = []HTTPStream{
+		{
+			Name:         "diagnostics",
+			HTTPEndpoint: HTTPEndpoint{Path: "/diagnostics", Method: http.MethodGet},
+			Interval:     time.Millisecond * 50,
+		},
+		{
+			Name:         "network",
+			HTTPEndpoint: HTTPEndpoint{Path: "/network", Method: http.MethodPost},
+			Measurement:  "network_stats",
+		},
+	}
+
+	profiles := NewProfileRegistry()
+	require.Nil(t, profiles.Register("test-type", profile))
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.HTTP.RegistrationInterval = time.Minute
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.Profiles = profiles
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceID := "0xABCD"
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /registration", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"device_id": deviceID,
+		"timestamp": float64(123),
+	}))
+	mux.Handle("GET /telemetry", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(123),
+	}))
+	mux.Handle("GET /diagnostics", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(123),
+		"heap_free": float64(1024),
+	}))
+	mux.Handle("POST /network", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(123),
+		"rssi":      float64(-42),
+	}))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancelFunc()
+
+	handler := handlerBuilder.getHandler(ctx, deviceID)
+
+	streams := make(map[string]devcore.JSON)
+
+	for len(streams) < 2 {
+		select {
+		case data := <-handler.stream:
+			streams[data.measurement] = data.js
+		case <-ctx.Done():
+			require.FailNow(t, "stream data isn't received")
+		}
+	}
+
+	require.Equal(t, float64(1024), streams["diagnostics"]["heap_free"])
+	require.Equal(t, float64(-42), streams["network_stats"]["rssi"])
+}
+
+func TestCacheStoreUpdateNoAdd(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		CacheStoreParams{},
+	)
+
+	require.Equal(t, status.StatusNoData,
+		store.Update("push://0xABCD", "push://0xABCD", "test-type", "foo-bar-baz"))
+	require.Equal(t, 0, db.count())
+}
+
+func TestCacheStoreUpdatePush(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	require.Nil(t, store.Add("push://0xABCD", "test-type", "foo-bar-baz", DeviceOptions{}))
+	require.Nil(t, store.Add("push://0xBCDE", "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	createdAt := store.GetDesc()[0].CreatedAt
+
+	require.Equal(t, ErrDeviceExist,
+		store.Update("push://0xABCD", "push://0xBCDE", "test-type", "foo-bar-baz"))
+	require.NotNil(t, store.Update("push://0xABCD", "push://", "test-type", "foo-bar-baz"))
+
+	var item StorageItem
+	_, err := item.Unmarshal(db.data["push://0xABCD"])
+	require.Nil(t, err)
+	require.Equal(t, "foo-bar-baz", item.Desc)
+
+	require.Nil(t, store.Update("push://0xABCD", "push://0xABCD", "test-type", "kitchen"))
+
+	_, err = item.Unmarshal(db.data["push://0xABCD"])
+	require.Nil(t, err)
+	require.Equal(t, "kitchen", item.Desc)
+	require.Equal(t, "test-type", item.Type)
+
+	require.Nil(t, store.Update("push://0xABCD", "push://0xCDEF", "new-type", "kitchen"))
+
+	_, ok := db.data["push://0xABCD"]
+	require.False(t, ok)
+
+	_, err = item.Unmarshal(db.data["push://0xCDEF"])
+	require.Nil(t, err)
+	require.Equal(t, "kitchen", item.Desc)
+	require.Equal(t, "new-type", item.Type)
+
+	_, err = store.GetPushHandler("0xABCD")
+	require.Equal(t, status.StatusNoData, err)
+
+	_, err = store.GetPushHandler("0xCDEF")
+	require.Nil(t, err)
+
+	for _, desc := range store.GetDesc() {
+		if desc.URI == "push://0xCDEF" {
+			require.Equal(t, "0xCDEF", desc.ID)
+			require.Equal(t, createdAt, desc.CreatedAt)
+		}
+	}
+
+	require.Equal(t, 2, db.count())
+}
+
+func TestCacheStoreUpdateURI(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 50
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceID := "0xABCD"
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	makeServer := func(temperature float64) *httptest.Server {
+		telemetryData := make(devcore.JSON)
+		telemetryData["timestamp"] = float64(123)
+		telemetryData["temperature"] = temperature
+
+		mux := http.NewServeMux()
+		mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+		mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+		return httptest.NewServer(mux)
+	}
+
+	server1 := makeServer(1)
+	defer server1.Close()
+
+	server2 := makeServer(2)
+	defer server2.Close()
+
+	require.Nil(t, store.Add(server1.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Millisecond*200)
+	defer cancelFunc()
+
+	handler := handlerBuilder.getHandler(ctx, deviceID)
+	require.Equal(t, float64(1), (<-handler.telemetry)["temperature"])
+
+	require.Nil(t, store.Update(server1.URL, server2.URL, "test-type", "foo-bar-baz"))
+
+	// Data handler isn't rebuilt, since the type and description aren't changed.
+	require.Eventually(t, func() bool {
+		return (<-handler.telemetry)["temperature"] == float64(2)
+	}, time.Second, time.Millisecond*10)
+
+	items := store.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, server2.URL, items[0].URI)
+	require.Equal(t, deviceID, items[0].ID)
+
+	_, ok := db.data[server1.URL]
+	require.False(t, ok)
+
+	_, ok = db.data[server2.URL]
+	require.True(t, ok)
+}
+
+func TestCacheStoreAddHTTPS(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceID := "0xABCD"
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	// Self-signed device certificate is trusted by its fingerprint.
+	fingerprint := sha256.Sum256(server.Certificate().Raw)
+	opts := DeviceOptions{
+		TLS: DeviceTLS{Fingerprint: hex.EncodeToString(fingerprint[:])},
+	}
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", opts))
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	handler := handlerBuilder.getHandler(ctx, deviceID)
+
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+
+	items := store.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, &opts.TLS, items[0].TLS)
+
+	// TLS settings are persisted with the device.
+	var item StorageItem
+	require.Nil(t, item.UnmarshalBinary(db.data[server.URL]))
+	require.Equal(t, opts.TLS.Fingerprint, item.TLSFingerprint)
+}
+
+func TestCacheStoreAddInvalidTLS(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	for _, test := range []struct {
+		uri string
+		tls DeviceTLS
+	}{
+		{"http://192.168.4.1:17321", DeviceTLS{ServerName: "sensor.local"}},
+		{"mqtt://localhost:1883/bonsai", DeviceTLS{ServerName: "sensor.local"}},
+		{"https://192.168.4.1:17321", DeviceTLS{Fingerprint: "abcd"}},
+		{"https://192.168.4.1:17321", DeviceTLS{CertPath: "/unknown/client.pem"}},
+		{"https://192.168.4.1:17321", DeviceTLS{CAPath: "/unknown/ca.pem"}},
+	} {
+		err := store.Add(test.uri, "test-type", "foo-bar-baz", DeviceOptions{TLS: test.tls})
+		require.ErrorIs(t, err, status.StatusInvalidArg, test.uri)
+	}
+
+	require.Equal(t, 0, db.count())
+	require.Empty(t, store.GetDesc())
+}
+
+func TestCacheStoreAddCredentials(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	key := make([]byte, stcore.CipherKeySize)
+	cipher, err := stcore.NewAESCipher(key)
+	require.Nil(t, err)
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.Cipher = cipher
+
+	deviceID := "0xABCD"
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	authorize := func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret-token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", authorize(newTestCacheStoreHTTPDataHandler(telemetryData)))
+	mux.Handle("/registration",
+		authorize(newTestCacheStoreHTTPDataHandler(registrationData)))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	opts := DeviceOptions{
+		Credentials: DeviceCredentials{
+			Type:  CredentialsTypeBearer,
+			Token: "secret-token",
+		},
+	}
+
+	for n := 0; n < 2; n++ {
+		handlerBuilder := newTestDataHandlerBuilder(t)
+
+		store := NewCacheStore(
+			context.Background(),
+			clock,
+			&testSystemClockReaderBuilder{},
+			handlerBuilder,
+			db,
+			sysnet.NewResolveStore(),
+			storeParams,
+		)
+
+		// Device is restored with the persisted credentials on the second run.
+		if n == 0 {
+			require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", opts))
+		} else {
+			require.Nil(t, store.Start())
+		}
+
+		ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+		handler := handlerBuilder.getHandler(ctx, deviceID)
+
+		require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+		require.True(t, maps.Equal(registrationData, <-handler.registration))
+		cancelFunc()
+
+		// Credentials are redacted.
+		items := store.GetDesc()
+		require.Equal(t, 1, len(items))
+		require.Equal(t, &DeviceCredentials{Type: CredentialsTypeBearer}, items[0].Credentials)
+
+		require.Nil(t, store.Stop())
+	}
+
+	// Credentials are persisted encrypted.
+	var item StorageItem
+	require.Nil(t, item.UnmarshalBinary(db.data[server.URL]))
+	require.NotEmpty(t, item.Credentials)
+	require.NotContains(t, string(item.Credentials), "secret-token")
+}
+
+func TestCacheStoreAddInvalidCredentials(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	for _, test := range []struct {
+		uri         string
+		credentials DeviceCredentials
+		err         error
+	}{
+		{
+			"mqtt://localhost:1883/bonsai",
+			DeviceCredentials{Type: CredentialsTypeBearer, Token: "foo"},
+			status.StatusInvalidArg,
+		},
+		{
+			"http://192.168.4.1:17321",
+			DeviceCredentials{Type: CredentialsTypeBearer},
+			status.StatusInvalidArg,
+		},
+		{
+			"http://192.168.4.1:17321",
+			DeviceCredentials{Type: CredentialsTypeHeader, Header: "X-API-Key"},
+			status.StatusInvalidArg,
+		},
+		{
+			"http://192.168.4.1:17321",
+			DeviceCredentials{Type: "digest", Username: "foo"},
+			status.StatusInvalidArg,
+		},
+		// Credentials can't be persisted without the cipher.
+		{
+			"http://192.168.4.1:17321",
+			DeviceCredentials{Type: CredentialsTypeBasic, Username: "foo"},
+			status.StatusInvalidState,
+		},
+	} {
+		err := store.Add(test.uri, "test-type", "foo-bar-baz", DeviceOptions{
+			Credentials: test.credentials,
+		})
+		require.ErrorIs(t, err, test.err, test.uri)
+	}
+
+	require.Equal(t, 0, db.count())
+	require.Empty(t, store.GetDesc())
+}
+
+func TestCacheStoreScheduler(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	scheduler := syssched.NewScheduler(ctx, syssched.SchedulerParams{Workers: 2})
+	require.Nil(t, scheduler.Start())
+	defer func() {
+		cancelFunc()
+		require.Nil(t, scheduler.Stop())
+	}()
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.Scheduler = scheduler
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		ctx,
+		&testCacheStoreClock{},
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+
+	deviceID := "0xABCD"
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	handlerCtx, handlerCancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer handlerCancelFunc()
+
+	handler := handlerBuilder.getHandler(handlerCtx, deviceID)
+
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+
+	// Device tasks are stopped on the shared scheduler.
+	require.Nil(t, store.Remove(server.URL))
+	require.Nil(t, store.Stop())
+}
+
+func TestCacheStoreWatchdog(t *testing.T) {
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	defer cancelFunc()
+
+	watchdog := syssched.NewWatchdog(syssched.WatchdogParams{
+		Deadline: time.Millisecond * 100,
+	})
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 50
+	storeParams.HTTP.FetchTimeout = time.Second * 30
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.Watchdog = watchdog
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := NewCacheStore(
+		ctx,
+		&testCacheStoreClock{},
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+
+	deviceID := "0xABCD"
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+
+	var hang atomic.Bool
+	hang.Store(true)
+
+	registrationHandler := newTestCacheStoreHTTPDataHandler(registrationData)
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.HandleFunc("/registration", func(w http.ResponseWriter, r *http.Request) {
+		if hang.Load() {
+			// Request hangs until it's canceled by the watchdog.
+			<-r.Context().Done()
+
+			return
+		}
+
+		registrationHandler.ServeHTTP(w, r)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	require.Eventually(t, func() bool {
+		stalls := watchdog.GetStalls()
+
+		return len(stalls) == 1 && stalls[0].Count >= 2
+	}, time.Second*5, time.Millisecond*10)
+
+	require.Equal(t, server.URL+"-device-http", watchdog.GetStalls()[0].Task)
+
+	// Device is polled again once the stalled run is interrupted.
+	hang.Store(false)
+
+	handlerCtx, handlerCancelFunc := context.WithTimeout(context.Background(), time.Second*5)
+	defer handlerCancelFunc()
+
+	handler := handlerBuilder.getHandler(handlerCtx, deviceID)
+
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+
+	// Removed device isn't watched anymore.
+	require.Nil(t, store.Remove(server.URL))
+	require.Eventually(t, func() bool {
+		return len(watchdog.GetStalls()) == 0
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestCacheStoreBreakerResolve(t *testing.T) {
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 10
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 50
+	storeParams.HTTP.BreakerThreshold = 1
+	storeParams.HTTP.BreakerInterval = time.Hour
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.Diagnostics.HistorySize = 10
+
+	handlerBuilder := newTestDataHandlerBuilder(t)
+	resolveStore := sysnet.NewResolveStore()
+
+	store := NewCacheStore(
+		context.Background(),
+		&testCacheStoreClock{},
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		newTestCacheStoreDB(),
+		resolveStore,
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	deviceID := "0xABCD"
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(123)
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(123)
+	registrationData["device_id"] = deviceID
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(telemetryData))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(registrationData))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	serverAddr := server.Listener.Addr().(*net.TCPAddr)
+	uri := fmt.Sprintf("http://bonsai-growlab.local:%d", serverAddr.Port)
+
+	require.Nil(t, store.Add(uri, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	// Device host isn't resolved, the breaker is opened after the first failure.
+	require.Eventually(t, func() bool {
+		diag, err := store.GetDiagnostics(uri)
+		require.Nil(t, err)
+
+		return diag.ConsecutiveFailures == 1
+	}, time.Second, time.Millisecond*10)
+
+	resolvedAddr := &net.IPAddr{IP: serverAddr.IP}
+	resolveStore.HandleResolve("bonsai-growlab.local", resolvedAddr)
+	store.HandleResolve("bonsai-growlab.local", resolvedAddr)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	handler := handlerBuilder.getHandler(ctx, deviceID)
+
+	require.True(t, maps.Equal(telemetryData, <-handler.telemetry))
+	require.True(t, maps.Equal(registrationData, <-handler.registration))
+}
+
+type testPausableTask struct {
+	calls int
+}
+
+func (t *testPausableTask) Run() error {
+	t.calls++
+
+	return nil
+}
+
+func TestCacheStorePausableTask(t *testing.T) {
+	clock := &testStoreAliveMonitorClock{now: time.Now()}
+	paused := &atomic.Bool{}
+	task := &testPausableTask{}
+
+	pausable := newPausableTask(paused, task, time.Minute)
+	pausable.clock = clock
+
+	require.Nil(t, pausable.Run())
+	require.Equal(t, 1, task.calls)
+
+	paused.Store(true)
+
+	// Paused task is skipped until the interval is elapsed.
+	clock.now = clock.now.Add(time.Second * 30)
+	require.Nil(t, pausable.Run())
+	require.Equal(t, 1, task.calls)
+
+	clock.now = clock.now.Add(time.Second * 30)
+	require.Nil(t, pausable.Run())
+	require.Equal(t, 2, task.calls)
+
+	require.Nil(t, pausable.Run())
+	require.Equal(t, 2, task.calls)
+
+	paused.Store(false)
+
+	require.Nil(t, pausable.Run())
+	require.Equal(t, 3, task.calls)
+}
+
+func TestCacheStorePausableTaskNoInterval(t *testing.T) {
+	paused := &atomic.Bool{}
+	paused.Store(true)
+
+	task := &testPausableTask{}
+
+	pausable := newPausableTask(paused, task, 0)
+
+	require.Nil(t, pausable.Run())
+	require.Equal(t, 0, task.calls)
+}
+
+type testBlockingDataHandlerBuilder struct {
+	blockedCh chan struct{}
+	releaseCh chan struct{}
+}
+
+func (b *testBlockingDataHandlerBuilder) BuildHandler(
+	syscore.SystemClock,
+	string,
+	string,
+	string,
+) devcore.DataHandler {
+	return b
+}
+
+func (b *testBlockingDataHandlerBuilder) HandleTelemetry(string, devcore.JSON) error {
+	select {
+	case b.blockedCh <- struct{}{}:
+	default:
+	}
+
+	// Blocking operation which ignores the device context, e.g. slow sink write.
+	<-b.releaseCh
+
+	return nil
+}
+
+func (*testBlockingDataHandlerBuilder) HandleRegistration(string, devcore.JSON) error {
+	return nil
+}
+
+func (*testBlockingDataHandlerBuilder) HandleStream(string, string, devcore.JSON) error {
+	return nil
+}
+
+func TestCacheStoreRemoveStopTimeout(t *testing.T) {
+	db := newTestCacheStoreDB()
+	clock := &testCacheStoreClock{}
+
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 10
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.StopTimeout = time.Millisecond * 50
+
+	handlerBuilder := &testBlockingDataHandlerBuilder{
+		blockedCh: make(chan struct{}, 1),
+		releaseCh: make(chan struct{}),
+	}
+	defer close(handlerBuilder.releaseCh)
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	defer func() {
+		require.Nil(t, store.Stop())
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(123),
+	}))
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(123),
+		"device_id": "0xABCD",
+	}))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	select {
+	case <-handlerBuilder.blockedCh:
+	case <-time.After(time.Second):
+		require.FailNow(t, "device data isn't handled")
+	}
+
+	start := time.Now()
+
+	// Hung device task doesn't block the store.
+	require.Nil(t, store.Remove(server.URL))
+	require.Less(t, time.Since(start), time.Second)
+	require.Empty(t, store.GetDesc())
+}
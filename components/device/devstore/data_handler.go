@@ -6,6 +6,8 @@
 package devstore
 
 import (
+	"sync"
+
 	"github.com/tendry-lab/device-hub/components/device/devcore"
 	"github.com/tendry-lab/device-hub/components/system/syscore"
 )
@@ -13,31 +15,73 @@ import (
 type dataHandler struct {
 	clock   syscore.SystemClock
 	builder DataHandlerBuilder
+	typ     string
+	desc    string
+
+	mu      sync.Mutex
 	handler devcore.DataHandler
 }
 
-func newDataHandler(clock syscore.SystemClock, builder DataHandlerBuilder) *dataHandler {
+func newDataHandler(
+	clock syscore.SystemClock,
+	builder DataHandlerBuilder,
+	typ string,
+	desc string,
+) *dataHandler {
 	return &dataHandler{
 		clock:   clock,
 		builder: builder,
+		typ:     typ,
+		desc:    desc,
 	}
 }
 
 func (h *dataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
-	h.buildHanlder(deviceID)
-	return h.handler.HandleTelemetry(deviceID, js)
+	return h.buildHanlder(deviceID).HandleTelemetry(deviceID, js)
 }
 
 func (h *dataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
-	h.buildHanlder(deviceID)
-	return h.handler.HandleRegistration(deviceID, js)
+	return h.buildHanlder(deviceID).HandleRegistration(deviceID, js)
+}
+
+func (h *dataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	return h.buildHanlder(deviceID).HandleStream(deviceID, measurement, js)
 }
 
-func (h *dataHandler) buildHanlder(deviceID string) {
+// update changes the device type and description, the handler is rebuilt
+// on the next received data if any of them is changed.
+func (h *dataHandler) update(typ string, desc string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.typ == typ && h.desc == desc {
+		return
+	}
+
+	h.typ = typ
+	h.desc = desc
+	h.handler = nil
+}
+
+// buildHanlder builds the handler on the first call, since the device ID is unknown
+// until the device data is received.
+//
+// Remarks:
+//   - Device data streams are handled by multiple goroutines.
+func (h *dataHandler) buildHanlder(deviceID string) devcore.DataHandler {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	if h.handler == nil {
-		h.handler = h.builder.BuildHandler(h.clock, deviceID)
+		h.handler = h.builder.BuildHandler(h.clock, deviceID, h.typ, h.desc)
 		if h.handler == nil {
 			panic("invalid state: handler can't be nil")
 		}
 	}
+
+	return h.handler
 }
@@ -14,5 +14,33 @@ import (
 type DataHandlerBuilder interface {
 	// BuildHandler builds a data handler that updates the provided system clock
 	// each time it receives data.
-	BuildHandler(clock syscore.SystemClock, deviceID string) devcore.DataHandler
+	//
+	// Parameters:
+	//   - clock to update the most recent UNIX time.
+	//   - deviceID - unique device identifier.
+	//   - typ - device type, as passed when the device is added.
+	//   - desc - device description, as passed when the device is added.
+	BuildHandler(
+		clock syscore.SystemClock,
+		deviceID string,
+		typ string,
+		desc string,
+	) devcore.DataHandler
+}
+
+// FallbackHandlerBuilder builds the data handlers, which may complete handling the
+// data after returning, e.g. write it in batches.
+type FallbackHandlerBuilder interface {
+	// BuildFallbackHandler builds a data handler, the same as BuildHandler().
+	//
+	// Parameters:
+	//   - fallback - handler to pass the data which fails to be handled after the
+	//     built handler has returned.
+	BuildFallbackHandler(
+		clock syscore.SystemClock,
+		deviceID string,
+		typ string,
+		desc string,
+		fallback devcore.DataHandler,
+	) devcore.DataHandler
 }
@@ -0,0 +1,363 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+// diagnosticsLatencyWindow - how many latest fetch latencies are used to calculate
+// the percentiles.
+const diagnosticsLatencyWindow = 100
+
+const (
+	diagnosticsKindRegistration = "registration"
+	diagnosticsKindTelemetry    = "telemetry"
+	diagnosticsKindDevice       = "device"
+)
+
+// DiagnosticsPayload is a raw data received from the device.
+type DiagnosticsPayload struct {
+	Time string `json:"time"`
+	Data string `json:"data"`
+}
+
+// DiagnosticsError is an error occurred while handling the device data.
+type DiagnosticsError struct {
+	Time   string `json:"time"`
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// DiagnosticsLatency is the fetch latency percentiles, in milliseconds.
+type DiagnosticsLatency struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+}
+
+// DiagnosticsTimeSync is a single device time synchronization.
+type DiagnosticsTimeSync struct {
+	Time string `json:"time"`
+
+	// Drift - last observed difference between local and device UNIX time, in seconds.
+	Drift int64 `json:"drift"`
+
+	// Error - synchronization error, empty if the synchronization has succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// DeviceDiagnostics is the runtime diagnostics of a single device.
+type DeviceDiagnostics struct {
+	URI string `json:"uri"`
+	ID  string `json:"id"`
+
+	// Registration - latest raw registration payloads.
+	Registration []DiagnosticsPayload `json:"registration"`
+
+	// Telemetry - latest raw telemetry payloads.
+	Telemetry []DiagnosticsPayload `json:"telemetry"`
+
+	// Errors - latest errors.
+	Errors []DiagnosticsError `json:"errors"`
+
+	// ConsecutiveFailures - number of failures since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastSuccess - time of the last successfully handled data, empty if none.
+	LastSuccess string `json:"last_success,omitempty"`
+
+	// Latency - fetch latency percentiles keyed by the data kind.
+	//
+	// Remarks:
+	//  - Only polled devices have the fetch latency.
+	Latency map[string]DiagnosticsLatency `json:"latency"`
+
+	// ResolvedAddr - device address resolved over mDNS, empty if not resolved.
+	ResolvedAddr string `json:"resolved_addr,omitempty"`
+
+	// Drift - last observed difference between local and device UNIX time, in seconds.
+	Drift int64 `json:"drift"`
+
+	// TimeSyncs - latest device time synchronizations.
+	TimeSyncs []DiagnosticsTimeSync `json:"time_syncs"`
+}
+
+// DiagnosticsReader reads the device runtime diagnostics.
+type DiagnosticsReader interface {
+	// GetDiagnostics returns the diagnostics of the device.
+	//
+	// Parameters:
+	//  - device - device URI or ID.
+	//
+	// Remarks:
+	//  - status.StatusNoData is returned if the device isn't added.
+	GetDiagnostics(device string) (DeviceDiagnostics, error)
+}
+
+// deviceDiagnostics collects the runtime diagnostics of a single device.
+type deviceDiagnostics struct {
+	size int
+
+	mu           sync.Mutex
+	registration []DiagnosticsPayload
+	telemetry    []DiagnosticsPayload
+	errors       []DiagnosticsError
+	latencies    map[string][]time.Duration
+	failures     int
+	lastSuccess  time.Time
+	drift        int64
+	timeSyncs    []DiagnosticsTimeSync
+}
+
+// newDeviceDiagnostics is an initialization of deviceDiagnostics.
+//
+// Parameters:
+//   - size - how many payloads, errors and time synchronizations to keep.
+func newDeviceDiagnostics(size int) *deviceDiagnostics {
+	return &deviceDiagnostics{
+		size:      size,
+		latencies: make(map[string][]time.Duration),
+	}
+}
+
+func (d *deviceDiagnostics) addPayload(kind string, buf []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	payload := DiagnosticsPayload{
+		Time: formatDiagnosticsTime(time.Now()),
+		Data: string(buf),
+	}
+
+	switch kind {
+	case diagnosticsKindRegistration:
+		d.registration = appendDiagnostics(d.registration, payload, d.size)
+	case diagnosticsKindTelemetry:
+		d.telemetry = appendDiagnostics(d.telemetry, payload, d.size)
+	}
+}
+
+func (d *deviceDiagnostics) addError(source string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.errors = appendDiagnostics(d.errors, DiagnosticsError{
+		Time:   formatDiagnosticsTime(time.Now()),
+		Source: source,
+		Error:  err.Error(),
+	}, d.size)
+}
+
+// recordError records the error, and returns it marked as recorded, so the error
+// handler the error is propagated to doesn't record it again.
+func (d *deviceDiagnostics) recordError(source string, err error) error {
+	d.addError(source, err)
+
+	return &diagnosticsError{err: err}
+}
+
+func (d *deviceDiagnostics) addLatency(kind string, latency time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.latencies[kind] = appendDiagnostics(
+		d.latencies[kind], latency, diagnosticsLatencyWindow)
+}
+
+func (d *deviceDiagnostics) addResult(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil {
+		d.failures++
+	} else {
+		d.failures = 0
+		d.lastSuccess = time.Now()
+	}
+}
+
+func (d *deviceDiagnostics) getFailures() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.failures
+}
+
+func (d *deviceDiagnostics) setDrift(drift int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.drift = drift
+}
+
+func (d *deviceDiagnostics) addTimeSync(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	timeSync := DiagnosticsTimeSync{
+		Time:  formatDiagnosticsTime(time.Now()),
+		Drift: d.drift,
+	}
+	if err != nil {
+		timeSync.Error = err.Error()
+	}
+
+	d.timeSyncs = appendDiagnostics(d.timeSyncs, timeSync, d.size)
+}
+
+func (d *deviceDiagnostics) get() DeviceDiagnostics {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	diag := DeviceDiagnostics{
+		Registration:        slices.Clone(d.registration),
+		Telemetry:           slices.Clone(d.telemetry),
+		Errors:              slices.Clone(d.errors),
+		ConsecutiveFailures: d.failures,
+		Latency:             make(map[string]DiagnosticsLatency),
+		Drift:               d.drift,
+		TimeSyncs:           slices.Clone(d.timeSyncs),
+	}
+
+	if !d.lastSuccess.IsZero() {
+		diag.LastSuccess = formatDiagnosticsTime(d.lastSuccess)
+	}
+
+	for kind, latencies := range d.latencies {
+		diag.Latency[kind] = makeDiagnosticsLatency(latencies)
+	}
+
+	return diag
+}
+
+func appendDiagnostics[T any](items []T, item T, size int) []T {
+	if size <= 0 {
+		return items
+	}
+
+	items = append(items, item)
+	if len(items) > size {
+		items = slices.Delete(items, 0, len(items)-size)
+	}
+
+	return items
+}
+
+func makeDiagnosticsLatency(latencies []time.Duration) DiagnosticsLatency {
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	percentile := func(p int) float64 {
+		if len(sorted) == 0 {
+			return 0
+		}
+
+		pos := (len(sorted) - 1) * p / 100
+
+		return float64(sorted[pos].Microseconds()) / 1000
+	}
+
+	return DiagnosticsLatency{
+		Count: len(sorted),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+	}
+}
+
+func formatDiagnosticsTime(t time.Time) string {
+	return t.Format(time.RFC1123)
+}
+
+// diagnosticsFetcher records the fetched payloads, fetch latencies and errors.
+type diagnosticsFetcher struct {
+	diag    *deviceDiagnostics
+	kind    string
+	fetcher devcore.Fetcher
+}
+
+func (f *diagnosticsFetcher) Fetch() ([]byte, error) {
+	start := time.Now()
+	buf, err := f.fetcher.Fetch()
+	f.diag.addLatency(f.kind, time.Since(start))
+
+	if err != nil {
+		return nil, f.diag.recordError(f.kind, err)
+	}
+
+	f.diag.addPayload(f.kind, buf)
+
+	return buf, nil
+}
+
+// diagnosticsTask records the result of each task run.
+type diagnosticsTask struct {
+	diag *deviceDiagnostics
+	task syssched.Task
+}
+
+func (t *diagnosticsTask) Run() error {
+	err := t.task.Run()
+	t.diag.addResult(err)
+
+	return err
+}
+
+// diagnosticsTimeVerifier records the difference between local and device UNIX time.
+type diagnosticsTimeVerifier struct {
+	diag     *deviceDiagnostics
+	clock    syscore.SystemClock
+	verifier devcore.TimeVerifier
+}
+
+func (v *diagnosticsTimeVerifier) VerifyTime(timestamp int64) bool {
+	if localTs, err := v.clock.GetTimestamp(); err == nil && timestamp >= 0 {
+		v.diag.setDrift(localTs - timestamp)
+	}
+
+	return v.verifier.VerifyTime(timestamp)
+}
+
+// diagnosticsTimeSynchronizer records the device time synchronizations.
+type diagnosticsTimeSynchronizer struct {
+	diag         *deviceDiagnostics
+	synchronizer devcore.TimeSynchronizer
+}
+
+func (s *diagnosticsTimeSynchronizer) SyncTime() error {
+	err := s.synchronizer.SyncTime()
+	s.diag.addTimeSync(err)
+
+	return err
+}
+
+// diagnosticsError is the error which is already recorded in the device diagnostics.
+type diagnosticsError struct {
+	err error
+}
+
+func (e *diagnosticsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *diagnosticsError) Unwrap() error {
+	return e.err
+}
+
+func isDiagnosticsRecorded(err error) bool {
+	var diagErr *diagnosticsError
+
+	return errors.As(err, &diagErr)
+}
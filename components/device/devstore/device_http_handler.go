@@ -0,0 +1,268 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// deviceHTTPMaxBodySize is a maximum allowed size of the device request body.
+const deviceHTTPMaxBodySize = 4 * 1024
+
+//go:embed openapi.json
+var openAPIDocument []byte
+
+type deviceHTTPCreateRequest struct {
+	URI         string            `json:"uri"`
+	Type        string            `json:"type"`
+	Desc        string            `json:"desc"`
+	TLS         DeviceTLS         `json:"tls"`
+	Credentials DeviceCredentials `json:"credentials"`
+}
+
+type deviceHTTPUpdateRequest struct {
+	URI  *string `json:"uri"`
+	Type *string `json:"type"`
+	Desc *string `json:"desc"`
+}
+
+// DeviceHTTPHandler allows to manage devices over RESTful HTTP API with JSON bodies.
+//
+// Remarks:
+//   - The device URI or ID is taken from the "device" path value of the request,
+//     URI should be escaped.
+//   - Errors are returned as JSON: {"code": 404, "message": "..."}.
+//   - ErrDeviceExist is mapped to 409, status.StatusNoData to 404,
+//     status.StatusNotSupported to 422, and status.StatusInvalidArg to 400.
+//   - HTTP methods are expected to be matched by the mux patterns.
+type DeviceHTTPHandler struct {
+	store Store
+}
+
+// NewDeviceHTTPHandler is an initialization of DeviceHTTPHandler.
+//
+// Parameters:
+//   - store to manage devices.
+func NewDeviceHTTPHandler(store Store) *DeviceHTTPHandler {
+	return &DeviceHTTPHandler{store: store}
+}
+
+// HandleCreate adds the device, POST /devices.
+func (h *DeviceHTTPHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	var req deviceHTTPCreateRequest
+	if err := decodeDeviceHTTPRequest(w, r, &req); err != nil {
+		htcore.WriteJSONError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"uri", req.URI},
+		{"type", req.Type},
+		{"desc", req.Desc},
+	} {
+		if field.value == "" {
+			htcore.WriteJSONError(w, http.StatusBadRequest,
+				fmt.Sprintf("missed `%s` field", field.name))
+
+			return
+		}
+	}
+
+	opts := DeviceOptions{
+		TLS:         req.TLS,
+		Credentials: req.Credentials,
+	}
+
+	if err := h.store.Add(req.URI, req.Type, req.Desc, opts); err != nil {
+		writeDeviceHTTPError(w, err, fmt.Sprintf("failed to add device: uri=%s", req.URI))
+
+		return
+	}
+
+	item, err := h.findDevice(req.URI)
+	if err != nil {
+		writeDeviceHTTPError(w, err, fmt.Sprintf("failed to read device: uri=%s", req.URI))
+
+		return
+	}
+
+	writeDeviceHTTPItem(w, http.StatusCreated, item)
+}
+
+// HandleList returns all added devices, GET /devices.
+func (h *DeviceHTTPHandler) HandleList(w http.ResponseWriter, _ *http.Request) {
+	items := h.store.GetDesc()
+	if items == nil {
+		items = []StoreItem{}
+	}
+
+	buf, err := json.Marshal(items)
+	if err != nil {
+		htcore.WriteJSONError(w, http.StatusInternalServerError,
+			fmt.Sprintf("failed to format JSON: %v", err))
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
+
+// HandleGet returns the device, GET /devices/{device}.
+func (h *DeviceHTTPHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	item, err := h.findDevice(r.PathValue("device"))
+	if err != nil {
+		writeDeviceHTTPError(w, err, "failed to read device")
+
+		return
+	}
+
+	writeDeviceHTTPItem(w, http.StatusOK, item)
+}
+
+// HandleUpdate changes the device URI, type or description, PATCH /devices/{device}.
+//
+// Remarks:
+//   - Omitted fields aren't changed.
+//   - Device isn't removed, only the changed parts of the device are restarted.
+func (h *DeviceHTTPHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	item, err := h.findDevice(r.PathValue("device"))
+	if err != nil {
+		writeDeviceHTTPError(w, err, "failed to update device")
+
+		return
+	}
+
+	var req deviceHTTPUpdateRequest
+	if err := decodeDeviceHTTPRequest(w, r, &req); err != nil {
+		htcore.WriteJSONError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	updated := item
+	if req.URI != nil {
+		updated.URI = *req.URI
+	}
+	if req.Type != nil {
+		updated.Type = *req.Type
+	}
+	if req.Desc != nil {
+		updated.Desc = *req.Desc
+	}
+
+	if updated.URI == "" || updated.Type == "" || updated.Desc == "" {
+		htcore.WriteJSONError(w, http.StatusBadRequest, "fields can't be empty")
+
+		return
+	}
+
+	if updated.URI != item.URI || updated.Type != item.Type || updated.Desc != item.Desc {
+		err := h.store.Update(item.URI, updated.URI, updated.Type, updated.Desc)
+		if err != nil {
+			writeDeviceHTTPError(w, err,
+				fmt.Sprintf("failed to update device: uri=%s", item.URI))
+
+			return
+		}
+	}
+
+	res, err := h.findDevice(updated.URI)
+	if err != nil {
+		writeDeviceHTTPError(w, err, "failed to read device")
+
+		return
+	}
+
+	writeDeviceHTTPItem(w, http.StatusOK, res)
+}
+
+// HandleDelete removes the device, DELETE /devices/{device}.
+func (h *DeviceHTTPHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	item, err := h.findDevice(r.PathValue("device"))
+	if err != nil {
+		writeDeviceHTTPError(w, err, "failed to remove device")
+
+		return
+	}
+
+	if err := h.store.Remove(item.URI); err != nil {
+		writeDeviceHTTPError(w, err, fmt.Sprintf("failed to remove device: uri=%s", item.URI))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleOpenAPI returns the OpenAPI document of the API.
+func (*DeviceHTTPHandler) HandleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	htcore.WriteJSON(w, openAPIDocument)
+}
+
+func (h *DeviceHTTPHandler) findDevice(device string) (StoreItem, error) {
+	if device == "" {
+		return StoreItem{}, status.StatusInvalidArg
+	}
+
+	for _, item := range h.store.GetDesc() {
+		if item.URI == device || item.ID == device {
+			return item, nil
+		}
+	}
+
+	return StoreItem{}, status.StatusNoData
+}
+
+func decodeDeviceHTTPRequest(w http.ResponseWriter, r *http.Request, req any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, deviceHTTPMaxBodySize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(req); err != nil {
+		return fmt.Errorf("failed to parse request body: %w", err)
+	}
+
+	return nil
+}
+
+func writeDeviceHTTPItem(w http.ResponseWriter, code int, item StoreItem) {
+	buf, err := json.Marshal(item)
+	if err != nil {
+		htcore.WriteJSONError(w, http.StatusInternalServerError,
+			fmt.Sprintf("failed to format JSON: %v", err))
+
+		return
+	}
+
+	htcore.WriteJSONStatus(w, code, buf)
+}
+
+func writeDeviceHTTPError(w http.ResponseWriter, err error, message string) {
+	code := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, ErrDeviceExist):
+		code = http.StatusConflict
+	case errors.Is(err, status.StatusNoData):
+		code = http.StatusNotFound
+	case errors.Is(err, status.StatusNotSupported):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(err, status.StatusInvalidArg):
+		code = http.StatusBadRequest
+	}
+
+	htcore.WriteJSONError(w, code, fmt.Sprintf("%s: %v", message, err))
+}
@@ -0,0 +1,257 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+type testDeviceHTTPStore struct {
+	items []StoreItem
+}
+
+func (s *testDeviceHTTPStore) Add(
+	uri string,
+	typ string,
+	desc string,
+	opts DeviceOptions,
+) error {
+	if strings.HasPrefix(uri, "ftp://") {
+		return status.StatusNotSupported
+	}
+
+	for _, item := range s.items {
+		if item.URI == uri {
+			return ErrDeviceExist
+		}
+	}
+
+	item := StoreItem{URI: uri, Type: typ, Desc: desc}
+	if !opts.TLS.IsZero() {
+		item.TLS = &opts.TLS
+	}
+
+	s.items = append(s.items, item)
+
+	return nil
+}
+
+func (s *testDeviceHTTPStore) Remove(uri string) error {
+	for n, item := range s.items {
+		if item.URI == uri {
+			s.items = append(s.items[:n], s.items[n+1:]...)
+
+			return nil
+		}
+	}
+
+	return status.StatusNoData
+}
+
+func (s *testDeviceHTTPStore) Update(
+	uri string,
+	newURI string,
+	typ string,
+	desc string,
+) error {
+	if strings.HasPrefix(newURI, "ftp://") {
+		return status.StatusNotSupported
+	}
+
+	pos := -1
+
+	for n, item := range s.items {
+		if item.URI == uri {
+			pos = n
+		} else if item.URI == newURI {
+			return ErrDeviceExist
+		}
+	}
+
+	if pos < 0 {
+		return status.StatusNoData
+	}
+
+	s.items[pos] = StoreItem{URI: newURI, Type: typ, Desc: desc}
+
+	return nil
+}
+
+func (s *testDeviceHTTPStore) GetDesc() []StoreItem {
+	return s.items
+}
+
+func newTestDeviceHTTPMux(store Store) *http.ServeMux {
+	handler := NewDeviceHTTPHandler(store)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/devices", handler.HandleCreate)
+	mux.HandleFunc("GET /api/v2/devices", handler.HandleList)
+	mux.HandleFunc("GET /api/v2/devices/{device}", handler.HandleGet)
+	mux.HandleFunc("PATCH /api/v2/devices/{device}", handler.HandleUpdate)
+	mux.HandleFunc("DELETE /api/v2/devices/{device}", handler.HandleDelete)
+	mux.HandleFunc("GET /api/v2/openapi.json", handler.HandleOpenAPI)
+
+	return mux
+}
+
+func doTestDeviceHTTPRequest(
+	t *testing.T,
+	mux *http.ServeMux,
+	method string,
+	path string,
+	body string,
+	res any,
+) int {
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	if res != nil && recorder.Body.Len() > 0 {
+		require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), res))
+	}
+
+	return recorder.Code
+}
+
+func TestDeviceHTTPHandlerCRUD(t *testing.T) {
+	mux := newTestDeviceHTTPMux(&testDeviceHTTPStore{})
+
+	uri := "http://bonsai-growlab.local:80/api/v1"
+	path := "/api/v2/devices/" + url.PathEscape(uri)
+
+	var item StoreItem
+	require.Equal(t, http.StatusCreated, doTestDeviceHTTPRequest(t, mux, http.MethodPost,
+		"/api/v2/devices", `{"uri":"`+uri+`","type":"bonsai-growlab","desc":"home"}`, &item))
+	require.Equal(t, StoreItem{URI: uri, Type: "bonsai-growlab", Desc: "home"}, item)
+
+	var items []StoreItem
+	require.Equal(t, http.StatusOK, doTestDeviceHTTPRequest(t, mux, http.MethodGet,
+		"/api/v2/devices", "", &items))
+	require.Equal(t, []StoreItem{item}, items)
+
+	require.Equal(t, http.StatusOK, doTestDeviceHTTPRequest(t, mux, http.MethodGet,
+		path, "", &item))
+	require.Equal(t, uri, item.URI)
+
+	require.Equal(t, http.StatusOK, doTestDeviceHTTPRequest(t, mux, http.MethodPatch,
+		path, `{"desc":"kitchen"}`, &item))
+	require.Equal(t, StoreItem{URI: uri, Type: "bonsai-growlab", Desc: "kitchen"}, item)
+
+	require.Equal(t, http.StatusNoContent, doTestDeviceHTTPRequest(t, mux,
+		http.MethodDelete, path, "", nil))
+
+	require.Equal(t, http.StatusOK, doTestDeviceHTTPRequest(t, mux, http.MethodGet,
+		"/api/v2/devices", "", &items))
+	require.Empty(t, items)
+}
+
+func TestDeviceHTTPHandlerCreateTLS(t *testing.T) {
+	mux := newTestDeviceHTTPMux(&testDeviceHTTPStore{})
+
+	uri := "https://bonsai-growlab.local:443/api/v1"
+
+	var item StoreItem
+	require.Equal(t, http.StatusCreated, doTestDeviceHTTPRequest(t, mux, http.MethodPost,
+		"/api/v2/devices", `{"uri":"`+uri+`","type":"bonsai-growlab","desc":"home",`+
+			`"tls":{"ca_path":"/etc/ca.pem","server_name":"growlab"}}`, &item))
+	require.Equal(t, &DeviceTLS{CAPath: "/etc/ca.pem", ServerName: "growlab"}, item.TLS)
+
+	require.Equal(t, http.StatusBadRequest, doTestDeviceHTTPRequest(t, mux, http.MethodPost,
+		"/api/v2/devices", `{"uri":"`+uri+`","type":"bonsai-growlab","desc":"home",`+
+			`"tls":{"ca":"/etc/ca.pem"}}`, nil))
+}
+
+func TestDeviceHTTPHandlerErrors(t *testing.T) {
+	store := &testDeviceHTTPStore{}
+	require.Nil(t, store.Add("push://0xABCD", "bonsai-growlab", "home", DeviceOptions{}))
+	require.Nil(t, store.Add("push://0xBEEF", "bonsai-growlab", "kitchen", DeviceOptions{}))
+
+	mux := newTestDeviceHTTPMux(store)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{"exist", http.MethodPost, "/api/v2/devices",
+			`{"uri":"push://0xABCD","type":"foo","desc":"bar"}`, http.StatusConflict},
+		{"not supported", http.MethodPost, "/api/v2/devices",
+			`{"uri":"ftp://foo","type":"foo","desc":"bar"}`, http.StatusUnprocessableEntity},
+		{"missed field", http.MethodPost, "/api/v2/devices",
+			`{"uri":"push://0xCAFE","type":"foo"}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/v2/devices",
+			`{"uri":"push://0xCAFE","type":"foo","desc":"bar","foo":1}`, http.StatusBadRequest},
+		{"invalid JSON", http.MethodPost, "/api/v2/devices", `{`, http.StatusBadRequest},
+		{"get not found", http.MethodGet, "/api/v2/devices/0xCAFE", "", http.StatusNotFound},
+		{"delete not found", http.MethodDelete, "/api/v2/devices/0xCAFE", "",
+			http.StatusNotFound},
+		{"update not found", http.MethodPatch, "/api/v2/devices/0xCAFE", `{}`,
+			http.StatusNotFound},
+		{"update exist", http.MethodPatch, "/api/v2/devices/push:%2F%2F0xABCD",
+			`{"uri":"push://0xBEEF"}`, http.StatusConflict},
+		{"update empty", http.MethodPatch, "/api/v2/devices/push:%2F%2F0xABCD",
+			`{"desc":""}`, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var res struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			}
+
+			require.Equal(t, test.code,
+				doTestDeviceHTTPRequest(t, mux, test.method, test.path, test.body, &res))
+			require.Equal(t, test.code, res.Code)
+			require.NotEmpty(t, res.Message)
+		})
+	}
+
+	require.Equal(t, 2, len(store.GetDesc()))
+}
+
+func TestDeviceHTTPHandlerUpdateRestore(t *testing.T) {
+	store := &testDeviceHTTPStore{}
+	require.Nil(t, store.Add("push://0xABCD", "bonsai-growlab", "home", DeviceOptions{}))
+
+	mux := newTestDeviceHTTPMux(store)
+
+	require.Equal(t, http.StatusUnprocessableEntity, doTestDeviceHTTPRequest(t, mux,
+		http.MethodPatch, "/api/v2/devices/push:%2F%2F0xABCD", `{"uri":"ftp://foo"}`, nil))
+
+	require.Equal(t, []StoreItem{{
+		URI:  "push://0xABCD",
+		Type: "bonsai-growlab",
+		Desc: "home",
+	}}, store.GetDesc())
+}
+
+func TestDeviceHTTPHandlerOpenAPI(t *testing.T) {
+	mux := newTestDeviceHTTPMux(&testDeviceHTTPStore{})
+
+	var doc struct {
+		OpenAPI string         `json:"openapi"`
+		Paths   map[string]any `json:"paths"`
+	}
+	require.Equal(t, http.StatusOK, doTestDeviceHTTPRequest(t, mux, http.MethodGet,
+		"/api/v2/openapi.json", "", &doc))
+
+	require.Equal(t, "3.0.3", doc.OpenAPI)
+	require.Contains(t, doc.Paths, "/devices")
+	require.Contains(t, doc.Paths, "/devices/{device}")
+}
@@ -0,0 +1,15 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+// DevicePauser allows to pause the device data processing.
+type DevicePauser interface {
+	// Pause pauses the data processing for the device associated with the provided URI.
+	Pause(uri string) error
+
+	// Resume resumes the data processing for the device associated with the provided URI.
+	Resume(uri string) error
+}
@@ -0,0 +1,74 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// DiagnosticsHTTPHandler allows to read the device runtime diagnostics over HTTP API.
+//
+// Remarks:
+//   - The device URI or ID is taken from the "device" path value of the request,
+//     URI should be escaped.
+type DiagnosticsHTTPHandler struct {
+	reader DiagnosticsReader
+}
+
+// NewDiagnosticsHTTPHandler is an initialization of DiagnosticsHTTPHandler.
+//
+// Parameters:
+//   - reader to read the device diagnostics.
+func NewDiagnosticsHTTPHandler(reader DiagnosticsReader) *DiagnosticsHTTPHandler {
+	return &DiagnosticsHTTPHandler{reader: reader}
+}
+
+// HandleDiagnostics returns the device runtime diagnostics.
+func (h *DiagnosticsHTTPHandler) HandleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	device := r.PathValue("device")
+	if device == "" {
+		http.Error(w, "error: missed `device` path parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	diag, err := h.reader.GetDiagnostics(device)
+	if err != nil {
+		if errors.Is(err, status.StatusNoData) {
+			http.Error(w, fmt.Sprintf("error: device not found: %s", device),
+				http.StatusNotFound)
+
+			return
+		}
+
+		http.Error(w, fmt.Sprintf("error: failed to read diagnostics: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	buf, err := json.Marshal(diag)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to format JSON: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
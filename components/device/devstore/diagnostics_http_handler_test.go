@@ -0,0 +1,143 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+)
+
+func readTestDiagnostics(
+	t *testing.T,
+	handler *DiagnosticsHTTPHandler,
+	device string,
+) (int, DeviceDiagnostics) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/devices/{device}/diagnostics", handler.HandleDiagnostics)
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+		"/api/v1/devices/"+url.PathEscape(device)+"/diagnostics", nil))
+
+	var diag DeviceDiagnostics
+	if recorder.Code == http.StatusOK {
+		require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &diag))
+	}
+
+	return recorder.Code, diag
+}
+
+func TestDiagnosticsHTTPHandlerPushDevice(t *testing.T) {
+	deviceID := "0xABCD"
+	uri := "push://" + deviceID
+	now := int64(1735732800)
+
+	clock := &testCacheStoreClock{timestamp: now}
+
+	store := newTestPushHTTPStore(t, clock, newTestDataHandlerBuilder(t))
+	store.params.Diagnostics.HistorySize = 2
+
+	require.Nil(t, store.Add(uri, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	pushHandler, err := store.GetPushHandler(deviceID)
+	require.Nil(t, err)
+
+	for _, timestamp := range []int64{now - 60, now - 70, now - 80} {
+		buf, err := json.Marshal(devcore.JSON{"timestamp": float64(timestamp)})
+		require.Nil(t, err)
+
+		err = pushHandler.HandleTelemetry(buf)
+		require.True(t, errors.Is(err, devcore.ErrInvalidTimestamp))
+	}
+
+	handler := NewDiagnosticsHTTPHandler(store)
+
+	for _, device := range []string{uri, deviceID} {
+		code, diag := readTestDiagnostics(t, handler, device)
+		require.Equal(t, http.StatusOK, code)
+
+		require.Equal(t, uri, diag.URI)
+		require.Equal(t, deviceID, diag.ID)
+		require.Equal(t, 2, len(diag.Telemetry))
+		require.Equal(t, `{"timestamp":1735732730}`, diag.Telemetry[0].Data)
+		require.Equal(t, `{"timestamp":1735732720}`, diag.Telemetry[1].Data)
+		require.Empty(t, diag.Registration)
+		require.Equal(t, 2, len(diag.Errors))
+		require.Equal(t, diagnosticsKindTelemetry, diag.Errors[0].Source)
+		require.Equal(t, 3, diag.ConsecutiveFailures)
+		require.Empty(t, diag.LastSuccess)
+		require.Equal(t, int64(80), diag.Drift)
+	}
+
+	code, _ := readTestDiagnostics(t, handler, "push://0xBEEF")
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func TestDeviceDiagnosticsLatency(t *testing.T) {
+	diag := newDeviceDiagnostics(0)
+
+	for n := 1; n <= diagnosticsLatencyWindow+10; n++ {
+		diag.addLatency(diagnosticsKindTelemetry, time.Millisecond*time.Duration(n))
+	}
+
+	diag.addPayload(diagnosticsKindTelemetry, []byte("{}"))
+	diag.addResult(nil)
+
+	res := diag.get()
+	require.Empty(t, res.Telemetry)
+	require.NotEmpty(t, res.LastSuccess)
+	require.Equal(t, DiagnosticsLatency{
+		Count: diagnosticsLatencyWindow,
+		P50:   60,
+		P90:   100,
+		P99:   109,
+	}, res.Latency[diagnosticsKindTelemetry])
+}
+
+func TestDiagnosticsHTTPHandlerErrorRecordedOnce(t *testing.T) {
+	clock := &testCacheStoreClock{}
+
+	store := newTestPushHTTPStore(t, clock, newTestDataHandlerBuilder(t))
+	store.params.HTTP.FetchInterval = time.Millisecond * 10
+	store.params.Diagnostics.HistorySize = 10
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/registration", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	require.Nil(t, store.Add(server.URL, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	handler := NewDiagnosticsHTTPHandler(store)
+
+	var diag DeviceDiagnostics
+
+	require.Eventually(t, func() bool {
+		code, res := readTestDiagnostics(t, handler, server.URL)
+		require.Equal(t, http.StatusOK, code)
+
+		diag = res
+
+		return len(diag.Errors) >= 3
+	}, time.Second*5, time.Millisecond*10)
+
+	// Fetch error is propagated to the error handler, but isn't recorded again.
+	for _, diagErr := range diag.Errors {
+		require.Equal(t, diagnosticsKindRegistration, diagErr.Source)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"errors"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// FanoutDataHandlerBuilder builds handlers that pass the device data to the
+// handlers of all underlying builders.
+//
+// Remarks:
+//   - Device data is passed to each handler, even if the previous handler fails.
+type FanoutDataHandlerBuilder struct {
+	builders []DataHandlerBuilder
+}
+
+// Add adds the builder to build the underlying handlers.
+//
+// Remarks:
+//   - Should be called before the handlers are built.
+func (b *FanoutDataHandlerBuilder) Add(builder DataHandlerBuilder) {
+	b.builders = append(b.builders, builder)
+}
+
+// BuildHandler builds the handler that passes the device data to all underlying handlers.
+func (b *FanoutDataHandlerBuilder) BuildHandler(
+	clock syscore.SystemClock,
+	deviceID string,
+	typ string,
+	desc string,
+) devcore.DataHandler {
+	handler := &fanoutDataHandler{}
+
+	for _, builder := range b.builders {
+		handler.handlers = append(handler.handlers,
+			builder.BuildHandler(clock, deviceID, typ, desc))
+	}
+
+	return handler
+}
+
+type fanoutDataHandler struct {
+	handlers []devcore.DataHandler
+}
+
+func (h *fanoutDataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	return h.handle(func(handler devcore.DataHandler) error {
+		return handler.HandleTelemetry(deviceID, js)
+	})
+}
+
+func (h *fanoutDataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	return h.handle(func(handler devcore.DataHandler) error {
+		return handler.HandleRegistration(deviceID, js)
+	})
+}
+
+func (h *fanoutDataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	return h.handle(func(handler devcore.DataHandler) error {
+		return handler.HandleStream(deviceID, measurement, js)
+	})
+}
+
+func (h *fanoutDataHandler) handle(fn func(devcore.DataHandler) error) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if err := fn(handler); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+)
+
+func TestFanoutDataHandlerBuilder(t *testing.T) {
+	failedSink := &testQueueSink{err: errors.New("failed")}
+	sink := &testQueueSink{}
+
+	builder := &FanoutDataHandlerBuilder{}
+	builder.Add(failedSink)
+	builder.Add(sink)
+
+	handler := builder.BuildHandler(&testCacheStoreClock{}, "0xABCD", "bonsai-growlab", "home")
+	require.Equal(t, []string{"0xABCD"}, failedSink.devices)
+	require.Equal(t, []string{"0xABCD"}, sink.devices)
+
+	js := devcore.JSON{"timestamp": float64(123)}
+
+	// Data is passed to all handlers, even if the previous handler fails.
+	require.NotNil(t, handler.HandleTelemetry("0xABCD", js))
+	require.NotNil(t, handler.HandleRegistration("0xABCD", js))
+	require.NotNil(t, handler.HandleStream("0xABCD", "power", js))
+
+	require.Equal(t, []testQueueData{
+		{kind: "telemetry", deviceID: "0xABCD", js: js},
+		{kind: "registration", deviceID: "0xABCD", js: js},
+		{kind: "stream", deviceID: "0xABCD", measurement: "power", js: js},
+	}, sink.data)
+}
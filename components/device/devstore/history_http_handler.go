@@ -0,0 +1,258 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// historyHTTPDefaultRange - time range of the returned data if "from" isn't provided.
+const historyHTTPDefaultRange = time.Hour * 24
+
+// HistoryHTTPHandler allows to read the stored device data over HTTP API.
+//
+// Remarks:
+//   - The device ID is taken from the "device_id" path value of the request.
+//   - Query parameters:
+//     from, to - UNIX time in seconds or RFC3339 time, the last day by default.
+//     fields - comma-separated flattened field names, all fields by default.
+//     step - aggregation step, e.g. 5m, the data isn't aggregated by default.
+//     agg - aggregation function: mean, min or max, mean by default.
+//     format - response format: json or csv, json by default.
+type HistoryHTTPHandler struct {
+	reader stcore.HistoryReader
+	clock  syscore.SystemClock
+}
+
+// NewHistoryHTTPHandler is an initialization of HistoryHTTPHandler.
+//
+// Parameters:
+//   - reader to read the stored device data.
+//   - clock to get the current UNIX time for the default time range.
+func NewHistoryHTTPHandler(
+	reader stcore.HistoryReader,
+	clock syscore.SystemClock,
+) *HistoryHTTPHandler {
+	return &HistoryHTTPHandler{
+		reader: reader,
+		clock:  clock,
+	}
+}
+
+// HandleTelemetry returns the stored device telemetry.
+func (h *HistoryHTTPHandler) HandleTelemetry(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	deviceID := r.PathValue("device_id")
+	if deviceID == "" {
+		http.Error(w, "error: missed `device_id` path parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	query, err := h.parseQuery(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: %v", err), http.StatusBadRequest)
+
+		return
+	}
+
+	query.DeviceID = deviceID
+	query.Measurement = "telemetry"
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, fmt.Sprintf("error: unsupported format: %s", format),
+			http.StatusBadRequest)
+
+		return
+	}
+
+	points, err := h.reader.ReadHistory(r.Context(), query)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to read data: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	if format == "csv" {
+		buf, err := formatHistoryCSV(points, query.Fields)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("error: failed to format CSV: %v", err),
+				http.StatusInternalServerError)
+
+			return
+		}
+
+		htcore.WriteCSV(w, buf)
+
+		return
+	}
+
+	buf, err := json.Marshal(points)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to format JSON: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
+
+func (h *HistoryHTTPHandler) parseQuery(r *http.Request) (stcore.HistoryQuery, error) {
+	values := r.URL.Query()
+
+	query := stcore.HistoryQuery{}
+
+	if s := values.Get("to"); s != "" {
+		to, err := parseHistoryTime(s)
+		if err != nil {
+			return query, fmt.Errorf("invalid `to` query parameter: %w", err)
+		}
+
+		query.To = to
+	} else {
+		now, err := h.clock.GetTimestamp()
+		if err != nil {
+			return query, fmt.Errorf("failed to get UNIX time: %w", err)
+		}
+
+		// Include the most recent data.
+		query.To = now + 1
+	}
+
+	if s := values.Get("from"); s != "" {
+		from, err := parseHistoryTime(s)
+		if err != nil {
+			return query, fmt.Errorf("invalid `from` query parameter: %w", err)
+		}
+
+		query.From = from
+	} else {
+		query.From = max(0, query.To-int64(historyHTTPDefaultRange/time.Second))
+	}
+
+	if query.From < 0 || query.From >= query.To {
+		return query, fmt.Errorf("invalid time range: from=%d to=%d", query.From, query.To)
+	}
+
+	if s := values.Get("fields"); s != "" {
+		for _, field := range strings.Split(s, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				query.Fields = append(query.Fields, field)
+			}
+		}
+	}
+
+	if s := values.Get("step"); s != "" {
+		step, err := time.ParseDuration(s)
+		if err != nil {
+			return query, fmt.Errorf("invalid `step` query parameter: %w", err)
+		}
+		if step < time.Second {
+			return query, fmt.Errorf("invalid `step` query parameter: should be at least 1s")
+		}
+
+		query.Step = step
+	}
+
+	if s := values.Get("agg"); s != "" {
+		aggregation, err := stcore.ParseAggregation(s)
+		if err != nil {
+			return query, fmt.Errorf("invalid `agg` query parameter: %w", err)
+		}
+
+		query.Aggregation = aggregation
+	}
+
+	return query, nil
+}
+
+func parseHistoryTime(s string) (int64, error) {
+	if timestamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return timestamp, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return -1, fmt.Errorf("should be UNIX time or RFC3339 time")
+	}
+
+	return t.Unix(), nil
+}
+
+// formatHistoryCSV formats the data points as CSV, with the timestamp in the
+// first column, and a column per field.
+func formatHistoryCSV(points []stcore.HistoryPoint, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		for _, point := range points {
+			for field := range point.Fields {
+				if !slices.Contains(fields, field) {
+					fields = append(fields, field)
+				}
+			}
+		}
+
+		slices.Sort(fields)
+	}
+
+	var buf bytes.Buffer
+
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(append([]string{"timestamp"}, fields...)); err != nil {
+		return nil, err
+	}
+
+	for _, point := range points {
+		record := []string{strconv.FormatInt(point.Timestamp, 10)}
+
+		for _, field := range fields {
+			record = append(record, formatHistoryValue(point.Fields[field]))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func formatHistoryValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
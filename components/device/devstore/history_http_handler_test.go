@@ -0,0 +1,131 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+type testHistoryReader struct {
+	query  stcore.HistoryQuery
+	points []stcore.HistoryPoint
+}
+
+func (r *testHistoryReader) ReadHistory(
+	_ context.Context,
+	query stcore.HistoryQuery,
+) ([]stcore.HistoryPoint, error) {
+	r.query = query
+
+	return r.points, nil
+}
+
+func getTestHistoryHTTPData(t *testing.T, handler *HistoryHTTPHandler, path string) (
+	int, string,
+) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/devices/{device_id}/telemetry", handler.HandleTelemetry)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + path)
+	require.Nil(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestHistoryHTTPHandlerDefaults(t *testing.T) {
+	reader := &testHistoryReader{points: []stcore.HistoryPoint{
+		{Timestamp: 100, Fields: map[string]any{"temperature": float64(21.5)}},
+	}}
+	handler := NewHistoryHTTPHandler(reader, &testCacheStoreClock{timestamp: 100000})
+
+	code, body := getTestHistoryHTTPData(t, handler, "/api/v1/devices/0xABCD/telemetry")
+	require.Equal(t, http.StatusOK, code)
+
+	require.Equal(t, stcore.HistoryQuery{
+		DeviceID:    "0xABCD",
+		Measurement: "telemetry",
+		From:        100001 - 24*60*60,
+		To:          100001,
+	}, reader.query)
+
+	var points []stcore.HistoryPoint
+	require.Nil(t, json.Unmarshal([]byte(body), &points))
+	require.Equal(t, reader.points, points)
+}
+
+func TestHistoryHTTPHandlerQuery(t *testing.T) {
+	reader := &testHistoryReader{}
+	handler := NewHistoryHTTPHandler(reader, &testCacheStoreClock{})
+
+	code, _ := getTestHistoryHTTPData(t, handler, "/api/v1/devices/0xABCD/telemetry"+
+		"?from=2025-01-01T00:00:00Z&to=1735693200&fields=temperature,humidity"+
+		"&step=5m&agg=max")
+	require.Equal(t, http.StatusOK, code)
+
+	require.Equal(t, stcore.HistoryQuery{
+		DeviceID:    "0xABCD",
+		Measurement: "telemetry",
+		From:        1735689600,
+		To:          1735693200,
+		Fields:      []string{"temperature", "humidity"},
+		Step:        time.Minute * 5,
+		Aggregation: stcore.AggregationMax,
+	}, reader.query)
+}
+
+func TestHistoryHTTPHandlerCSV(t *testing.T) {
+	reader := &testHistoryReader{points: []stcore.HistoryPoint{
+		{Timestamp: 100, Fields: map[string]any{"temperature": float64(21.5)}},
+		{Timestamp: 200, Fields: map[string]any{
+			"temperature": float64(22),
+			"status":      "ok",
+		}},
+	}}
+	handler := NewHistoryHTTPHandler(reader, &testCacheStoreClock{timestamp: 1000})
+
+	code, body := getTestHistoryHTTPData(t, handler,
+		"/api/v1/devices/0xABCD/telemetry?format=csv")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "timestamp,status,temperature\n100,,21.5\n200,ok,22\n", body)
+}
+
+func TestHistoryHTTPHandlerInvalidQuery(t *testing.T) {
+	handler := NewHistoryHTTPHandler(&testHistoryReader{},
+		&testCacheStoreClock{timestamp: 1000})
+
+	for _, query := range []string{
+		"from=foo",
+		"to=foo",
+		"from=200&to=100",
+		"step=foo",
+		"step=100ms",
+		"agg=median",
+		"format=xml",
+	} {
+		t.Run(query, func(t *testing.T) {
+			code, _ := getTestHistoryHTTPData(t, handler,
+				"/api/v1/devices/0xABCD/telemetry?"+query)
+			require.Equal(t, http.StatusBadRequest, code)
+		})
+	}
+}
@@ -5,15 +5,39 @@
 
 package devstore
 
-import "github.com/tendry-lab/device-hub/components/system/syscore"
+import (
+	"sync"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
 
 type logErrorHandler struct {
+	diag *deviceDiagnostics
+
+	mu   sync.Mutex
 	uri  string
 	typ  string
 	desc string
 }
 
 func (h *logErrorHandler) HandleError(err error) {
+	// Errors of the fetched and pushed data are recorded with their own source.
+	if h.diag != nil && !isDiagnosticsRecorded(err) {
+		h.diag.addError(diagnosticsKindDevice, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	syscore.LogErr.Printf("failed to handle device data: uri=%s type=%s desc=%s err=%v",
 		h.uri, h.typ, h.desc, err)
 }
+
+func (h *logErrorHandler) update(uri string, typ string, desc string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.uri = uri
+	h.typ = typ
+	h.desc = desc
+}
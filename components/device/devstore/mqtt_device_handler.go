@@ -0,0 +1,37 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"fmt"
+
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+// mqttDeviceHandler routes messages received from the MQTT broker to the push handler.
+type mqttDeviceHandler struct {
+	handler           PushHandler
+	errorHandler      syssched.ErrorHandler
+	registrationTopic string
+	telemetryTopic    string
+}
+
+func (h *mqttDeviceHandler) HandleMessage(topic string, payload []byte) {
+	var err error
+
+	switch topic {
+	case h.registrationTopic:
+		err = h.handler.HandleRegistration(payload)
+	case h.telemetryTopic:
+		err = h.handler.HandleTelemetry(payload)
+	default:
+		err = fmt.Errorf("unexpected MQTT topic: %s", topic)
+	}
+
+	if err != nil {
+		h.errorHandler.HandleError(err)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+)
+
+// ProbeReport is the result of the device probing.
+type ProbeReport struct {
+	URI string `json:"uri"`
+
+	// DeviceID - device ID from the registration data, empty if not received.
+	DeviceID string `json:"device_id,omitempty"`
+
+	// RegistrationFields - flattened fields of the registration data.
+	RegistrationFields []string `json:"registration_fields"`
+
+	// TelemetryFields - flattened fields of the telemetry data.
+	TelemetryFields []string `json:"telemetry_fields"`
+
+	// Drift - difference between local and device UNIX time, in seconds.
+	//
+	// Remarks:
+	//  - nil if the device time isn't read.
+	Drift *int64 `json:"drift,omitempty"`
+
+	// Errors - validation errors, the device can be added if empty.
+	Errors []string `json:"errors"`
+}
+
+// DeviceProber validates the device before it's added.
+type DeviceProber interface {
+	// Probe fetches the device data once, and validates it.
+	//
+	// Parameters:
+	//  - ctx - probing context.
+	//  - uri - device URI.
+	//  - typ - device type, to select the device profile.
+	//  - opts - TLS settings and credentials to access the device.
+	//
+	// Remarks:
+	//  - Device isn't added, the device data isn't persisted.
+	//  - status.StatusNotSupported is returned if the device can't be probed.
+	Probe(ctx context.Context, uri string, typ string, opts DeviceOptions) (ProbeReport, error)
+}
+
+// probeDataHandler drops the device data, since the probed device isn't added.
+type probeDataHandler struct{}
+
+func (probeDataHandler) HandleTelemetry(string, devcore.JSON) error {
+	return nil
+}
+
+func (probeDataHandler) HandleRegistration(string, devcore.JSON) error {
+	return nil
+}
+
+func (probeDataHandler) HandleStream(string, string, devcore.JSON) error {
+	return nil
+}
+
+// probeFields returns the sorted flattened fields of the latest payload.
+func probeFields(payloads []DiagnosticsPayload) []string {
+	fields := []string{}
+
+	if len(payloads) == 0 {
+		return fields
+	}
+
+	var js devcore.JSON
+	if err := json.Unmarshal([]byte(payloads[len(payloads)-1].Data), &js); err != nil {
+		return fields
+	}
+
+	for field := range devcore.FlattenJSON(js, ".") {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	return fields
+}
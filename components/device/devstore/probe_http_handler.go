@@ -0,0 +1,95 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// probeHTTPMaxBodySize is a maximum allowed size of the probe request.
+const probeHTTPMaxBodySize = 4 * 1024
+
+type probeHTTPRequest struct {
+	URI         string            `json:"uri"`
+	Type        string            `json:"type"`
+	TLS         DeviceTLS         `json:"tls"`
+	Credentials DeviceCredentials `json:"credentials"`
+}
+
+// ProbeHTTPHandler allows to validate the device over HTTP API, before it's added.
+//
+// Remarks:
+//   - The device is described with the JSON request body: {"uri": "...", "type": "..."},
+//     type is optional, it's used to select the device profile.
+//   - Optional "tls" and "credentials" fields are the same as for the added device.
+type ProbeHTTPHandler struct {
+	prober DeviceProber
+}
+
+// NewProbeHTTPHandler is an initialization of ProbeHTTPHandler.
+//
+// Parameters:
+//   - prober to validate the device.
+func NewProbeHTTPHandler(prober DeviceProber) *ProbeHTTPHandler {
+	return &ProbeHTTPHandler{prober: prober}
+}
+
+// HandleProbe validates the device and returns the probe report.
+func (h *ProbeHTTPHandler) HandleProbe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var req probeHTTPRequest
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, probeHTTPMaxBodySize))
+	if err := decoder.Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to parse request body: %v", err),
+			http.StatusBadRequest)
+
+		return
+	}
+
+	if req.URI == "" {
+		http.Error(w, "error: missed `uri` field", http.StatusBadRequest)
+
+		return
+	}
+
+	report, err := h.prober.Probe(r.Context(), req.URI, req.Type, DeviceOptions{
+		TLS:         req.TLS,
+		Credentials: req.Credentials,
+	})
+	if err != nil {
+		if errors.Is(err, status.StatusNotSupported) {
+			http.Error(w, fmt.Sprintf("error: device can't be probed: uri=%s", req.URI),
+				http.StatusBadRequest)
+		} else {
+			http.Error(w, fmt.Sprintf("error: failed to probe device: uri=%s err=%v",
+				req.URI, err), http.StatusBadRequest)
+		}
+
+		return
+	}
+
+	buf, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to format JSON: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
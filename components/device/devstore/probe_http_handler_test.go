@@ -0,0 +1,155 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/system/sysnet"
+)
+
+func newTestProbeStore(t *testing.T, db *testCacheStoreDB, now int64) *CacheStore {
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.TimeSync.MaxDriftInterval = time.Second * 5
+
+	store := NewCacheStore(
+		context.Background(),
+		&testCacheStoreClock{timestamp: now},
+		&testSystemClockReaderBuilder{},
+		newTestDataHandlerBuilder(t),
+		db,
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	t.Cleanup(func() {
+		require.Nil(t, store.Stop())
+	})
+
+	return store
+}
+
+func newTestProbeDevice(deviceTs int64) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/registration", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(deviceTs),
+		"device_id": "0xABCD",
+	}))
+	mux.Handle("/telemetry", newTestCacheStoreHTTPDataHandler(devcore.JSON{
+		"timestamp": float64(deviceTs),
+		"sensors": map[string]any{
+			"temperature": float64(23.4),
+		},
+	}))
+	mux.HandleFunc("/system/time", func(w http.ResponseWriter, _ *http.Request) {
+		writeTestProbeTimestamp(w, deviceTs)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func writeTestProbeTimestamp(w http.ResponseWriter, timestamp int64) {
+	w.Header().Set("Content-Type", "text/plain")
+
+	if _, err := w.Write([]byte(strconv.FormatInt(timestamp, 10))); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func probeTestDevice(t *testing.T, store *CacheStore, body string) (int, ProbeReport) {
+	recorder := httptest.NewRecorder()
+	NewProbeHTTPHandler(store).HandleProbe(recorder, httptest.NewRequest(
+		http.MethodPost, "/api/v1/devices/probe", strings.NewReader(body)))
+
+	var report ProbeReport
+	if recorder.Code == http.StatusOK {
+		require.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	}
+
+	return recorder.Code, report
+}
+
+func TestProbeHTTPHandlerValid(t *testing.T) {
+	now := int64(1735732800)
+
+	server := newTestProbeDevice(now - 1)
+	defer server.Close()
+
+	db := newTestCacheStoreDB()
+	store := newTestProbeStore(t, db, now)
+
+	code, report := probeTestDevice(t, store, `{"uri":"`+server.URL+`"}`)
+	require.Equal(t, http.StatusOK, code)
+
+	require.Equal(t, server.URL, report.URI)
+	require.Equal(t, "0xABCD", report.DeviceID)
+	require.Equal(t, []string{"device_id", "timestamp"}, report.RegistrationFields)
+	require.Equal(t, []string{"sensors.temperature", "timestamp"}, report.TelemetryFields)
+	require.NotNil(t, report.Drift)
+	require.Equal(t, int64(1), *report.Drift)
+	require.Empty(t, report.Errors)
+
+	require.Equal(t, 0, db.count())
+	require.Empty(t, store.GetDesc())
+}
+
+func TestProbeHTTPHandlerInvalidTimestamp(t *testing.T) {
+	now := int64(1735732800)
+
+	server := newTestProbeDevice(now - 3600)
+	defer server.Close()
+
+	store := newTestProbeStore(t, newTestCacheStoreDB(), now)
+
+	code, report := probeTestDevice(t, store, `{"uri":"`+server.URL+`"}`)
+	require.Equal(t, http.StatusOK, code)
+
+	require.Equal(t, "0xABCD", report.DeviceID)
+	require.Equal(t, int64(3600), *report.Drift)
+	require.Equal(t, 1, len(report.Errors))
+	require.Contains(t, report.Errors[0], devcore.ErrInvalidTimestamp.Error())
+}
+
+func TestProbeHTTPHandlerUnreachable(t *testing.T) {
+	server := newTestProbeDevice(0)
+	server.Close()
+
+	store := newTestProbeStore(t, newTestCacheStoreDB(), 0)
+
+	code, report := probeTestDevice(t, store, `{"uri":"`+server.URL+`"}`)
+	require.Equal(t, http.StatusOK, code)
+
+	require.Empty(t, report.DeviceID)
+	require.Empty(t, report.RegistrationFields)
+	require.Nil(t, report.Drift)
+	require.Equal(t, 2, len(report.Errors))
+}
+
+func TestProbeHTTPHandlerInvalidRequest(t *testing.T) {
+	store := newTestProbeStore(t, newTestCacheStoreDB(), 0)
+
+	for _, body := range []string{
+		``,
+		`{"type":"bonsai-growlab"}`,
+		`{"uri":"push://0xABCD"}`,
+		`{"uri":"http://localhost"}`,
+	} {
+		code, _ := probeTestDevice(t, store, body)
+		require.Equal(t, http.StatusBadRequest, code, body)
+	}
+}
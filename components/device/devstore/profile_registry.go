@@ -0,0 +1,225 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+)
+
+// HTTPEndpoint is a device HTTP resource.
+type HTTPEndpoint struct {
+	// Path - path relative to the device URI, e.g. /telemetry.
+	Path string
+
+	// Method - HTTP method, e.g. GET or POST.
+	Method string
+}
+
+// HTTPStream is an additional device data stream, fetched independently from telemetry.
+type HTTPStream struct {
+	HTTPEndpoint
+
+	// Name - unique stream name, e.g. diagnostics.
+	Name string
+
+	// Interval - how often to fetch the stream data.
+	//
+	// Remarks:
+	//  - Device fetch interval is used if zero.
+	Interval time.Duration
+
+	// Measurement - where the stream data should be stored.
+	//
+	// Remarks:
+	//  - Stream name is used if empty.
+	Measurement string
+}
+
+// DeviceProfile describes how to communicate with the devices of a particular type.
+type DeviceProfile struct {
+	HTTP struct {
+		// Registration - endpoint to fetch the device registration data.
+		Registration HTTPEndpoint
+
+		// Telemetry - endpoint to fetch the device telemetry data.
+		Telemetry HTTPEndpoint
+
+		// RegistrationInterval - how often to fetch the device registration data.
+		//
+		// Remarks:
+		//  - Store default interval is used if zero.
+		RegistrationInterval time.Duration
+
+		// Streams - additional device data streams.
+		Streams []HTTPStream
+
+		// TimePath - path to get/set the device UNIX time in seconds.
+		//
+		// Remarks:
+		//  - Automatic time synchronization is disabled for the device if empty.
+		TimePath string
+	}
+
+	// Schema - where the well-known fields are located in the device data.
+	Schema devcore.DataSchema
+
+	// TLS - TLS settings of the https:// devices, overridden by the device settings.
+	TLS DeviceTLS
+}
+
+// DefaultDeviceProfile returns the profile of the control-components firmware.
+func DefaultDeviceProfile() DeviceProfile {
+	profile := DeviceProfile{}
+	profile.HTTP.Registration = HTTPEndpoint{Path: "/registration", Method: http.MethodGet}
+	profile.HTTP.Telemetry = HTTPEndpoint{Path: "/telemetry", Method: http.MethodGet}
+	profile.HTTP.TimePath = "/system/time"
+	profile.Schema = devcore.DefaultDataSchema()
+
+	return profile
+}
+
+// Validate ensures the profile is correctly configured.
+func (p *DeviceProfile) Validate() error {
+	if err := p.HTTP.Registration.validate(); err != nil {
+		return fmt.Errorf("invalid registration endpoint: %w", err)
+	}
+
+	if err := p.HTTP.Telemetry.validate(); err != nil {
+		return fmt.Errorf("invalid telemetry endpoint: %w", err)
+	}
+
+	if p.HTTP.RegistrationInterval < 0 {
+		return fmt.Errorf("invalid registration interval: should be non-negative")
+	}
+
+	names := make(map[string]struct{})
+
+	for _, stream := range p.HTTP.Streams {
+		if err := stream.validate(); err != nil {
+			return fmt.Errorf("invalid stream: name=%s err=%w", stream.Name, err)
+		}
+
+		if _, ok := names[stream.Name]; ok {
+			return fmt.Errorf("duplicate stream: name=%s", stream.Name)
+		}
+
+		names[stream.Name] = struct{}{}
+	}
+
+	if p.HTTP.TimePath != "" && !strings.HasPrefix(p.HTTP.TimePath, "/") {
+		return fmt.Errorf("invalid time path: should start with /")
+	}
+
+	if err := p.Schema.Validate(); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return nil
+}
+
+func (e *HTTPEndpoint) validate() error {
+	if !strings.HasPrefix(e.Path, "/") {
+		return fmt.Errorf("path should start with /")
+	}
+
+	if e.Method != http.MethodGet && e.Method != http.MethodPost {
+		return fmt.Errorf("unsupported method: %s", e.Method)
+	}
+
+	return nil
+}
+
+// GetMeasurement returns where the stream data should be stored.
+func (s *HTTPStream) GetMeasurement() string {
+	if s.Measurement != "" {
+		return s.Measurement
+	}
+
+	return s.Name
+}
+
+func (s *HTTPStream) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("empty name")
+	}
+
+	if err := s.HTTPEndpoint.validate(); err != nil {
+		return err
+	}
+
+	if s.Interval < 0 {
+		return fmt.Errorf("interval should be non-negative")
+	}
+
+	// Stream data shouldn't be mixed with the primary device data.
+	if measurement := s.GetMeasurement(); measurement == "telemetry" ||
+		measurement == "registration" {
+		return fmt.Errorf("reserved measurement: %s", measurement)
+	}
+
+	return nil
+}
+
+// ProfileRegistry holds device profiles, keyed by the device type.
+type ProfileRegistry struct {
+	mu       sync.RWMutex
+	profiles map[string]DeviceProfile
+}
+
+// NewProfileRegistry is an initialization of ProfileRegistry.
+func NewProfileRegistry() *ProfileRegistry {
+	return &ProfileRegistry{
+		profiles: make(map[string]DeviceProfile),
+	}
+}
+
+// Register registers the profile for the device type.
+//
+// Remarks:
+//   - Existing profile for the same type is replaced.
+func (r *ProfileRegistry) Register(typ string, profile DeviceProfile) error {
+	if typ == "" {
+		return fmt.Errorf("empty device type")
+	}
+
+	if err := profile.Validate(); err != nil {
+		return fmt.Errorf("invalid profile: type=%s err=%w", typ, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.profiles[typ] = profile
+
+	return nil
+}
+
+// Get returns the profile for the device type.
+//
+// Remarks:
+//   - DefaultDeviceProfile() is returned if the profile for the type isn't registered.
+//   - Can be called on the nil registry.
+func (r *ProfileRegistry) Get(typ string) DeviceProfile {
+	if r == nil {
+		return DefaultDeviceProfile()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, ok := r.profiles[typ]
+	if !ok {
+		return DefaultDeviceProfile()
+	}
+
+	return profile
+}
@@ -0,0 +1,99 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileRegistryGet(t *testing.T) {
+	var nilRegistry *ProfileRegistry
+	require.Equal(t, DefaultDeviceProfile(), nilRegistry.Get("foo"))
+
+	registry := NewProfileRegistry()
+	require.Equal(t, DefaultDeviceProfile(), registry.Get("foo"))
+
+	profile := DefaultDeviceProfile()
+	profile.HTTP.Telemetry = HTTPEndpoint{Path: "/api/data", Method: http.MethodPost}
+	profile.HTTP.TimePath = ""
+	profile.Schema.IDPath = "meta.id"
+
+	require.Nil(t, registry.Register("foo", profile))
+	require.Equal(t, profile, registry.Get("foo"))
+	require.Equal(t, DefaultDeviceProfile(), registry.Get("bar"))
+}
+
+func TestProfileRegistryRegisterInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		update func(p *DeviceProfile)
+	}{
+		{"relative registration path", func(p *DeviceProfile) {
+			p.HTTP.Registration.Path = "registration"
+		}},
+		{"unsupported telemetry method", func(p *DeviceProfile) {
+			p.HTTP.Telemetry.Method = http.MethodDelete
+		}},
+		{"relative time path", func(p *DeviceProfile) {
+			p.HTTP.TimePath = "system/time"
+		}},
+		{"empty ID path", func(p *DeviceProfile) {
+			p.Schema.IDPath = ""
+		}},
+		{"negative registration interval", func(p *DeviceProfile) {
+			p.HTTP.RegistrationInterval = -time.Second
+		}},
+		{"empty stream name", func(p *DeviceProfile) {
+			p.HTTP.Streams = []HTTPStream{{
+				HTTPEndpoint: HTTPEndpoint{Path: "/diagnostics", Method: http.MethodGet},
+			}}
+		}},
+		{"duplicate stream name", func(p *DeviceProfile) {
+			stream := HTTPStream{
+				Name:         "diagnostics",
+				HTTPEndpoint: HTTPEndpoint{Path: "/diagnostics", Method: http.MethodGet},
+			}
+			p.HTTP.Streams = []HTTPStream{stream, stream}
+		}},
+		{"relative stream path", func(p *DeviceProfile) {
+			p.HTTP.Streams = []HTTPStream{{
+				Name:         "diagnostics",
+				HTTPEndpoint: HTTPEndpoint{Path: "diagnostics", Method: http.MethodGet},
+			}}
+		}},
+		{"negative stream interval", func(p *DeviceProfile) {
+			p.HTTP.Streams = []HTTPStream{{
+				Name:         "diagnostics",
+				HTTPEndpoint: HTTPEndpoint{Path: "/diagnostics", Method: http.MethodGet},
+				Interval:     -time.Second,
+			}}
+		}},
+		{"reserved stream measurement", func(p *DeviceProfile) {
+			p.HTTP.Streams = []HTTPStream{{
+				Name:         "diagnostics",
+				HTTPEndpoint: HTTPEndpoint{Path: "/diagnostics", Method: http.MethodGet},
+				Measurement:  "telemetry",
+			}}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile := DefaultDeviceProfile()
+			test.update(&profile)
+
+			registry := NewProfileRegistry()
+			require.NotNil(t, registry.Register("foo", profile))
+			require.Equal(t, DefaultDeviceProfile(), registry.Get("foo"))
+		})
+	}
+
+	require.NotNil(t, NewProfileRegistry().Register("", DefaultDeviceProfile()))
+}
@@ -0,0 +1,70 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+// PushHandler handles data pushed by the device.
+type PushHandler interface {
+	// HandleRegistration handles raw registration data pushed by the device.
+	//
+	// Remarks:
+	//  - devcore.ErrInvalidTimestamp is returned if the data timestamp isn't valid.
+	HandleRegistration(buf []byte) error
+
+	// HandleTelemetry handles raw telemetry data pushed by the device.
+	//
+	// Remarks:
+	//  - devcore.ErrInvalidTimestamp is returned if the data timestamp isn't valid.
+	HandleTelemetry(buf []byte) error
+}
+
+// PushHandlerResolver resolves the push handler for the device.
+type PushHandlerResolver interface {
+	// GetPushHandler returns the push handler for the device with the provided ID.
+	//
+	// Remarks:
+	//  - status.StatusNoData is returned if the push device with the provided ID isn't added.
+	GetPushHandler(deviceID string) (PushHandler, error)
+}
+
+// pushDeviceHandler notifies the device is alive each time the pushed data is handled,
+// and records the pushed data in the device diagnostics.
+type pushDeviceHandler struct {
+	device   *devcore.PushDevice
+	notifier syssched.AliveNotifier
+	diag     *deviceDiagnostics
+}
+
+func (h *pushDeviceHandler) HandleRegistration(buf []byte) error {
+	return h.handle(diagnosticsKindRegistration, buf, h.device.HandleRegistration)
+}
+
+func (h *pushDeviceHandler) HandleTelemetry(buf []byte) error {
+	return h.handle(diagnosticsKindTelemetry, buf, h.device.HandleTelemetry)
+}
+
+func (h *pushDeviceHandler) handle(
+	kind string,
+	buf []byte,
+	handle func(buf []byte) error,
+) error {
+	h.diag.addPayload(kind, buf)
+
+	err := handle(buf)
+	h.diag.addResult(err)
+
+	if err != nil {
+		return h.diag.recordError(kind, err)
+	}
+
+	h.notifier.NotifyAlive()
+
+	return nil
+}
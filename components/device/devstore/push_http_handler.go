@@ -0,0 +1,133 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// pushHTTPMaxBodySize is a maximum allowed size of the data pushed by the device.
+const pushHTTPMaxBodySize = 64 * 1024
+
+// PushHTTPHandler allows devices to push their data over HTTP API.
+//
+// Remarks:
+//   - The device ID is taken from the "device_id" path value of the request.
+//   - If the pushed data has an invalid timestamp, the 409 status code is returned,
+//     with the current UNIX time of the hub in the response body, so the device
+//     can correct its time and push the data again.
+type PushHTTPHandler struct {
+	resolver PushHandlerResolver
+	clock    syscore.SystemClock
+}
+
+// NewPushHTTPHandler is an initialization of PushHTTPHandler.
+//
+// Parameters:
+//   - resolver to resolve the push handler for the device.
+//   - clock to report the current UNIX time to the device.
+func NewPushHTTPHandler(
+	resolver PushHandlerResolver,
+	clock syscore.SystemClock,
+) *PushHTTPHandler {
+	return &PushHTTPHandler{
+		resolver: resolver,
+		clock:    clock,
+	}
+}
+
+// HandleRegistration handles the registration data pushed by the device.
+func (h *PushHTTPHandler) HandleRegistration(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, PushHandler.HandleRegistration)
+}
+
+// HandleTelemetry handles the telemetry data pushed by the device.
+func (h *PushHTTPHandler) HandleTelemetry(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, PushHandler.HandleTelemetry)
+}
+
+func (h *PushHTTPHandler) handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	fn func(PushHandler, []byte) error,
+) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	deviceID := r.PathValue("device_id")
+	if deviceID == "" {
+		http.Error(w, "error: missed `device_id` path parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	handler, err := h.resolver.GetPushHandler(deviceID)
+	if err != nil {
+		if errors.Is(err, status.StatusNoData) {
+			http.Error(w, fmt.Sprintf("error: unknown device: id=%s", deviceID),
+				http.StatusNotFound)
+		} else {
+			http.Error(w, fmt.Sprintf("error: failed to resolve device: id=%s err=%v",
+				deviceID, err), http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	buf, err := io.ReadAll(http.MaxBytesReader(w, r.Body, pushHTTPMaxBodySize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to read request body: %v", err),
+			http.StatusBadRequest)
+
+		return
+	}
+
+	if err := fn(handler, buf); err != nil {
+		if errors.Is(err, devcore.ErrInvalidTimestamp) {
+			h.writeTimestamp(w)
+		} else {
+			http.Error(w, fmt.Sprintf("error: failed to handle device data: id=%s err=%v",
+				deviceID, err), http.StatusBadRequest)
+		}
+
+		return
+	}
+
+	htcore.WriteText(w, "OK")
+}
+
+func (h *PushHTTPHandler) writeTimestamp(w http.ResponseWriter) {
+	timestamp, err := h.clock.GetTimestamp()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to get UNIX time: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	text := strconv.FormatInt(timestamp, 10)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(text)))
+
+	w.WriteHeader(http.StatusConflict)
+
+	if _, err := w.Write([]byte(text)); err != nil {
+		syscore.LogErr.Printf("failed to write HTTP response: %v", err)
+	}
+}
@@ -0,0 +1,246 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysnet"
+)
+
+func newTestPushHTTPStore(
+	t *testing.T,
+	clock syscore.SystemClock,
+	handlerBuilder DataHandlerBuilder,
+) *CacheStore {
+	storeParams := CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = time.Millisecond * 100
+	storeParams.HTTP.FetchTimeout = time.Millisecond * 100
+	storeParams.TimeSync.RestoreInterval = time.Millisecond * 100
+	storeParams.TimeSync.MaxDriftInterval = time.Second * 5
+
+	store := NewCacheStore(
+		context.Background(),
+		clock,
+		&testSystemClockReaderBuilder{},
+		handlerBuilder,
+		newTestCacheStoreDB(),
+		sysnet.NewResolveStore(),
+		storeParams,
+	)
+	t.Cleanup(func() {
+		require.Nil(t, store.Stop())
+	})
+
+	return store
+}
+
+func newTestPushHTTPServer(handler *PushHTTPHandler) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/ingest/{device_id}/registration", handler.HandleRegistration)
+	mux.HandleFunc("/api/v1/ingest/{device_id}/telemetry", handler.HandleTelemetry)
+
+	return httptest.NewServer(mux)
+}
+
+func pushTestHTTPData(
+	t *testing.T,
+	server *httptest.Server,
+	path string,
+	data devcore.JSON,
+) (int, string) {
+	buf, err := json.Marshal(data)
+	require.Nil(t, err)
+
+	resp, err := http.Post(server.URL+path, "application/json", bytes.NewReader(buf))
+	require.Nil(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.Nil(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestPushHTTPHandler(t *testing.T) {
+	deviceID := "0xABCD"
+	now := int64(1735732800)
+
+	clock := &testCacheStoreClock{timestamp: now}
+	handlerBuilder := newTestDataHandlerBuilder(t)
+
+	store := newTestPushHTTPStore(t, clock, handlerBuilder)
+	require.Nil(t, store.Add("push://"+deviceID, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	server := newTestPushHTTPServer(NewPushHTTPHandler(store, clock))
+	defer server.Close()
+
+	registrationData := make(devcore.JSON)
+	registrationData["timestamp"] = float64(now)
+	registrationData["device_id"] = deviceID
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(now)
+	telemetryData["temperature"] = float64(123.222)
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second)
+	defer cancelFunc()
+
+	registrationCh := make(chan devcore.JSON)
+	telemetryCh := make(chan devcore.JSON)
+
+	go func() {
+		handler := handlerBuilder.getHandler(ctx, deviceID)
+
+		registrationCh <- <-handler.registration
+		telemetryCh <- <-handler.telemetry
+	}()
+
+	// The data handler drops the data if nobody is waiting for it, repeat until received.
+	require.Eventually(t, func() bool {
+		code, body := pushTestHTTPData(t, server,
+			"/api/v1/ingest/"+deviceID+"/registration", registrationData)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "OK", body)
+
+		select {
+		case js := <-registrationCh:
+			require.True(t, maps.Equal(registrationData, js))
+			return true
+		case <-time.After(time.Millisecond * 10):
+			return false
+		}
+	}, time.Second, time.Millisecond*10)
+
+	require.Eventually(t, func() bool {
+		code, body := pushTestHTTPData(t, server,
+			"/api/v1/ingest/"+deviceID+"/telemetry", telemetryData)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "OK", body)
+
+		select {
+		case js := <-telemetryCh:
+			require.True(t, maps.Equal(telemetryData, js))
+			return true
+		case <-time.After(time.Millisecond * 10):
+			return false
+		}
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestPushHTTPHandlerStaleTimestamp(t *testing.T) {
+	deviceID := "0xABCD"
+	now := int64(1735732800)
+
+	clock := &testCacheStoreClock{timestamp: now}
+
+	store := newTestPushHTTPStore(t, clock, newTestDataHandlerBuilder(t))
+	require.Nil(t, store.Add("push://"+deviceID, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	server := newTestPushHTTPServer(NewPushHTTPHandler(store, clock))
+	defer server.Close()
+
+	for _, timestamp := range []float64{-1, float64(now - 60)} {
+		telemetryData := make(devcore.JSON)
+		telemetryData["timestamp"] = timestamp
+
+		code, body := pushTestHTTPData(t, server,
+			"/api/v1/ingest/"+deviceID+"/telemetry", telemetryData)
+		require.Equal(t, http.StatusConflict, code)
+		require.Equal(t, "1735732800", body)
+	}
+}
+
+func TestPushHTTPHandlerAliveNotify(t *testing.T) {
+	deviceID := "0xABCD"
+	now := int64(1735732800)
+
+	clock := &testCacheStoreClock{timestamp: now}
+
+	store := newTestPushHTTPStore(t, clock, newTestDataHandlerBuilder(t))
+
+	aliveCh := make(chan string, 1)
+	store.SetAliveMonitor(&testCacheStoreAliveMonitor{aliveCh: aliveCh})
+
+	require.Nil(t, store.Add("push://"+deviceID, "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	server := newTestPushHTTPServer(NewPushHTTPHandler(store, clock))
+	defer server.Close()
+
+	telemetryData := make(devcore.JSON)
+	telemetryData["timestamp"] = float64(-1)
+
+	code, _ := pushTestHTTPData(t, server,
+		"/api/v1/ingest/"+deviceID+"/telemetry", telemetryData)
+	require.Equal(t, http.StatusConflict, code)
+
+	select {
+	case <-aliveCh:
+		require.FailNow(t, "device with invalid data shouldn't be alive")
+	default:
+	}
+
+	telemetryData["timestamp"] = float64(now)
+
+	code, _ = pushTestHTTPData(t, server,
+		"/api/v1/ingest/"+deviceID+"/telemetry", telemetryData)
+	require.Equal(t, http.StatusOK, code)
+
+	select {
+	case uri := <-aliveCh:
+		require.Equal(t, "push://"+deviceID, uri)
+	case <-time.After(time.Second):
+		require.FailNow(t, "device isn't alive")
+	}
+}
+
+func TestPushHTTPHandlerInvalidRequest(t *testing.T) {
+	deviceID := "0xABCD"
+	now := int64(1735732800)
+
+	clock := &testCacheStoreClock{timestamp: now}
+
+	store := newTestPushHTTPStore(t, clock, newTestDataHandlerBuilder(t))
+	require.Nil(t, store.Add("push://"+deviceID, "test-type", "foo-bar-baz", DeviceOptions{}))
+	require.Nil(t,
+		store.Add("http://127.0.0.1:1/api/v1", "test-type", "foo-bar-baz", DeviceOptions{}))
+
+	server := newTestPushHTTPServer(NewPushHTTPHandler(store, clock))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v1/ingest/" + deviceID + "/telemetry")
+	require.Nil(t, err)
+	require.Nil(t, resp.Body.Close())
+	require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	code, _ := pushTestHTTPData(t, server, "/api/v1/ingest/0xDCBA/telemetry",
+		devcore.JSON{"timestamp": float64(now)})
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, _ = pushTestHTTPData(t, server, "/api/v1/ingest/"+deviceID+"/registration",
+		devcore.JSON{"timestamp": float64(now), "device_id": "0xDCBA"})
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = pushTestHTTPData(t, server, "/api/v1/ingest/"+deviceID+"/telemetry",
+		devcore.JSON{"temperature": float64(123)})
+	require.Equal(t, http.StatusBadRequest, code)
+
+	_, err = store.GetPushHandler("")
+	require.Equal(t, status.StatusNoData, err)
+}
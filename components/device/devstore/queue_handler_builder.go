@@ -0,0 +1,334 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+const (
+	queueKindTelemetry    = "telemetry"
+	queueKindRegistration = "registration"
+	queueKindStream       = "stream"
+)
+
+type queueEntry struct {
+	DeviceID    string       `json:"device_id"`
+	Type        string       `json:"type"`
+	Desc        string       `json:"desc"`
+	Kind        string       `json:"kind"`
+	Measurement string       `json:"measurement,omitempty"`
+	Data        devcore.JSON `json:"data"`
+}
+
+// QueueHandlerBuilderParams provides various configuration options for
+// QueueHandlerBuilder.
+type QueueHandlerBuilderParams struct {
+	// MaxAttempts - how many times to replay the entry before it's dropped.
+	//
+	// Remarks:
+	//  - Entry is replayed until it's expired by the queue if zero.
+	//  - All failures are counted, including the ones caused by the unreachable
+	//    handler, e.g. while the time-series database is offline, so the non-zero
+	//    value drops the buffered data during the long outage.
+	MaxAttempts int
+}
+
+// QueueHandlerBuilder buffers the device data in the persistent queue if the data
+// can't be handled by the underlying handler, e.g. if the time-series database
+// is unreachable, and replays the buffered data once the handler recovers.
+//
+// Remarks:
+//   - Once the queue isn't empty, all device data is queued, to keep the data order.
+//   - Buffered data is replayed in the timestamp order on each Run() call.
+//   - If the underlying builder implements FallbackHandlerBuilder, the data which
+//     fails to be handled after the handler has returned, e.g. the failed batch,
+//     is buffered too.
+//   - Data without a valid timestamp isn't buffered.
+//   - Data rejected by the underlying handler with status.StatusInvalidArg isn't
+//     buffered, and the rejected entry is dropped, since handling it again won't
+//     succeed.
+//   - Other replay failures, e.g. while the handler is unreachable, keep the entry
+//     until it's expired by the queue, unless the maximum number of attempts is set.
+type QueueHandlerBuilder struct {
+	builder DataHandlerBuilder
+	queue   stcore.Queue
+	params  QueueHandlerBuilderParams
+
+	// Accessed only by Run().
+	//
+	// Remarks:
+	//  - Handlers are built for the replayed devices, and are dropped once the queue
+	//    is drained, so the handlers of the removed devices aren't kept.
+	//  - Replay attempts are counted for the front entry.
+	handlers   map[string]devcore.DataHandler
+	failedItem stcore.QueueItem
+	attempts   int
+}
+
+// NewQueueHandlerBuilder is an initialization of QueueHandlerBuilder.
+//
+// Parameters:
+//   - builder to build the underlying data handlers.
+//   - queue to buffer the device data.
+//   - params - various replaying configuration options.
+func NewQueueHandlerBuilder(
+	builder DataHandlerBuilder,
+	queue stcore.Queue,
+	params QueueHandlerBuilderParams,
+) *QueueHandlerBuilder {
+	return &QueueHandlerBuilder{
+		builder:  builder,
+		queue:    queue,
+		params:   params,
+		handlers: make(map[string]devcore.DataHandler),
+	}
+}
+
+// BuildHandler builds the data handler that buffers the data if the underlying
+// handler fails.
+func (b *QueueHandlerBuilder) BuildHandler(
+	clock syscore.SystemClock,
+	deviceID string,
+	typ string,
+	desc string,
+) devcore.DataHandler {
+	return &queueDataHandler{
+		builder: b,
+		handler: b.buildHandler(clock, deviceID, typ, desc),
+		clock:   clock,
+		typ:     typ,
+		desc:    desc,
+	}
+}
+
+// Run replays the buffered data.
+//
+// Remarks:
+//   - Replaying is stopped on the first failure, and is continued on the next call,
+//     unless the failed entry is dropped.
+func (b *QueueHandlerBuilder) Run() error {
+	replayed := 0
+
+	for {
+		item, err := b.queue.Front()
+		if err != nil {
+			if err == status.StatusNoData {
+				clear(b.handlers)
+
+				if replayed > 0 {
+					syscore.LogInf.Printf("queue-handler: data replayed: count=%d", replayed)
+				}
+
+				return nil
+			}
+
+			return fmt.Errorf("queue-handler: failed to read queue: %w", err)
+		}
+
+		var entry queueEntry
+
+		if err := json.Unmarshal(item.Value, &entry); err != nil {
+			syscore.LogErr.Printf("queue-handler: drop invalid entry: err=%v", err)
+		} else if err := handleQueueEntry(b.getHandler(entry), entry); err != nil {
+			if !b.shouldDrop(item, err) {
+				return fmt.Errorf("queue-handler: failed to replay data: device_id=%s"+
+					" kind=%s attempts=%d pending=%d err=%w", entry.DeviceID, entry.Kind,
+					b.attempts, b.queue.Stats().Length, err)
+			}
+
+			syscore.LogErr.Printf("queue-handler: drop entry: device_id=%s kind=%s"+
+				" timestamp=%d attempts=%d err=%v", entry.DeviceID, entry.Kind,
+				item.Timestamp, b.attempts, err)
+		}
+
+		if err := b.queue.Remove(item); err != nil {
+			return fmt.Errorf("queue-handler: failed to remove entry: %w", err)
+		}
+
+		b.failedItem = stcore.QueueItem{}
+		b.attempts = 0
+
+		replayed++
+	}
+}
+
+// shouldDrop counts the failed replay attempt of the front entry, and returns true
+// if the entry shouldn't be replayed anymore.
+//
+// Remarks:
+//   - Rejected entry is dropped immediately, other entries are dropped only after
+//     the maximum number of attempts, if it's set.
+func (b *QueueHandlerBuilder) shouldDrop(item stcore.QueueItem, err error) bool {
+	if item.Timestamp == b.failedItem.Timestamp &&
+		bytes.Equal(item.Value, b.failedItem.Value) {
+		b.attempts++
+	} else {
+		b.failedItem = item
+		b.attempts = 1
+	}
+
+	if errors.Is(err, status.StatusInvalidArg) {
+		return true
+	}
+
+	return b.params.MaxAttempts > 0 && b.attempts >= b.params.MaxAttempts
+}
+
+// HandleError logs the replaying error.
+func (*QueueHandlerBuilder) HandleError(err error) {
+	syscore.LogWrn.Printf("%v", err)
+}
+
+func (b *QueueHandlerBuilder) getHandler(entry queueEntry) devcore.DataHandler {
+	handler, ok := b.handlers[entry.DeviceID]
+	if !ok {
+		// The device clock isn't used, it's already updated when the data is buffered,
+		// and the device may be not added yet, e.g. after the restart.
+		handler = b.buildHandler(
+			&queueSystemClock{}, entry.DeviceID, entry.Type, entry.Desc)
+		b.handlers[entry.DeviceID] = handler
+	}
+
+	return handler
+}
+
+// buildHandler builds the underlying handler, data failed to be handled after the
+// handler has returned is buffered as well.
+func (b *QueueHandlerBuilder) buildHandler(
+	clock syscore.SystemClock,
+	deviceID string,
+	typ string,
+	desc string,
+) devcore.DataHandler {
+	builder, ok := b.builder.(FallbackHandlerBuilder)
+	if !ok {
+		return b.builder.BuildHandler(clock, deviceID, typ, desc)
+	}
+
+	return builder.BuildFallbackHandler(clock, deviceID, typ, desc, &queueDataHandler{
+		builder: b,
+		clock:   clock,
+		typ:     typ,
+		desc:    desc,
+	})
+}
+
+func (b *QueueHandlerBuilder) handle(
+	handler devcore.DataHandler,
+	clock syscore.SystemClock,
+	entry queueEntry,
+) error {
+	if handler != nil && b.queue.Stats().Length == 0 {
+		err := handleQueueEntry(handler, entry)
+		if err == nil {
+			return nil
+		}
+
+		// Rejected data won't be accepted after the replay either.
+		if errors.Is(err, status.StatusInvalidArg) {
+			return err
+		}
+
+		syscore.LogWrn.Printf("queue-handler: failed to handle data, buffering:"+
+			" device_id=%s kind=%s err=%v", entry.DeviceID, entry.Kind, err)
+	}
+
+	ts, ok := entry.Data["timestamp"].(float64)
+	if !ok || ts <= 0 {
+		return fmt.Errorf("queue-handler: invalid timestamp: device_id=%s kind=%s",
+			entry.DeviceID, entry.Kind)
+	}
+
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("queue-handler: failed to format entry: %w", err)
+	}
+
+	if err := b.queue.Push(int64(ts), buf); err != nil {
+		return fmt.Errorf("queue-handler: failed to push entry: %w", err)
+	}
+
+	return clock.SetTimestamp(int64(ts))
+}
+
+func handleQueueEntry(handler devcore.DataHandler, entry queueEntry) error {
+	switch entry.Kind {
+	case queueKindTelemetry:
+		return handler.HandleTelemetry(entry.DeviceID, entry.Data)
+	case queueKindRegistration:
+		return handler.HandleRegistration(entry.DeviceID, entry.Data)
+	case queueKindStream:
+		return handler.HandleStream(entry.DeviceID, entry.Measurement, entry.Data)
+	default:
+		return fmt.Errorf("%w: unknown data kind: %s", status.StatusInvalidArg, entry.Kind)
+	}
+}
+
+// queueDataHandler buffers the data if the underlying handler fails, the data is
+// always buffered if the underlying handler is nil.
+type queueDataHandler struct {
+	builder *QueueHandlerBuilder
+	handler devcore.DataHandler
+	clock   syscore.SystemClock
+	typ     string
+	desc    string
+}
+
+func (h *queueDataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	return h.builder.handle(h.handler, h.clock,
+		h.makeEntry(deviceID, queueKindTelemetry, "", js))
+}
+
+func (h *queueDataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	return h.builder.handle(h.handler, h.clock,
+		h.makeEntry(deviceID, queueKindRegistration, "", js))
+}
+
+func (h *queueDataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	return h.builder.handle(h.handler, h.clock,
+		h.makeEntry(deviceID, queueKindStream, measurement, js))
+}
+
+func (h *queueDataHandler) makeEntry(
+	deviceID string,
+	kind string,
+	measurement string,
+	js devcore.JSON,
+) queueEntry {
+	return queueEntry{
+		DeviceID:    deviceID,
+		Type:        h.typ,
+		Desc:        h.desc,
+		Kind:        kind,
+		Measurement: measurement,
+		Data:        js,
+	}
+}
+
+// queueSystemClock ignores the timestamps of the replayed data, since the device
+// clock isn't available until the device is added.
+type queueSystemClock struct{}
+
+func (*queueSystemClock) SetTimestamp(int64) error {
+	return nil
+}
+
+func (*queueSystemClock) GetTimestamp() (int64, error) {
+	return -1, status.StatusNotSupported
+}
@@ -0,0 +1,366 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+	"github.com/tendry-lab/device-hub/components/storage/stinfluxdb"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+type testQueueData struct {
+	kind        string
+	deviceID    string
+	measurement string
+	js          devcore.JSON
+}
+
+type testQueueDataHandler struct {
+	sink *testQueueSink
+}
+
+func (h *testQueueDataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	return h.sink.handle(testQueueData{kind: "telemetry", deviceID: deviceID, js: js})
+}
+
+func (h *testQueueDataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	return h.sink.handle(testQueueData{kind: "registration", deviceID: deviceID, js: js})
+}
+
+func (h *testQueueDataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	return h.sink.handle(testQueueData{
+		kind:        "stream",
+		deviceID:    deviceID,
+		measurement: measurement,
+		js:          js,
+	})
+}
+
+type testQueueSink struct {
+	err     error
+	data    []testQueueData
+	devices []string
+}
+
+func (s *testQueueSink) BuildHandler(
+	_ syscore.SystemClock,
+	deviceID string,
+	_ string,
+	_ string,
+) devcore.DataHandler {
+	s.devices = append(s.devices, deviceID)
+
+	return &testQueueDataHandler{sink: s}
+}
+
+func (s *testQueueSink) handle(data testQueueData) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.data = append(s.data, data)
+
+	return nil
+}
+
+func newTestQueue(t *testing.T) *stcore.BboltQueue {
+	db, err := stcore.NewBboltDB(filepath.Join(t.TempDir(), "bbolt.db"), nil)
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+
+	queue, err := stcore.NewBboltQueue(db, "queue", &testCacheStoreClock{},
+		stcore.BboltQueueParams{})
+	require.Nil(t, err)
+
+	return queue
+}
+
+func TestQueueHandlerBuilderReplay(t *testing.T) {
+	deviceID := "0xABCD"
+
+	sink := &testQueueSink{}
+	queue := newTestQueue(t)
+	clock := &testCacheStoreClock{}
+
+	builder := NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{})
+	handler := builder.BuildHandler(clock, deviceID, "test-type", "foo-bar-baz")
+
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(10)}))
+	require.Equal(t, 1, len(sink.data))
+	require.Equal(t, 0, queue.Stats().Length)
+
+	sink.err = errors.New("sink is offline")
+
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(30)}))
+	require.Nil(t, handler.HandleRegistration(deviceID, devcore.JSON{"timestamp": float64(20)}))
+	require.Equal(t, 2, queue.Stats().Length)
+	require.Equal(t, int64(20), clock.timestamp)
+
+	require.NotNil(t, builder.Run())
+	require.Equal(t, 2, queue.Stats().Length)
+
+	sink.err = nil
+
+	// Data is queued while the queue isn't empty, to keep the data order.
+	require.Nil(t, handler.HandleStream(deviceID, "diagnostics",
+		devcore.JSON{"timestamp": float64(25)}))
+	require.Equal(t, 3, queue.Stats().Length)
+	require.Equal(t, 1, len(sink.data))
+
+	require.Nil(t, builder.Run())
+	require.Equal(t, 0, queue.Stats().Length)
+
+	require.Equal(t, 4, len(sink.data))
+	require.Equal(t, "registration", sink.data[1].kind)
+	require.Equal(t, float64(20), sink.data[1].js["timestamp"])
+	require.Equal(t, "stream", sink.data[2].kind)
+	require.Equal(t, "diagnostics", sink.data[2].measurement)
+	require.Equal(t, float64(25), sink.data[2].js["timestamp"])
+	require.Equal(t, "telemetry", sink.data[3].kind)
+	require.Equal(t, float64(30), sink.data[3].js["timestamp"])
+
+	// Replayed data is handled by the handler built for the replay.
+	require.Equal(t, []string{deviceID, deviceID}, sink.devices)
+
+	// Replay handlers are dropped once the queue is drained.
+	require.Empty(t, builder.handlers)
+}
+
+func TestQueueHandlerBuilderReplayUnknownDevice(t *testing.T) {
+	deviceID := "0xABCD"
+
+	queue := newTestQueue(t)
+
+	sink := &testQueueSink{err: errors.New("sink is offline")}
+	handler := NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{}).BuildHandler(
+		&testCacheStoreClock{}, deviceID, "test-type", "foo-bar-baz")
+
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(10)}))
+	require.Equal(t, 1, queue.Stats().Length)
+
+	// Emulate the restart, the device isn't added yet.
+	sink = &testQueueSink{}
+	require.Nil(t, NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{}).Run())
+	require.Equal(t, 0, queue.Stats().Length)
+	require.Equal(t, 1, len(sink.data))
+	require.Equal(t, deviceID, sink.data[0].deviceID)
+	require.Equal(t, []string{deviceID}, sink.devices)
+}
+
+func TestQueueHandlerBuilderInvalidTimestamp(t *testing.T) {
+	deviceID := "0xABCD"
+
+	queue := newTestQueue(t)
+
+	sink := &testQueueSink{err: errors.New("sink is offline")}
+	handler := NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{}).BuildHandler(
+		&testCacheStoreClock{}, deviceID, "test-type", "foo-bar-baz")
+
+	require.NotNil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"temperature": 42.0}))
+	require.Equal(t, 0, queue.Stats().Length)
+}
+
+func TestQueueHandlerBuilderMaxAttempts(t *testing.T) {
+	deviceID := "0xABCD"
+
+	sink := &testQueueSink{err: errors.New("sink is offline")}
+	queue := newTestQueue(t)
+
+	builder := NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{
+		MaxAttempts: 3,
+	})
+	handler := builder.BuildHandler(
+		&testCacheStoreClock{}, deviceID, "test-type", "foo-bar-baz")
+
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(10)}))
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(20)}))
+	require.Equal(t, 2, queue.Stats().Length)
+
+	require.NotNil(t, builder.Run())
+	require.NotNil(t, builder.Run())
+	require.Equal(t, 2, queue.Stats().Length)
+
+	// Front entry is dropped after the last attempt, the next entry is retried.
+	require.NotNil(t, builder.Run())
+	require.Equal(t, 1, queue.Stats().Length)
+
+	sink.err = nil
+
+	require.Nil(t, builder.Run())
+	require.Equal(t, 0, queue.Stats().Length)
+	require.Equal(t, 1, len(sink.data))
+	require.Equal(t, float64(20), sink.data[0].js["timestamp"])
+}
+
+func TestQueueHandlerBuilderUnreachable(t *testing.T) {
+	deviceID := "0xABCD"
+
+	sink := &testQueueSink{err: errors.New("sink is offline")}
+	queue := newTestQueue(t)
+
+	builder := NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{})
+	handler := builder.BuildHandler(
+		&testCacheStoreClock{}, deviceID, "test-type", "foo-bar-baz")
+
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(10)}))
+
+	// Entry isn't dropped while the sink is unreachable.
+	for n := 0; n < 100; n++ {
+		require.NotNil(t, builder.Run())
+	}
+	require.Equal(t, 1, queue.Stats().Length)
+
+	sink.err = nil
+
+	require.Nil(t, builder.Run())
+	require.Equal(t, 0, queue.Stats().Length)
+	require.Equal(t, 1, len(sink.data))
+}
+
+func TestQueueHandlerBuilderRejected(t *testing.T) {
+	deviceID := "0xABCD"
+
+	sink := &testQueueSink{err: errors.New("sink is offline")}
+	queue := newTestQueue(t)
+
+	builder := NewQueueHandlerBuilder(sink, queue, QueueHandlerBuilderParams{})
+	handler := builder.BuildHandler(
+		&testCacheStoreClock{}, deviceID, "test-type", "foo-bar-baz")
+
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(10)}))
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(20)}))
+	require.Equal(t, 2, queue.Stats().Length)
+
+	// Rejected entry is dropped immediately, without blocking the next entries.
+	sink.err = status.StatusInvalidArg
+
+	require.Nil(t, builder.Run())
+	require.Equal(t, 0, queue.Stats().Length)
+	require.Empty(t, sink.data)
+
+	// Rejected data isn't buffered.
+	require.ErrorIs(t, handler.HandleTelemetry(deviceID,
+		devcore.JSON{"timestamp": float64(30)}), status.StatusInvalidArg)
+	require.Equal(t, 0, queue.Stats().Length)
+}
+
+type testQueueInfluxDB struct {
+	offline atomic.Bool
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func (db *testQueueInfluxDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if db.offline.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	buf, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	db.mu.Lock()
+	db.lines = append(db.lines, strings.Split(strings.TrimSpace(string(buf)), "\n")...)
+	db.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (db *testQueueInfluxDB) getLines() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return append([]string(nil), db.lines...)
+}
+
+func TestQueueHandlerBuilderBatchFallback(t *testing.T) {
+	deviceID := "0xABCD"
+
+	db := &testQueueInfluxDB{}
+	db.offline.Store(true)
+
+	server := httptest.NewServer(db)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pipeline := stinfluxdb.NewPipeline(ctx, stinfluxdb.DBParams{
+		URL:    server.URL,
+		Org:    "org",
+		Bucket: "bucket",
+		Batch: stinfluxdb.BatchWriterParams{
+			BatchSize:        2,
+			BufferSize:       10,
+			FlushInterval:    time.Hour,
+			RetryCount:       1,
+			RetryInterval:    time.Millisecond,
+			MaxRetryInterval: time.Millisecond,
+		},
+	})
+	require.Nil(t, pipeline.Start())
+	defer func() {
+		cancel()
+		require.Nil(t, pipeline.Stop())
+	}()
+
+	queue := newTestQueue(t)
+
+	builder := NewQueueHandlerBuilder(pipeline, queue, QueueHandlerBuilderParams{})
+	handler := builder.BuildHandler(
+		&testCacheStoreClock{}, deviceID, "test-type", "foo-bar-baz")
+
+	// Batch is accepted, and fails after the handler has returned.
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(10)}))
+	require.Nil(t, handler.HandleTelemetry(deviceID, devcore.JSON{"timestamp": float64(20)}))
+
+	// Failed batch is buffered.
+	require.Eventually(t, func() bool {
+		return queue.Stats().Length == 2
+	}, time.Second*5, time.Millisecond*10)
+
+	db.offline.Store(false)
+
+	require.Nil(t, builder.Run())
+	require.Equal(t, 0, queue.Stats().Length)
+
+	// Replayed data is written once the batch is full.
+	require.Eventually(t, func() bool {
+		return len(db.getLines()) == 2
+	}, time.Second*5, time.Millisecond*10)
+
+	lines := db.getLines()
+	require.Contains(t, lines[0], "timestamp=10")
+	require.Contains(t, lines[1], "timestamp=20")
+}
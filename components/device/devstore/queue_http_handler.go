@@ -0,0 +1,47 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+// QueueHTTPHandler allows to inspect the buffered device data over HTTP API.
+type QueueHTTPHandler struct {
+	queue stcore.Queue
+}
+
+// NewQueueHTTPHandler is an initialization of QueueHTTPHandler.
+//
+// Parameters:
+//   - queue to get the statistics of the buffered device data.
+func NewQueueHTTPHandler(queue stcore.Queue) *QueueHTTPHandler {
+	return &QueueHTTPHandler{queue: queue}
+}
+
+// HandleStats returns the statistics of the buffered device data.
+func (h *QueueHTTPHandler) HandleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	buf, err := json.Marshal(h.queue.Stats())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to format JSON: %v", err),
+			http.StatusInternalServerError)
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
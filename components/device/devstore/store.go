@@ -5,7 +5,32 @@
 
 package devstore
 
-import "errors"
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/textproto"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// DeviceState is a device operational state.
+type DeviceState string
+
+const (
+	// DeviceStateOnline - device is active.
+	DeviceStateOnline DeviceState = "online"
+
+	// DeviceStateDegraded - device is inactive for a while, but isn't offline yet.
+	DeviceStateDegraded DeviceState = "degraded"
+
+	// DeviceStateOffline - device is inactive for too long.
+	DeviceStateOffline DeviceState = "offline"
+
+	// DeviceStateRemoved - device is removed due to inactivity.
+	DeviceStateRemoved DeviceState = "removed"
+)
 
 // StoreItem is a description of a single device.
 type StoreItem struct {
@@ -14,6 +39,175 @@ type StoreItem struct {
 	Desc      string `json:"desc"`
 	ID        string `json:"id"`
 	CreatedAt string `json:"created_at"`
+
+	// State - device operational state, empty if the device activity isn't monitored.
+	State DeviceState `json:"state,omitempty"`
+
+	// StateChangedAt - time of the last state transition.
+	StateChangedAt string `json:"state_changed_at,omitempty"`
+
+	// TLS - TLS settings of the device, omitted if the device has no own settings.
+	TLS *DeviceTLS `json:"tls,omitempty"`
+
+	// Credentials - redacted device credentials, omitted if the device has none.
+	Credentials *DeviceCredentials `json:"credentials,omitempty"`
+}
+
+// DeviceTLS is a TLS configuration to reach the https:// device.
+type DeviceTLS struct {
+	// CAPath - PEM encoded CA bundle file path to verify the device certificate,
+	// system CA is used if empty.
+	CAPath string `json:"ca_path,omitempty"`
+
+	// Fingerprint - hex encoded SHA-256 fingerprint of the device certificate.
+	//
+	// Remarks:
+	//  - Device certificate isn't verified against CA if the CA bundle isn't set,
+	//    e.g. for the self-signed certificates.
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// CertPath - PEM encoded client certificate file path, presented to the device.
+	CertPath string `json:"cert_path,omitempty"`
+
+	// KeyPath - PEM encoded client private key file path.
+	KeyPath string `json:"key_path,omitempty"`
+
+	// ServerName - name to verify the device certificate and to send in SNI,
+	// device URI hostname is used if empty.
+	ServerName string `json:"server_name,omitempty"`
+}
+
+// IsZero returns true if no TLS settings are set.
+func (t DeviceTLS) IsZero() bool {
+	return t == DeviceTLS{}
+}
+
+// merge returns the settings, where the empty fields are taken from base.
+//
+// Remarks:
+//   - Client certificate and key are taken together.
+func (t DeviceTLS) merge(base DeviceTLS) DeviceTLS {
+	if t.CAPath == "" {
+		t.CAPath = base.CAPath
+	}
+	if t.Fingerprint == "" {
+		t.Fingerprint = base.Fingerprint
+	}
+	if t.CertPath == "" && t.KeyPath == "" {
+		t.CertPath = base.CertPath
+		t.KeyPath = base.KeyPath
+	}
+	if t.ServerName == "" {
+		t.ServerName = base.ServerName
+	}
+
+	return t
+}
+
+// CredentialsType is a way the credentials are sent to the device.
+type CredentialsType string
+
+const (
+	// CredentialsTypeBasic - HTTP basic authentication.
+	CredentialsTypeBasic CredentialsType = "basic"
+
+	// CredentialsTypeBearer - bearer token in the Authorization header.
+	CredentialsTypeBearer CredentialsType = "bearer"
+
+	// CredentialsTypeHeader - custom header, e.g. X-API-Key.
+	CredentialsTypeHeader CredentialsType = "header"
+)
+
+// DeviceCredentials are sent with each request to the HTTP device.
+type DeviceCredentials struct {
+	// Type - how the credentials are sent to the device.
+	Type CredentialsType `json:"type"`
+
+	// Username - basic authentication user name.
+	Username string `json:"username,omitempty"`
+
+	// Password - basic authentication password.
+	Password string `json:"password,omitempty"`
+
+	// Token - bearer token.
+	Token string `json:"token,omitempty"`
+
+	// Header - custom header name.
+	Header string `json:"header,omitempty"`
+
+	// Value - custom header value.
+	Value string `json:"value,omitempty"`
+}
+
+// IsZero returns true if no credentials are set.
+func (c DeviceCredentials) IsZero() bool {
+	return c == DeviceCredentials{}
+}
+
+// validate returns status.StatusInvalidArg if the credentials are inconsistent.
+func (c DeviceCredentials) validate() error {
+	switch c.Type {
+	case CredentialsTypeBasic:
+		if c.Username == "" {
+			return fmt.Errorf("%w: basic credentials require username", status.StatusInvalidArg)
+		}
+	case CredentialsTypeBearer:
+		if c.Token == "" {
+			return fmt.Errorf("%w: bearer credentials require token", status.StatusInvalidArg)
+		}
+	case CredentialsTypeHeader:
+		if c.Header == "" || c.Value == "" {
+			return fmt.Errorf("%w: header credentials require header and value",
+				status.StatusInvalidArg)
+		}
+	default:
+		return fmt.Errorf("%w: unknown credentials type: %s", status.StatusInvalidArg, c.Type)
+	}
+
+	return nil
+}
+
+// header returns the headers to send the credentials.
+func (c DeviceCredentials) header() http.Header {
+	header := make(http.Header)
+
+	switch c.Type {
+	case CredentialsTypeBasic:
+		header.Set("Authorization", "Basic "+
+			base64.StdEncoding.EncodeToString([]byte(c.Username+":"+c.Password)))
+	case CredentialsTypeBearer:
+		header.Set("Authorization", "Bearer "+c.Token)
+	case CredentialsTypeHeader:
+		header.Set(textproto.CanonicalMIMEHeaderKey(c.Header), c.Value)
+	}
+
+	return header
+}
+
+// redact returns the credentials without the secrets.
+//
+// Remarks:
+//   - Only the type and the custom header name are kept.
+func (c DeviceCredentials) redact() DeviceCredentials {
+	return DeviceCredentials{
+		Type:   c.Type,
+		Header: c.Header,
+	}
+}
+
+// DeviceOptions are optional device settings, persisted with the device.
+type DeviceOptions struct {
+	// TLS - TLS settings of the https:// device.
+	//
+	// Remarks:
+	//  - TLS settings of the device profile are used for the empty fields.
+	TLS DeviceTLS `json:"tls"`
+
+	// Credentials - credentials of the HTTP device.
+	//
+	// Remarks:
+	//  - Credentials are persisted encrypted, and are never returned or logged.
+	Credentials DeviceCredentials `json:"credentials"`
 }
 
 // ErrDeviceExist is returned if the device already exists in the store.
@@ -27,6 +221,7 @@ type Store interface {
 	//   - uri - device URI, how device can be reached.
 	//   - typ - device type, to distinguish one device from another.
 	//   - desc - human readable device description.
+	//   - opts - optional device settings.
 	//
 	// Remarks:
 	//   - uri should be unique.
@@ -35,6 +230,8 @@ type Store interface {
 	// URI examples:
 	//   - http://bonsai-growlab.local:12345/api/v1. mDNS HTTP API
 	//   - http://192.168.4.1:17321. Static IP address.
+	//   - mqtt://localhost:1883/bonsai/growlab-1. MQTT broker and topic prefix.
+	//   - push://0xABCD. Device pushes data to the hub, identified by the device ID.
 	//
 	// Typ examples:
 	//  - bonsai-growlab
@@ -45,7 +242,7 @@ type Store interface {
 	// Desc examples:
 	//   - room-plant-zamioculcas
 	//   - living-room-light-bulb
-	Add(uri string, typ string, desc string) error
+	Add(uri string, typ string, desc string, opts DeviceOptions) error
 
 	// Remove removes the device associated with the provided URI.
 	//
@@ -53,6 +250,20 @@ type Store interface {
 	//   - uri - unique device identifier.
 	Remove(uri string) error
 
+	// Update changes the URI, type and description of the device.
+	//
+	// Parameters:
+	//   - uri - unique device identifier.
+	//   - newURI - new device URI, the same as uri if the URI isn't changed.
+	//   - typ - new device type.
+	//   - desc - new device description.
+	//
+	// Remarks:
+	//   - Device options are kept.
+	//   - status.StatusNoData is returned if the device doesn't exist.
+	//   - ErrDeviceExist is returned if newURI is used by another device.
+	Update(uri string, newURI string, typ string, desc string) error
+
 	// GetDesc returns descriptions for registered devices.
 	GetDesc() []StoreItem
 }
@@ -13,33 +13,79 @@ import (
 	"github.com/tendry-lab/device-hub/components/system/syssched"
 )
 
-// StoreAliveMonitor monitors the operational health of devices. If a device isn't
-// active for a period of time, it is considered to be inactive and is removed.
+// InactiveAction is an action applied to the device once it goes offline.
+type InactiveAction string
+
+const (
+	// InactiveActionOffline - device is only marked as offline.
+	InactiveActionOffline InactiveAction = "offline"
+
+	// InactiveActionPause - device is marked as offline, and its data processing
+	// is paused until the device is active again.
+	InactiveActionPause InactiveAction = "pause"
+
+	// InactiveActionRemove - device is removed from the store.
+	InactiveActionRemove InactiveAction = "remove"
+)
+
+// StoreAliveMonitorParams provides various configuration options for StoreAliveMonitor.
+type StoreAliveMonitorParams struct {
+	// DegradedInterval - how long a device can be inactive before it's degraded.
+	//
+	// Remarks:
+	//  - Device isn't degraded if zero.
+	DegradedInterval time.Duration
+
+	// OfflineInterval - how long a device can be inactive before it's offline.
+	OfflineInterval time.Duration
+
+	// Action - what to do with the device once it's offline.
+	Action InactiveAction
+}
+
+// StoreAliveMonitor monitors the operational health of devices.
+//
+// Remarks:
+//   - Device goes through the online, degraded, offline and removed states, depending
+//     on how long it is inactive.
+//   - Offline device is handled according to the configured action.
+//   - Paused device is resumed once it's active again, or once it's added again,
+//     e.g. by the mDNS autodiscovery. Pauser should keep checking the paused
+//     device at a low rate, otherwise the device is only resumed once it's
+//     added again or pushes data.
 type StoreAliveMonitor struct {
-	maxInactiveInterval time.Duration
-	clock               syscore.MonotonicClock
-	store               Store
+	clock  syscore.MonotonicClock
+	store  Store
+	params StoreAliveMonitorParams
+	pauser DevicePauser
 
 	mu      sync.Mutex
-	devices map[string]time.Time
+	devices map[string]*aliveDevice
+}
+
+type aliveDevice struct {
+	updateTime time.Time
+	changeTime time.Time
+	state      DeviceState
+	paused     bool
 }
 
 // NewStoreAliveMonitor is an initialization of StoreAliveMonitor.
 //
 // Parameters:
-//   - store to automatically add/remove devices.
 //   - clock to measure time for how long device is inactive.
-//   - maxInactiveInterval is maximum allowed interval for a device to be inactive.
+//   - store to automatically add/remove devices.
+//   - params - various monitoring configuration options.
 func NewStoreAliveMonitor(
 	clock syscore.MonotonicClock,
 	store Store,
-	maxInactiveInterval time.Duration,
+	params StoreAliveMonitorParams,
 ) *StoreAliveMonitor {
 	monitor := &StoreAliveMonitor{
-		maxInactiveInterval: maxInactiveInterval,
-		store:               store,
-		clock:               clock,
-		devices:             make(map[string]time.Time),
+		clock:   clock,
+		store:   store,
+		params:  params,
+		devices: make(map[string]*aliveDevice),
 	}
 
 	monitor.restoreDevices()
@@ -47,6 +93,15 @@ func NewStoreAliveMonitor(
 	return monitor
 }
 
+// SetPauser sets the pauser to pause the offline devices.
+//
+// Remarks:
+//   - Offline devices are only marked as offline if the pauser isn't set.
+//   - Should be called before Run().
+func (m *StoreAliveMonitor) SetPauser(pauser DevicePauser) {
+	m.pauser = pauser
+}
+
 // Monitor returns the alive notifier for the device associated with the provided URI.
 func (m *StoreAliveMonitor) Monitor(uri string) syssched.AliveNotifier {
 	return &storeAliveNotifier{
@@ -56,15 +111,33 @@ func (m *StoreAliveMonitor) Monitor(uri string) syssched.AliveNotifier {
 }
 
 // Add adds the device to the underlying store and starts monitoring its well-being.
-func (m *StoreAliveMonitor) Add(uri string, typ string, desc string) error {
+//
+// Remarks:
+//   - Paused device is resumed if it's added again.
+func (m *StoreAliveMonitor) Add(
+	uri string,
+	typ string,
+	desc string,
+	opts DeviceOptions,
+) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if err := m.store.Add(uri, typ, desc); err != nil {
+	if err := m.store.Add(uri, typ, desc, opts); err != nil {
+		if device, ok := m.devices[uri]; ok && err == ErrDeviceExist && device.paused {
+			m.setOnline(uri, device)
+		}
+
 		return err
 	}
 
-	m.devices[uri] = m.clock.Now()
+	now := m.clock.Now()
+
+	m.devices[uri] = &aliveDevice{
+		updateTime: now,
+		changeTime: now,
+		state:      DeviceStateOnline,
+	}
 
 	return nil
 }
@@ -83,9 +156,41 @@ func (m *StoreAliveMonitor) Remove(uri string) error {
 	return nil
 }
 
-// GetDesc returns descriptions for registered devices.
+// Update updates the device associated with the provided URI.
+//
+// Remarks:
+//   - Device state is kept if the device URI is changed.
+func (m *StoreAliveMonitor) Update(uri string, newURI string, typ string, desc string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.store.Update(uri, newURI, typ, desc); err != nil {
+		return err
+	}
+
+	if device, ok := m.devices[uri]; ok && newURI != uri {
+		delete(m.devices, uri)
+		m.devices[newURI] = device
+	}
+
+	return nil
+}
+
+// GetDesc returns descriptions for registered devices, with their current state.
 func (m *StoreAliveMonitor) GetDesc() []StoreItem {
-	return m.store.GetDesc()
+	items := m.store.GetDesc()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for n := range items {
+		if device, ok := m.devices[items[n].URI]; ok {
+			items[n].State = device.state
+			items[n].StateChangedAt = device.changeTime.Format(time.RFC1123)
+		}
+	}
+
+	return items
 }
 
 // HandleError handles Run() error.
@@ -93,35 +198,100 @@ func (*StoreAliveMonitor) HandleError(err error) {
 	syscore.LogErr.Printf("failed to verify inactive devices: %v", err)
 }
 
-// Run verifies if added devices are still alive.
+// Run updates the state of the added devices.
 func (m *StoreAliveMonitor) Run() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	for uri, updateTime := range m.devices {
-		now := m.clock.Now()
+	for uri, device := range m.devices {
+		diff := m.clock.Now().Sub(device.updateTime)
+
+		if diff >= m.params.OfflineInterval {
+			if device.state == DeviceStateOffline {
+				continue
+			}
+
+			syscore.LogWrn.Printf("device is offline: uri=%s cur_inactive=%s"+
+				" max_inactive=%s action=%s", uri, diff, m.params.OfflineInterval,
+				m.params.Action)
 
-		diff := now.Sub(updateTime)
-		if diff < m.maxInactiveInterval {
-			continue
+			if err := m.setOffline(uri, device); err != nil {
+				return err
+			}
+		} else if m.params.DegradedInterval > 0 && diff >= m.params.DegradedInterval {
+			if device.state == DeviceStateOnline {
+				m.setState(uri, device, DeviceStateDegraded)
+			}
 		}
+	}
 
-		syscore.LogWrn.Printf("removing inactive device:"+
-			" uri=%s cur_inactive=%s max_inactive=%s", uri, diff, m.maxInactiveInterval)
+	return nil
+}
 
+func (m *StoreAliveMonitor) setOffline(uri string, device *aliveDevice) error {
+	switch m.params.Action {
+	case InactiveActionRemove:
 		if err := m.store.Remove(uri); err != nil {
 			return err
 		}
 
+		m.setState(uri, device, DeviceStateRemoved)
 		delete(m.devices, uri)
+
+		return nil
+
+	case InactiveActionPause:
+		if m.pauser != nil {
+			if err := m.pauser.Pause(uri); err != nil {
+				return err
+			}
+
+			device.paused = true
+		}
+
+	case InactiveActionOffline:
 	}
 
+	m.setState(uri, device, DeviceStateOffline)
+
 	return nil
 }
 
+func (m *StoreAliveMonitor) setOnline(uri string, device *aliveDevice) {
+	device.updateTime = m.clock.Now()
+
+	if device.paused {
+		if err := m.pauser.Resume(uri); err != nil {
+			syscore.LogErr.Printf("failed to resume device: uri=%s err=%v", uri, err)
+
+			return
+		}
+
+		device.paused = false
+	}
+
+	if device.state != DeviceStateOnline {
+		m.setState(uri, device, DeviceStateOnline)
+	}
+}
+
+func (m *StoreAliveMonitor) setState(uri string, device *aliveDevice, state DeviceState) {
+	syscore.LogInf.Printf("device state changed: uri=%s from=%s to=%s",
+		uri, device.state, state)
+
+	device.state = state
+	device.changeTime = m.clock.Now()
+}
+
 func (m *StoreAliveMonitor) restoreDevices() {
-	for _, desc := range m.GetDesc() {
-		m.devices[desc.URI] = m.clock.Now()
+	now := m.clock.Now()
+
+	for _, desc := range m.store.GetDesc() {
+		m.devices[desc.URI] = &aliveDevice{
+			updateTime: now,
+			changeTime: now,
+			state:      DeviceStateOnline,
+		}
 	}
 }
 
@@ -129,7 +299,20 @@ func (m *StoreAliveMonitor) notifyAlive(uri string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	m.devices[uri] = m.clock.Now()
+	device, ok := m.devices[uri]
+	if !ok {
+		now := m.clock.Now()
+
+		m.devices[uri] = &aliveDevice{
+			updateTime: now,
+			changeTime: now,
+			state:      DeviceStateOnline,
+		}
+
+		return
+	}
+
+	m.setOnline(uri, device)
 }
 
 type storeAliveNotifier struct {
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
 )
 
 type testStoreAliveMonitorClock struct {
@@ -38,7 +40,12 @@ func newTestStoreAliveMonitorStore() *testStoreAliveMonitorStore {
 	}
 }
 
-func (s *testStoreAliveMonitorStore) Add(uri string, typ string, desc string) error {
+func (s *testStoreAliveMonitorStore) Add(
+	uri string,
+	typ string,
+	desc string,
+	_ DeviceOptions,
+) error {
 	s.addCallCount++
 
 	if s.err != nil {
@@ -65,6 +72,30 @@ func (s *testStoreAliveMonitorStore) Remove(uri string) error {
 	return nil
 }
 
+func (s *testStoreAliveMonitorStore) Update(
+	uri string,
+	newURI string,
+	typ string,
+	desc string,
+) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if _, ok := s.devices[uri]; !ok {
+		return status.StatusNoData
+	}
+
+	delete(s.devices, uri)
+
+	s.devices[newURI] = testStoreAliveMonitorDevice{
+		typ:  typ,
+		desc: desc,
+	}
+
+	return nil
+}
+
 func (s *testStoreAliveMonitorStore) GetDesc() []StoreItem {
 	var ret []StoreItem
 
@@ -102,9 +133,12 @@ func TestStoreAliveMonitorVerifyInactivityOK(t *testing.T) {
 	clock := &testStoreAliveMonitorClock{}
 	store := newTestStoreAliveMonitorStore()
 
-	monitor := NewStoreAliveMonitor(clock, store, inactiveInterval)
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		OfflineInterval: inactiveInterval,
+		Action:          InactiveActionRemove,
+	})
 
-	require.Nil(t, monitor.Add(uri, typ, desc))
+	require.Nil(t, monitor.Add(uri, typ, desc, DeviceOptions{}))
 	require.Nil(t, monitor.Run())
 
 	require.Equal(t, 1, store.count())
@@ -132,12 +166,15 @@ func TestStoreAliveMonitorVerifyInactivityTimeout(t *testing.T) {
 	clock := &testStoreAliveMonitorClock{}
 	store := newTestStoreAliveMonitorStore()
 
-	monitor := NewStoreAliveMonitor(clock, store, inactiveInterval)
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		OfflineInterval: inactiveInterval,
+		Action:          InactiveActionRemove,
+	})
 
 	notifier := monitor.Monitor(uri)
 	require.NotNil(t, notifier)
 
-	require.Nil(t, monitor.Add(uri, typ, desc))
+	require.Nil(t, monitor.Add(uri, typ, desc, DeviceOptions{}))
 	require.Nil(t, monitor.Run())
 
 	require.Equal(t, 1, store.count())
@@ -186,9 +223,12 @@ func TestStoreAliveMonitorVerifyInactivityOnRestore(t *testing.T) {
 	clock := &testStoreAliveMonitorClock{}
 
 	store := newTestStoreAliveMonitorStore()
-	require.Nil(t, store.Add(uri, typ, desc))
+	require.Nil(t, store.Add(uri, typ, desc, DeviceOptions{}))
 
-	monitor := NewStoreAliveMonitor(clock, store, inactiveInterval)
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		OfflineInterval: inactiveInterval,
+		Action:          InactiveActionRemove,
+	})
 
 	clock.now = clock.now.Add(inactiveInterval)
 
@@ -202,3 +242,137 @@ func TestStoreAliveMonitorVerifyInactivityOnRestore(t *testing.T) {
 	require.Equal(t, 1, store.removeCallCount)
 	require.False(t, store.checkDevice(uri, typ, desc))
 }
+
+type testStoreAliveMonitorPauser struct {
+	paused map[string]bool
+}
+
+func (p *testStoreAliveMonitorPauser) Pause(uri string) error {
+	p.paused[uri] = true
+
+	return nil
+}
+
+func (p *testStoreAliveMonitorPauser) Resume(uri string) error {
+	p.paused[uri] = false
+
+	return nil
+}
+
+func testStoreAliveMonitorState(t *testing.T, monitor *StoreAliveMonitor) DeviceState {
+	items := monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+
+	return items[0].State
+}
+
+func TestStoreAliveMonitorStates(t *testing.T) {
+	uri := "http://bonsai-growlab.local/api/v1"
+
+	clock := &testStoreAliveMonitorClock{}
+	store := newTestStoreAliveMonitorStore()
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		DegradedInterval: time.Minute,
+		OfflineInterval:  time.Minute * 5,
+		Action:           InactiveActionOffline,
+	})
+
+	require.Nil(t, monitor.Add(uri, "test-type", "home-plant", DeviceOptions{}))
+	require.Equal(t, DeviceStateOnline, testStoreAliveMonitorState(t, monitor))
+
+	clock.now = clock.now.Add(time.Minute)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, DeviceStateDegraded, testStoreAliveMonitorState(t, monitor))
+
+	clock.now = clock.now.Add(time.Minute * 4)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, DeviceStateOffline, testStoreAliveMonitorState(t, monitor))
+	require.Equal(t, clock.now.Format(time.RFC1123), monitor.GetDesc()[0].StateChangedAt)
+
+	// Offline device is kept in the store.
+	clock.now = clock.now.Add(time.Hour)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, DeviceStateOffline, testStoreAliveMonitorState(t, monitor))
+	require.Equal(t, 0, store.removeCallCount)
+
+	monitor.Monitor(uri).NotifyAlive()
+	require.Equal(t, DeviceStateOnline, testStoreAliveMonitorState(t, monitor))
+}
+
+func TestStoreAliveMonitorPause(t *testing.T) {
+	uri := "http://bonsai-growlab.local/api/v1"
+
+	clock := &testStoreAliveMonitorClock{}
+	store := newTestStoreAliveMonitorStore()
+	pauser := &testStoreAliveMonitorPauser{paused: make(map[string]bool)}
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		OfflineInterval: time.Minute,
+		Action:          InactiveActionPause,
+	})
+	monitor.SetPauser(pauser)
+
+	require.Nil(t, monitor.Add(uri, "test-type", "home-plant", DeviceOptions{}))
+
+	clock.now = clock.now.Add(time.Minute)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, DeviceStateOffline, testStoreAliveMonitorState(t, monitor))
+	require.True(t, pauser.paused[uri])
+
+	// Device is resumed once it's added again, e.g. by the mDNS autodiscovery.
+	store.err = ErrDeviceExist
+	require.Equal(t, ErrDeviceExist, monitor.Add(uri, "test-type", "home-plant", DeviceOptions{}))
+	require.False(t, pauser.paused[uri])
+	require.Equal(t, DeviceStateOnline, testStoreAliveMonitorState(t, monitor))
+
+	clock.now = clock.now.Add(time.Minute)
+	require.Nil(t, monitor.Run())
+	require.True(t, pauser.paused[uri])
+
+	// Device is resumed once it's active again.
+	monitor.Monitor(uri).NotifyAlive()
+	require.False(t, pauser.paused[uri])
+	require.Equal(t, DeviceStateOnline, testStoreAliveMonitorState(t, monitor))
+	require.Equal(t, 0, store.removeCallCount)
+}
+
+func TestStoreAliveMonitorUpdate(t *testing.T) {
+	uri := "http://192.168.1.10:80/api/v1"
+	newURI := "http://192.168.1.20:80/api/v1"
+
+	clock := &testStoreAliveMonitorClock{}
+	store := newTestStoreAliveMonitorStore()
+
+	monitor := NewStoreAliveMonitor(clock, store, StoreAliveMonitorParams{
+		DegradedInterval: time.Minute,
+		OfflineInterval:  time.Minute * 5,
+		Action:           InactiveActionRemove,
+	})
+
+	require.Equal(t, status.StatusNoData,
+		monitor.Update(uri, newURI, "test-type", "home-plant"))
+
+	require.Nil(t, monitor.Add(uri, "test-type", "home-plant", DeviceOptions{}))
+
+	clock.now = clock.now.Add(time.Minute)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, DeviceStateDegraded, testStoreAliveMonitorState(t, monitor))
+
+	// Device state is kept for the new URI.
+	require.Nil(t, monitor.Update(uri, newURI, "test-type", "kitchen-plant"))
+
+	items := monitor.GetDesc()
+	require.Equal(t, 1, len(items))
+	require.Equal(t, newURI, items[0].URI)
+	require.Equal(t, "kitchen-plant", items[0].Desc)
+	require.Equal(t, DeviceStateDegraded, items[0].State)
+
+	monitor.Monitor(newURI).NotifyAlive()
+	require.Equal(t, DeviceStateOnline, testStoreAliveMonitorState(t, monitor))
+
+	clock.now = clock.now.Add(time.Minute * 5)
+	require.Nil(t, monitor.Run())
+	require.Equal(t, 1, store.removeCallCount)
+	require.Empty(t, store.devices)
+}
@@ -13,7 +13,7 @@ import (
 	"github.com/tendry-lab/device-hub/components/http/htcore"
 )
 
-// StoreHTTPHandler allows to add/remove devices over HTTP API.
+// StoreHTTPHandler allows to add/update/remove devices over HTTP API.
 type StoreHTTPHandler struct {
 	store Store
 }
@@ -21,7 +21,7 @@ type StoreHTTPHandler struct {
 // NewStoreHTTPHandler is an initialization of StoreHTTPHandler.
 //
 // Parameters:
-//   - store to add/remove devices.
+//   - store to add/update/remove devices.
 func NewStoreHTTPHandler(store Store) *StoreHTTPHandler {
 	return &StoreHTTPHandler{store: store}
 }
@@ -55,7 +55,7 @@ func (h *StoreHTTPHandler) HandleAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.Add(uri, typ, desc); err != nil {
+	if err := h.store.Add(uri, typ, desc, DeviceOptions{}); err != nil {
 		http.Error(w, fmt.Sprintf("error: failed to add device with uri=%s: %v", uri, err),
 			http.StatusBadRequest)
 
@@ -90,6 +90,65 @@ func (h *StoreHTTPHandler) HandleRemove(w http.ResponseWriter, r *http.Request)
 	htcore.WriteText(w, "OK")
 }
 
+// HandleUpdate changes the device URI, type or description over HTTP API.
+//
+// Remarks:
+//   - Omitted `new_uri`, `type` and `desc` query parameters aren't changed.
+func (h *StoreHTTPHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		http.Error(w, "error: missed `uri` query parameter", http.StatusBadRequest)
+
+		return
+	}
+
+	var (
+		item  StoreItem
+		found bool
+	)
+
+	for _, desc := range h.store.GetDesc() {
+		if desc.URI == uri {
+			item = desc
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		http.Error(w, fmt.Sprintf("error: device not found: uri=%s", uri),
+			http.StatusNotFound)
+
+		return
+	}
+
+	if newURI := r.URL.Query().Get("new_uri"); newURI != "" {
+		item.URI = newURI
+	}
+	if typ := r.URL.Query().Get("type"); typ != "" {
+		item.Type = typ
+	}
+	if desc := r.URL.Query().Get("desc"); desc != "" {
+		item.Desc = desc
+	}
+
+	if err := h.store.Update(uri, item.URI, item.Type, item.Desc); err != nil {
+		http.Error(w, fmt.Sprintf("error: failed to update device with uri=%s: %v", uri, err),
+			http.StatusBadRequest)
+
+		return
+	}
+
+	htcore.WriteText(w, "OK")
+}
+
 // HandleList returns the description of all added devices.
 func (h *StoreHTTPHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
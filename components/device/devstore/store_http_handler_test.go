@@ -0,0 +1,82 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package devstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+func doTestStoreHTTPUpdate(
+	t *testing.T,
+	handler *StoreHTTPHandler,
+	query url.Values,
+) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.HandleUpdate(recorder,
+		httptest.NewRequest(http.MethodGet, "/api/v1/device/update?"+query.Encode(), nil))
+
+	return recorder
+}
+
+func requireTestStoreItem(t *testing.T, store Store, uri string, typ string, desc string) {
+	for _, item := range store.GetDesc() {
+		if item.URI == uri {
+			require.Equal(t, typ, item.Type)
+			require.Equal(t, desc, item.Desc)
+
+			return
+		}
+	}
+
+	require.FailNow(t, "device not found", uri)
+}
+
+func TestStoreHTTPHandlerUpdate(t *testing.T) {
+	clock := &testCacheStoreClock{}
+
+	store := newTestPushHTTPStore(t, clock, newTestDataHandlerBuilder(t))
+	require.Nil(t, store.Add("push://0xABCD", "bonsai-growlab", "home", DeviceOptions{}))
+	require.Nil(t, store.Add("push://0xBEEF", "bonsai-growlab", "kitchen", DeviceOptions{}))
+
+	handler := NewStoreHTTPHandler(store)
+
+	recorder := doTestStoreHTTPUpdate(t, handler, url.Values{
+		"uri":  []string{"push://0xABCD"},
+		"desc": []string{"bedroom"},
+	})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	// Omitted parameters aren't changed.
+	requireTestStoreItem(t, store, "push://0xABCD", "bonsai-growlab", "bedroom")
+
+	recorder = doTestStoreHTTPUpdate(t, handler, url.Values{
+		"uri":  []string{"push://0xCAFE"},
+		"desc": []string{"bedroom"},
+	})
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	require.ErrorIs(t, store.Update("push://0xCAFE", "push://0xCAFE", "bonsai-growlab",
+		"bedroom"), status.StatusNoData)
+
+	recorder = doTestStoreHTTPUpdate(t, handler, url.Values{
+		"uri":     []string{"push://0xABCD"},
+		"new_uri": []string{"push://0xBEEF"},
+	})
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), ErrDeviceExist.Error())
+	require.ErrorIs(t, store.Update("push://0xABCD", "push://0xBEEF", "bonsai-growlab",
+		"bedroom"), ErrDeviceExist)
+
+	// Failed update doesn't change the devices.
+	requireTestStoreItem(t, store, "push://0xABCD", "bonsai-growlab", "bedroom")
+	requireTestStoreItem(t, store, "push://0xBEEF", "bonsai-growlab", "kitchen")
+}
@@ -81,7 +81,7 @@ func (h *StoreMdnsHandler) handleAutodiscovery(
 }
 
 func (h *StoreMdnsHandler) handleAutodiscoveryAdd(uri string, typ string, desc string) error {
-	err := h.store.Add(uri, typ, desc)
+	err := h.store.Add(uri, typ, desc, DeviceOptions{})
 	if err != nil && err != ErrDeviceExist {
 		return err
 	}
@@ -32,7 +32,12 @@ func newTestStoreMdnsHandlerStore() *testStoreMdnsHandlerStore {
 	}
 }
 
-func (s *testStoreMdnsHandlerStore) Add(uri string, typ string, desc string) error {
+func (s *testStoreMdnsHandlerStore) Add(
+	uri string,
+	typ string,
+	desc string,
+	_ DeviceOptions,
+) error {
 	if s.err != nil {
 		return s.err
 	}
@@ -64,6 +69,10 @@ func (s *testStoreMdnsHandlerStore) Remove(uri string) error {
 	return nil
 }
 
+func (*testStoreMdnsHandlerStore) Update(string, string, string, string) error {
+	return status.StatusNotSupported
+}
+
 func (*testStoreMdnsHandlerStore) GetDesc() []StoreItem {
 	return []StoreItem{}
 }
@@ -0,0 +1,93 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// AuditEntry is a single API call that changes the hub state.
+type AuditEntry struct {
+	// Time - time the call was handled, in RFC3339 format.
+	Time string `json:"time"`
+
+	// Token - name of the token used for the call.
+	Token string `json:"token"`
+
+	// Scope - scope of the token used for the call.
+	Scope Scope `json:"scope"`
+
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	RemoteAddr string `json:"remote_addr"`
+
+	// Code - HTTP response status code.
+	Code int `json:"code"`
+}
+
+// AuditLog records the API calls that change the hub state.
+type AuditLog interface {
+	// Record records the API call.
+	Record(entry AuditEntry)
+}
+
+// LogAuditLog records the API calls in the hub log.
+type LogAuditLog struct{}
+
+// Record records the API call in the hub log.
+func (LogAuditLog) Record(entry AuditEntry) {
+	syscore.LogInf.Printf("audit: token=%s scope=%s method=%s path=%s remote_addr=%s"+
+		" code=%d", entry.Token, entry.Scope, entry.Method, entry.Path, entry.RemoteAddr,
+		entry.Code)
+}
+
+// FileAuditLog appends the API calls to the file, one JSON object per line.
+type FileAuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditLog is an initialization of FileAuditLog.
+//
+// Parameters:
+//   - path - audit log file path, it's created if it doesn't exist.
+func NewFileAuditLog(path string) (*FileAuditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileAuditLog{file: file}, nil
+}
+
+// Record appends the API call to the file.
+func (l *FileAuditLog) Record(entry AuditEntry) {
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		syscore.LogErr.Printf("audit: failed to format entry: %v", err)
+
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(buf, '\n')); err != nil {
+		syscore.LogErr.Printf("audit: failed to write entry: %v", err)
+	}
+}
+
+// Stop closes the audit log file.
+func (l *FileAuditLog) Stop() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
@@ -0,0 +1,241 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// anonymousToken is the token name recorded in the audit log for the calls
+// made without the token.
+const anonymousToken = "anonymous"
+
+// Policy returns the scope required to handle the request, and whether the request
+// changes the hub state.
+type Policy func(r *http.Request) (scope Scope, mutating bool)
+
+// PublicPolicy - request doesn't require the token.
+func PublicPolicy(*http.Request) (Scope, bool) {
+	return ScopeNone, false
+}
+
+// ReadPolicy - request requires the token with the provided scope, and doesn't
+// change the hub state.
+func ReadPolicy(scope Scope) Policy {
+	return func(*http.Request) (Scope, bool) {
+		return scope, false
+	}
+}
+
+// WritePolicy - request requires the token with the provided scope, and changes
+// the hub state.
+func WritePolicy(scope Scope) Policy {
+	return func(*http.Request) (Scope, bool) {
+		return scope, true
+	}
+}
+
+// Middleware authorizes requests to the handlers registered in the mux.
+//
+// Remarks:
+//   - Token is expected in the "Authorization: Bearer <token>" header.
+//   - 401 is returned if the token is missed or unknown, 403 if the token scope
+//     is insufficient.
+//   - Requests which change the hub state are recorded in the audit log,
+//     including the rejected ones.
+//   - Requests aren't authorized if the authenticator isn't set, but they're
+//     still recorded in the audit log.
+//   - Requests to the unknown routes are passed to the mux as is.
+type Middleware struct {
+	mux             *http.ServeMux
+	authenticator   Authenticator
+	auditLog        AuditLog
+	clientCertScope Scope
+	policies        map[string]Policy
+}
+
+// NewMiddleware is an initialization of Middleware.
+//
+// Parameters:
+//   - mux to handle the authorized requests.
+func NewMiddleware(mux *http.ServeMux) *Middleware {
+	return &Middleware{
+		mux:      mux,
+		auditLog: LogAuditLog{},
+		policies: make(map[string]Policy),
+	}
+}
+
+// SetAuthenticator sets the authenticator to authorize requests.
+//
+// Remarks:
+//   - Should be called before the first request.
+func (m *Middleware) SetAuthenticator(authenticator Authenticator) {
+	m.authenticator = authenticator
+}
+
+// SetAuditLog sets the log to record the requests which change the hub state.
+//
+// Remarks:
+//   - Requests are recorded in the hub log if the audit log isn't set.
+//   - Should be called before the first request.
+func (m *Middleware) SetAuditLog(auditLog AuditLog) {
+	m.auditLog = auditLog
+}
+
+// SetClientCertScope requires the verified TLS client certificate for the requests
+// with the provided or higher scope.
+//
+// Remarks:
+//   - 403 is returned if the certificate isn't provided, the API token is still
+//     required if the authenticator is set.
+//   - Client certificate isn't required if the scope is ScopeNone.
+//   - Should be called before the first request.
+func (m *Middleware) SetClientCertScope(scope Scope) {
+	m.clientCertScope = scope
+}
+
+// Handle registers the handler for the pattern in the mux, with the access policy.
+func (m *Middleware) Handle(pattern string, policy Policy, handler http.Handler) {
+	m.mux.Handle(pattern, handler)
+	m.policies[pattern] = policy
+}
+
+// HandleFunc registers the handler function for the pattern in the mux,
+// with the access policy.
+func (m *Middleware) HandleFunc(
+	pattern string,
+	policy Policy,
+	handler func(http.ResponseWriter, *http.Request),
+) {
+	m.Handle(pattern, policy, http.HandlerFunc(handler))
+}
+
+// ServeHTTP authorizes the request and passes it to the mux.
+func (m *Middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, pattern := m.mux.Handler(r)
+
+	policy, ok := m.policies[pattern]
+	if !ok {
+		m.mux.ServeHTTP(w, r)
+
+		return
+	}
+
+	scope, mutating := policy(r)
+
+	if !mutating {
+		if _, ok := m.authorize(w, r, scope); ok {
+			m.mux.ServeHTTP(w, r)
+		}
+
+		return
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+
+	info, ok := m.authorize(recorder, r, scope)
+	if ok {
+		m.mux.ServeHTTP(recorder, r)
+	}
+
+	m.auditLog.Record(AuditEntry{
+		Time:       time.Now().Format(time.RFC3339),
+		Token:      info.Name,
+		Scope:      info.Scope,
+		Method:     r.Method,
+		Path:       r.URL.RequestURI(),
+		RemoteAddr: r.RemoteAddr,
+		Code:       recorder.code,
+	})
+}
+
+func (m *Middleware) authorize(
+	w http.ResponseWriter,
+	r *http.Request,
+	scope Scope,
+) (TokenInfo, bool) {
+	anonymous := TokenInfo{Name: anonymousToken}
+
+	if m.clientCertScope != ScopeNone && scope.Allows(m.clientCertScope) &&
+		!hasClientCert(r) {
+		htcore.WriteJSONError(w, http.StatusForbidden,
+			fmt.Sprintf("client certificate required: scope=%s", scope))
+
+		return anonymous, false
+	}
+
+	if m.authenticator == nil || scope == ScopeNone {
+		return anonymous, true
+	}
+
+	token, ok := parseBearerToken(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		htcore.WriteJSONError(w, http.StatusUnauthorized, "missed API token")
+
+		return anonymous, false
+	}
+
+	info, err := m.authenticator.Authenticate(token)
+	if err != nil {
+		if errors.Is(err, status.StatusNoData) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			htcore.WriteJSONError(w, http.StatusUnauthorized, "invalid API token")
+		} else {
+			syscore.LogErr.Printf("failed to authenticate API token: %v", err)
+
+			htcore.WriteJSONError(w, http.StatusInternalServerError,
+				"failed to authenticate API token")
+		}
+
+		return anonymous, false
+	}
+
+	if !info.Scope.Allows(scope) {
+		htcore.WriteJSONError(w, http.StatusForbidden,
+			fmt.Sprintf("insufficient token scope: required=%s actual=%s",
+				scope, info.Scope))
+
+		return info, false
+	}
+
+	return info, true
+}
+
+func hasClientCert(r *http.Request) bool {
+	return r.TLS != nil && len(r.TLS.VerifiedChains) > 0
+}
+
+func parseBearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+// statusRecorder records the response status code.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
@@ -0,0 +1,195 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+)
+
+type testAuditLog struct {
+	entries []AuditEntry
+}
+
+func (l *testAuditLog) Record(entry AuditEntry) {
+	l.entries = append(l.entries, entry)
+}
+
+func newTestMiddleware(t *testing.T, auth bool) (*Middleware, *TokenStore, *testAuditLog) {
+	store, _ := newTestTokenStore(t)
+	auditLog := &testAuditLog{}
+
+	handler := func(w http.ResponseWriter, _ *http.Request) {
+		htcore.WriteText(w, "OK")
+	}
+
+	middleware := NewMiddleware(http.NewServeMux())
+	middleware.SetAuditLog(auditLog)
+	if auth {
+		middleware.SetAuthenticator(store)
+	}
+
+	middleware.HandleFunc("GET /public", PublicPolicy, handler)
+	middleware.HandleFunc("GET /devices", ReadPolicy(ScopeRead), handler)
+	middleware.HandleFunc("POST /ingest", ReadPolicy(ScopeIngest), handler)
+	middleware.HandleFunc("POST /devices", WritePolicy(ScopeDeviceAdmin), handler)
+	middleware.HandleFunc("POST /system", WritePolicy(ScopeSystemAdmin), handler)
+
+	tokenHandler := NewTokenHTTPHandler(store)
+	middleware.HandleFunc("POST /tokens", WritePolicy(ScopeSystemAdmin),
+		tokenHandler.HandleCreate)
+
+	return middleware, store, auditLog
+}
+
+func doTestMiddlewareRequest(
+	m *Middleware,
+	method string,
+	path string,
+	token string,
+	body string,
+) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestMiddlewareScopes(t *testing.T) {
+	middleware, store, auditLog := newTestMiddleware(t, true)
+
+	readToken, _, err := store.Create("grafana", ScopeRead)
+	require.Nil(t, err)
+
+	deviceToken, _, err := store.Create("installer", ScopeDeviceAdmin)
+	require.Nil(t, err)
+
+	ingestToken, _, err := store.Create("sensor", ScopeIngest)
+	require.Nil(t, err)
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		{http.MethodGet, "/public", "", http.StatusOK},
+		{http.MethodGet, "/devices", "", http.StatusUnauthorized},
+		{http.MethodGet, "/devices", "dh_invalid", http.StatusUnauthorized},
+		{http.MethodGet, "/devices", readToken, http.StatusOK},
+		{http.MethodPost, "/devices", readToken, http.StatusForbidden},
+		{http.MethodPost, "/devices", deviceToken, http.StatusOK},
+		{http.MethodPost, "/system", deviceToken, http.StatusForbidden},
+		{http.MethodPost, "/ingest", "", http.StatusUnauthorized},
+		{http.MethodPost, "/ingest", readToken, http.StatusForbidden},
+		{http.MethodPost, "/ingest", ingestToken, http.StatusOK},
+		{http.MethodPost, "/ingest", deviceToken, http.StatusOK},
+		{http.MethodGet, "/devices", ingestToken, http.StatusForbidden},
+		{http.MethodGet, "/unknown", "", http.StatusNotFound},
+	} {
+		w := doTestMiddlewareRequest(middleware, tc.method, tc.path, tc.token, "")
+		require.Equal(t, tc.code, w.Code, "%s %s", tc.method, tc.path)
+	}
+
+	// Only the requests changing the hub state are recorded.
+	require.Equal(t, 3, len(auditLog.entries))
+
+	require.Equal(t, "grafana", auditLog.entries[0].Token)
+	require.Equal(t, http.StatusForbidden, auditLog.entries[0].Code)
+
+	require.Equal(t, "installer", auditLog.entries[1].Token)
+	require.Equal(t, ScopeDeviceAdmin, auditLog.entries[1].Scope)
+	require.Equal(t, "/devices", auditLog.entries[1].Path)
+	require.Equal(t, http.StatusOK, auditLog.entries[1].Code)
+
+	require.Equal(t, "installer", auditLog.entries[2].Token)
+	require.Equal(t, http.StatusForbidden, auditLog.entries[2].Code)
+}
+
+func TestMiddlewareCreateToken(t *testing.T) {
+	middleware, store, auditLog := newTestMiddleware(t, true)
+
+	adminToken, _, err := store.Create("admin", ScopeSystemAdmin)
+	require.Nil(t, err)
+
+	w := doTestMiddlewareRequest(middleware, http.MethodPost, "/tokens", adminToken,
+		`{"name":"grafana","scope":"read"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var res tokenHTTPCreateResponse
+	require.Nil(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Equal(t, "grafana", res.Name)
+	require.Equal(t, ScopeRead, res.Scope)
+
+	w = doTestMiddlewareRequest(middleware, http.MethodGet, "/devices", res.Token, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = doTestMiddlewareRequest(middleware, http.MethodPost, "/tokens", adminToken,
+		`{"name":"grafana","scope":"read"}`)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	w = doTestMiddlewareRequest(middleware, http.MethodPost, "/tokens", adminToken,
+		`{"name":"root","scope":"root"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	require.Equal(t, 3, len(auditLog.entries))
+}
+
+func TestMiddlewareNoAuthenticator(t *testing.T) {
+	middleware, _, auditLog := newTestMiddleware(t, false)
+
+	w := doTestMiddlewareRequest(middleware, http.MethodPost, "/system", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, 1, len(auditLog.entries))
+	require.Equal(t, anonymousToken, auditLog.entries[0].Token)
+}
+
+func TestMiddlewareClientCert(t *testing.T) {
+	middleware, store, auditLog := newTestMiddleware(t, true)
+	middleware.SetClientCertScope(ScopeDeviceAdmin)
+
+	readToken, _, err := store.Create("grafana", ScopeRead)
+	require.Nil(t, err)
+
+	deviceToken, _, err := store.Create("installer", ScopeDeviceAdmin)
+	require.Nil(t, err)
+
+	// Client certificate isn't required for the lower scopes.
+	w := doTestMiddlewareRequest(middleware, http.MethodGet, "/devices", readToken, "")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = doTestMiddlewareRequest(middleware, http.MethodPost, "/devices", deviceToken, "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	r := httptest.NewRequest(http.MethodPost, "/devices", nil)
+	r.Header.Set("Authorization", "Bearer "+deviceToken)
+	r.TLS = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{&x509.Certificate{}}},
+	}
+
+	w = httptest.NewRecorder()
+	middleware.ServeHTTP(w, r)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	require.Equal(t, 2, len(auditLog.entries))
+	require.Equal(t, http.StatusForbidden, auditLog.entries[0].Code)
+	require.Equal(t, http.StatusOK, auditLog.entries[1].Code)
+}
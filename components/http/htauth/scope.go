@@ -0,0 +1,70 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"fmt"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// Scope is an access level granted to the API token.
+//
+// Remarks:
+//   - Scopes are ordered, each scope includes the access of the previous ones:
+//     read, device-admin, system-admin.
+//   - Ingest scope only allows to push the device data, it's included in the
+//     device-admin and system-admin scopes, but not in the read scope.
+type Scope string
+
+const (
+	// ScopeNone - no token is required.
+	ScopeNone Scope = ""
+
+	// ScopeIngest - devices can push their data.
+	ScopeIngest Scope = "ingest"
+
+	// ScopeRead - read-only access to the devices, data and hub state.
+	ScopeRead Scope = "read"
+
+	// ScopeDeviceAdmin - devices can be added, updated and removed.
+	ScopeDeviceAdmin Scope = "device-admin"
+
+	// ScopeSystemAdmin - hub system settings and API tokens can be changed.
+	ScopeSystemAdmin Scope = "system-admin"
+)
+
+// ParseScope parses the scope from the string.
+func ParseScope(str string) (Scope, error) {
+	switch scope := Scope(str); scope {
+	case ScopeIngest, ScopeRead, ScopeDeviceAdmin, ScopeSystemAdmin:
+		return scope, nil
+	default:
+		return ScopeNone, fmt.Errorf("%w: unknown scope: %s", status.StatusInvalidArg, str)
+	}
+}
+
+// Allows returns true if the scope includes the access of the required scope.
+func (s Scope) Allows(required Scope) bool {
+	if required == ScopeIngest {
+		return s == ScopeIngest || s.Allows(ScopeDeviceAdmin)
+	}
+
+	return s.level() >= required.level()
+}
+
+func (s Scope) level() int {
+	switch s {
+	case ScopeRead:
+		return 1
+	case ScopeDeviceAdmin:
+		return 2
+	case ScopeSystemAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,135 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// tokenHTTPMaxBodySize is a maximum allowed size of the token request body.
+const tokenHTTPMaxBodySize = 1024
+
+type tokenHTTPCreateRequest struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+type tokenHTTPCreateResponse struct {
+	TokenInfo
+
+	// Token - the token itself, it can't be retrieved later.
+	Token string `json:"token"`
+}
+
+// TokenHTTPHandler allows to manage API tokens over HTTP API with JSON bodies.
+//
+// Remarks:
+//   - The token name is taken from the "name" path value of the request.
+//   - Errors are returned as JSON: {"code": 404, "message": "..."}.
+//   - HTTP methods are expected to be matched by the mux patterns.
+type TokenHTTPHandler struct {
+	store *TokenStore
+}
+
+// NewTokenHTTPHandler is an initialization of TokenHTTPHandler.
+//
+// Parameters:
+//   - store to manage API tokens.
+func NewTokenHTTPHandler(store *TokenStore) *TokenHTTPHandler {
+	return &TokenHTTPHandler{store: store}
+}
+
+// HandleCreate creates the token, POST /tokens.
+func (h *TokenHTTPHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, tokenHTTPMaxBodySize))
+	decoder.DisallowUnknownFields()
+
+	var req tokenHTTPCreateRequest
+	if err := decoder.Decode(&req); err != nil {
+		htcore.WriteJSONError(w, http.StatusBadRequest,
+			fmt.Sprintf("failed to parse request body: %v", err))
+
+		return
+	}
+
+	scope, err := ParseScope(req.Scope)
+	if err != nil {
+		htcore.WriteJSONError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	token, info, err := h.store.Create(req.Name, scope)
+	if err != nil {
+		writeTokenHTTPError(w, err, fmt.Sprintf("failed to create token: name=%s", req.Name))
+
+		return
+	}
+
+	buf, err := json.Marshal(tokenHTTPCreateResponse{TokenInfo: info, Token: token})
+	if err != nil {
+		htcore.WriteJSONError(w, http.StatusInternalServerError,
+			fmt.Sprintf("failed to format JSON: %v", err))
+
+		return
+	}
+
+	htcore.WriteJSONStatus(w, http.StatusCreated, buf)
+}
+
+// HandleList returns all tokens, without the tokens themselves, GET /tokens.
+func (h *TokenHTTPHandler) HandleList(w http.ResponseWriter, _ *http.Request) {
+	infos, err := h.store.List()
+	if err != nil {
+		writeTokenHTTPError(w, err, "failed to list tokens")
+
+		return
+	}
+
+	buf, err := json.Marshal(infos)
+	if err != nil {
+		htcore.WriteJSONError(w, http.StatusInternalServerError,
+			fmt.Sprintf("failed to format JSON: %v", err))
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
+
+// HandleRemove removes the token, DELETE /tokens/{name}.
+func (h *TokenHTTPHandler) HandleRemove(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	if err := h.store.Remove(name); err != nil {
+		writeTokenHTTPError(w, err, fmt.Sprintf("failed to remove token: name=%s", name))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTokenHTTPError(w http.ResponseWriter, err error, message string) {
+	code := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, ErrTokenExist):
+		code = http.StatusConflict
+	case errors.Is(err, status.StatusNoData):
+		code = http.StatusNotFound
+	case errors.Is(err, status.StatusInvalidArg):
+		code = http.StatusBadRequest
+	}
+
+	htcore.WriteJSONError(w, code, fmt.Sprintf("%s: %v", message, err))
+}
@@ -0,0 +1,212 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+// tokenPrefix allows to recognize the device-hub API tokens, e.g. in the leaked secrets.
+const tokenPrefix = "dh_"
+
+// tokenSize - number of random bytes in the API token.
+const tokenSize = 32
+
+var tokenNameRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ErrTokenExist is returned if the token with the same name already exists.
+var ErrTokenExist = errors.New("token already exists")
+
+// TokenInfo describes the API token, without the token itself.
+type TokenInfo struct {
+	// Name - unique token name, identifies the token owner in the audit log.
+	Name string `json:"name"`
+
+	// Scope - access level granted to the token.
+	Scope Scope `json:"scope"`
+
+	// CreatedAt - time the token was created, in RFC1123 format.
+	CreatedAt string `json:"created_at"`
+}
+
+// Authenticator authenticates the API token.
+type Authenticator interface {
+	// Authenticate returns the information of the provided token.
+	//
+	// Remarks:
+	//  - status.StatusNoData is returned if the token is unknown.
+	Authenticate(token string) (TokenInfo, error)
+}
+
+// TokenStore persists the API tokens.
+//
+// Remarks:
+//   - Only the SHA-256 hash of the token is persisted, the token itself is returned
+//     once, when it's created. Tokens are random, so a slow hash isn't required.
+//   - Tokens are keyed by their hash, to authenticate the token with a single lookup.
+type TokenStore struct {
+	mu sync.Mutex
+	db stcore.DB
+}
+
+// NewTokenStore is an initialization of TokenStore.
+//
+// Parameters:
+//   - db to persist the token hashes.
+func NewTokenStore(db stcore.DB) *TokenStore {
+	return &TokenStore{db: db}
+}
+
+// Create creates a new token with the provided name and scope.
+//
+// Remarks:
+//   - ErrTokenExist is returned if the token with the same name already exists.
+func (s *TokenStore) Create(name string, scope Scope) (string, TokenInfo, error) {
+	if !tokenNameRegexp.MatchString(name) {
+		return "", TokenInfo{}, fmt.Errorf("%w: invalid token name: %q",
+			status.StatusInvalidArg, name)
+	}
+
+	if _, err := ParseScope(string(scope)); err != nil {
+		return "", TokenInfo{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, _, err := s.find(name); err == nil {
+		return "", TokenInfo{}, ErrTokenExist
+	} else if !errors.Is(err, status.StatusNoData) {
+		return "", TokenInfo{}, err
+	}
+
+	secret := make([]byte, tokenSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", TokenInfo{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	token := tokenPrefix + hex.EncodeToString(secret)
+
+	info := TokenInfo{
+		Name:      name,
+		Scope:     scope,
+		CreatedAt: time.Now().Format(time.RFC1123),
+	}
+
+	buf, err := json.Marshal(info)
+	if err != nil {
+		return "", TokenInfo{}, err
+	}
+
+	if err := s.db.Write(hashToken(token), buf); err != nil {
+		return "", TokenInfo{}, fmt.Errorf("failed to persist token: name=%s err=%w",
+			name, err)
+	}
+
+	return token, info, nil
+}
+
+// Remove removes the token with the provided name.
+//
+// Remarks:
+//   - status.StatusNoData is returned if the token doesn't exist.
+func (s *TokenStore) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, _, err := s.find(name)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Remove(key)
+}
+
+// List returns all tokens.
+func (s *TokenStore) List() ([]TokenInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := []TokenInfo{}
+
+	err := s.db.ForEach(func(_ string, buf []byte) error {
+		var info TokenInfo
+		if err := json.Unmarshal(buf, &info); err != nil {
+			return err
+		}
+
+		infos = append(infos, info)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// Authenticate returns the information of the provided token.
+func (s *TokenStore) Authenticate(token string) (TokenInfo, error) {
+	buf, err := s.db.Read(hashToken(token))
+	if err != nil {
+		return TokenInfo{}, err
+	}
+
+	var info TokenInfo
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return TokenInfo{}, err
+	}
+
+	return info, nil
+}
+
+func (s *TokenStore) find(name string) (string, TokenInfo, error) {
+	var (
+		key   string
+		found TokenInfo
+	)
+
+	err := s.db.ForEach(func(k string, buf []byte) error {
+		var info TokenInfo
+		if err := json.Unmarshal(buf, &info); err != nil {
+			return err
+		}
+
+		if info.Name == name {
+			key = k
+			found = info
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", TokenInfo{}, err
+	}
+
+	if key == "" {
+		return "", TokenInfo{}, status.StatusNoData
+	}
+
+	return key, found, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
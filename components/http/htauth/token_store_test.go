@@ -0,0 +1,116 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htauth
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+func newTestTokenStore(t *testing.T) (*TokenStore, *stcore.BboltDBBucket) {
+	db, err := stcore.NewBboltDB(filepath.Join(t.TempDir(), "bbolt.db"), nil)
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+
+	bucket := stcore.NewBboltDBBucket(db, "token_bucket")
+
+	return NewTokenStore(bucket), bucket
+}
+
+func TestTokenStoreCreateAuthenticate(t *testing.T) {
+	store, bucket := newTestTokenStore(t)
+
+	token, info, err := store.Create("grafana", ScopeRead)
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(token, tokenPrefix))
+	require.Equal(t, "grafana", info.Name)
+	require.Equal(t, ScopeRead, info.Scope)
+
+	res, err := store.Authenticate(token)
+	require.Nil(t, err)
+	require.Equal(t, info, res)
+
+	_, err = store.Authenticate(token + "0")
+	require.Equal(t, status.StatusNoData, err)
+
+	// Only the token hash is persisted.
+	require.Nil(t, bucket.ForEach(func(key string, buf []byte) error {
+		require.NotContains(t, key, token)
+		require.NotContains(t, string(buf), token)
+
+		return nil
+	}))
+}
+
+func TestTokenStoreCreateInvalid(t *testing.T) {
+	store, _ := newTestTokenStore(t)
+
+	_, _, err := store.Create("", ScopeRead)
+	require.ErrorIs(t, err, status.StatusInvalidArg)
+
+	_, _, err = store.Create("foo bar", ScopeRead)
+	require.ErrorIs(t, err, status.StatusInvalidArg)
+
+	_, _, err = store.Create("foo", Scope("root"))
+	require.ErrorIs(t, err, status.StatusInvalidArg)
+
+	_, _, err = store.Create("foo", ScopeDeviceAdmin)
+	require.Nil(t, err)
+
+	_, _, err = store.Create("foo", ScopeRead)
+	require.Equal(t, ErrTokenExist, err)
+}
+
+func TestTokenStoreRemove(t *testing.T) {
+	store, _ := newTestTokenStore(t)
+
+	require.Equal(t, status.StatusNoData, store.Remove("foo"))
+
+	token, _, err := store.Create("foo", ScopeSystemAdmin)
+	require.Nil(t, err)
+
+	_, _, err = store.Create("bar", ScopeRead)
+	require.Nil(t, err)
+
+	infos, err := store.List()
+	require.Nil(t, err)
+	require.Equal(t, 2, len(infos))
+
+	require.Nil(t, store.Remove("foo"))
+
+	_, err = store.Authenticate(token)
+	require.Equal(t, status.StatusNoData, err)
+
+	infos, err = store.List()
+	require.Nil(t, err)
+	require.Equal(t, 1, len(infos))
+	require.Equal(t, "bar", infos[0].Name)
+}
+
+func TestScopeAllows(t *testing.T) {
+	require.True(t, ScopeSystemAdmin.Allows(ScopeDeviceAdmin))
+	require.True(t, ScopeDeviceAdmin.Allows(ScopeRead))
+	require.True(t, ScopeRead.Allows(ScopeNone))
+	require.False(t, ScopeRead.Allows(ScopeDeviceAdmin))
+	require.False(t, ScopeDeviceAdmin.Allows(ScopeSystemAdmin))
+	require.False(t, ScopeNone.Allows(ScopeRead))
+
+	require.True(t, ScopeIngest.Allows(ScopeIngest))
+	require.True(t, ScopeDeviceAdmin.Allows(ScopeIngest))
+	require.True(t, ScopeSystemAdmin.Allows(ScopeIngest))
+	require.False(t, ScopeRead.Allows(ScopeIngest))
+	require.False(t, ScopeNone.Allows(ScopeIngest))
+	require.False(t, ScopeIngest.Allows(ScopeRead))
+}
@@ -0,0 +1,128 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htcore
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// CertReloader loads the TLS certificate from the files, and reloads it once
+// the files are changed.
+//
+// Remarks:
+//   - Run() should be called periodically to check whether the files are changed.
+//   - The previous certificate is kept if the changed files can't be loaded.
+type CertReloader struct {
+	certPath string
+	keyPath  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// NewCertReloader is an initialization of CertReloader.
+//
+// Parameters:
+//   - certPath - PEM encoded certificate file path.
+//   - keyPath - PEM encoded private key file path.
+func NewCertReloader(certPath string, keyPath string) (*CertReloader, error) {
+	r := &CertReloader{
+		certPath: certPath,
+		keyPath:  keyPath,
+	}
+
+	modTime, err := r.getModTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the latest loaded certificate.
+//
+// Remarks:
+//   - Implements tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// Run reloads the certificate if the files are changed.
+func (r *CertReloader) Run() error {
+	modTime, err := r.getModTime()
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	changed := !modTime.Equal(r.modTime)
+	r.mu.RUnlock()
+
+	if !changed {
+		return nil
+	}
+
+	if err := r.load(modTime); err != nil {
+		return err
+	}
+
+	syscore.LogInf.Printf("TLS certificate reloaded: path=%s", r.certPath)
+
+	return nil
+}
+
+// HandleError handles Run() error.
+func (*CertReloader) HandleError(err error) {
+	syscore.LogErr.Printf("failed to reload TLS certificate: %v", err)
+}
+
+func (r *CertReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: cert=%s key=%s err=%w",
+			r.certPath, r.keyPath, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.modTime = modTime
+
+	return nil
+}
+
+// getModTime returns the latest modification time of the certificate and key files.
+func (r *CertReloader) getModTime() (time.Time, error) {
+	var modTime time.Time
+
+	for _, path := range []string{r.certPath, r.keyPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	return modTime, nil
+}
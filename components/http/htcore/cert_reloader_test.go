@@ -0,0 +1,131 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htcore
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertPool(t *testing.T, certPath string) *x509.CertPool {
+	buf, err := os.ReadFile(certPath)
+	require.Nil(t, err)
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(buf))
+
+	return pool
+}
+
+func TestEnsureSelfSignedCert(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	require.Nil(t, EnsureSelfSignedCert(certPath, keyPath,
+		[]string{"localhost", "127.0.0.1"}, time.Hour))
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.Nil(t, err)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.Nil(t, err)
+	require.Equal(t, []string{"localhost"}, leaf.DNSNames)
+	require.Equal(t, 1, len(leaf.IPAddresses))
+
+	// Existing certificate is kept.
+	buf, err := os.ReadFile(certPath)
+	require.Nil(t, err)
+
+	require.Nil(t, EnsureSelfSignedCert(certPath, keyPath, nil, time.Hour))
+
+	newBuf, err := os.ReadFile(certPath)
+	require.Nil(t, err)
+	require.Equal(t, buf, newBuf)
+
+	// Key without certificate isn't overwritten.
+	require.Nil(t, os.Remove(certPath))
+	require.NotNil(t, EnsureSelfSignedCert(certPath, keyPath, nil, time.Hour))
+}
+
+func TestCertReloaderServer(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	require.Nil(t, EnsureSelfSignedCert(certPath, keyPath,
+		[]string{"127.0.0.1"}, time.Hour))
+
+	reloader, err := NewCertReloader(certPath, keyPath)
+	require.Nil(t, err)
+
+	server, err := NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		WriteText(w, "OK")
+	}), ServerParams{
+		Host: "127.0.0.1",
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		},
+	})
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(server.URL(), "https://"))
+
+	require.Nil(t, server.Start())
+	defer func() {
+		require.Nil(t, server.Stop())
+	}()
+
+	get := func(pool *x509.CertPool) error {
+		client := &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12,
+					RootCAs:    pool,
+				},
+			},
+		}
+		defer client.CloseIdleConnections()
+
+		resp, err := client.Get(server.URL())
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		return nil
+	}
+
+	oldPool := newTestCertPool(t, certPath)
+	require.Nil(t, get(oldPool))
+
+	// Files aren't changed.
+	require.Nil(t, reloader.Run())
+
+	require.Nil(t, os.Remove(certPath))
+	require.Nil(t, os.Remove(keyPath))
+	require.Nil(t, EnsureSelfSignedCert(certPath, keyPath,
+		[]string{"127.0.0.1"}, time.Hour))
+
+	// Make sure the modification time is changed on the coarse file systems.
+	future := time.Now().Add(time.Minute)
+	require.Nil(t, os.Chtimes(certPath, future, future))
+
+	require.Nil(t, reloader.Run())
+
+	require.NotNil(t, get(oldPool))
+	require.Nil(t, get(newTestCertPool(t, certPath)))
+}
@@ -6,6 +6,9 @@
 package htcore
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 
@@ -16,6 +19,8 @@ import (
 // HTTPClient is a standard HTTP client wrapper that simplifies reading responses.
 type HTTPClient struct {
 	http.Client
+
+	header http.Header
 }
 
 // NewDefaultClient creates a general purpose HTTP client.
@@ -23,17 +28,61 @@ func NewDefaultClient() *HTTPClient {
 	return &HTTPClient{}
 }
 
+// NewTLSClient creates HTTP client with custom TLS configuration.
+func NewTLSClient(tlsConfig *tls.Config) *HTTPClient {
+	return &HTTPClient{
+		Client: http.Client{
+			Transport: newTransport(tlsConfig),
+		},
+	}
+}
+
 // NewResolveClient creates HTTP client with custom resolving rules.
-func NewResolveClient(resolver sysnet.Resolver) *HTTPClient {
+//
+// Remarks:
+//   - tlsConfig is optional, default TLS configuration is used if nil.
+//   - tlsConfig.ServerName should be set for HTTPS, since the request hostname
+//     is replaced with the resolved address.
+func NewResolveClient(resolver sysnet.Resolver, tlsConfig *tls.Config) *HTTPClient {
 	return &HTTPClient{
 		Client: http.Client{
-			Transport: httransport.NewResolveRoundTripper(resolver, http.DefaultTransport),
+			Transport: httransport.NewResolveRoundTripper(resolver, newTransport(tlsConfig)),
 		},
 	}
 }
 
+// SetHeader sets the headers sent with each request, e.g. credentials.
+//
+// Remarks:
+//   - Headers are set by Do() for each request, existing request headers are replaced.
+//   - Redirects to another host are refused, so the headers aren't sent to it.
+//   - Should be called before the first request.
+func (c *HTTPClient) SetHeader(header http.Header) {
+	c.header = header.Clone()
+	c.CheckRedirect = checkSameHostRedirect
+}
+
+func newTransport(tlsConfig *tls.Config) http.RoundTripper {
+	if tlsConfig == nil {
+		return http.DefaultTransport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return transport
+}
+
 // Do sends a request, receives a response, and fully reads the response body.
 func (c *HTTPClient) Do(req *http.Request) (*http.Response, []byte, error) {
+	if len(c.header) != 0 {
+		req = req.Clone(req.Context())
+
+		for key, values := range c.header {
+			req.Header[key] = values
+		}
+	}
+
 	resp, err := c.Client.Do(req)
 	if err != nil {
 		return nil, nil, err
@@ -56,3 +105,18 @@ func (c *HTTPClient) Do(req *http.Request) (*http.Response, []byte, error) {
 
 	return resp, body, nil
 }
+
+// checkSameHostRedirect refuses redirects to another host, since the request
+// headers, e.g. credentials, are copied to the redirected request.
+func checkSameHostRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	if host := via[0].URL.Host; req.URL.Host != host {
+		return fmt.Errorf("redirect to another host is refused: from=%s to=%s",
+			host, req.URL.Host)
+	}
+
+	return nil
+}
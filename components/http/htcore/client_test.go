@@ -0,0 +1,87 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htcore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClientHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request,
+	) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/data", http.StatusFound)
+
+			return
+		}
+
+		WriteText(w, r.Header.Get("X-Device-Token"))
+	}))
+	defer server.Close()
+
+	client := NewDefaultClient()
+	client.SetHeader(http.Header{"X-Device-Token": []string{"secret"}})
+
+	for _, path := range []string{"/data", "/redirect"} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			server.URL+path, nil)
+		require.Nil(t, err)
+
+		resp, body, err := client.Do(req)
+		require.Nil(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "secret", string(body))
+
+		// Request provided by the caller isn't modified.
+		require.Empty(t, req.Header)
+	}
+}
+
+func TestHTTPClientHeaderCrossHostRedirect(t *testing.T) {
+	var leaked atomic.Bool
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request,
+	) {
+		if r.Header.Get("Authorization") != "" || r.Header.Get("X-Device-Token") != "" {
+			leaked.Store(true)
+		}
+
+		WriteText(w, "OK")
+	}))
+	defer target.Close()
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request,
+	) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer source.Close()
+
+	for _, header := range []http.Header{
+		{"Authorization": []string{"Bearer secret"}},
+		{"X-Device-Token": []string{"secret"}},
+	} {
+		client := NewDefaultClient()
+		client.SetHeader(header)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet,
+			source.URL, nil)
+		require.Nil(t, err)
+
+		_, _, err = client.Do(req)
+		require.NotNil(t, err)
+	}
+
+	require.False(t, leaked.Load())
+}
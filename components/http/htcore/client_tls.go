@@ -0,0 +1,135 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htcore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// ClientTLSParams provides various TLS configuration options for the HTTPS client.
+type ClientTLSParams struct {
+	// CAPath - PEM encoded CA bundle file path to verify the server certificate.
+	//
+	// Remarks:
+	//  - System CA is used if empty.
+	CAPath string
+
+	// Fingerprint - hex encoded SHA-256 fingerprint of the server certificate.
+	//
+	// Remarks:
+	//  - Colons between the bytes are allowed.
+	//  - Server certificate isn't verified against CA if the fingerprint is set
+	//    and the CA bundle isn't, e.g. for the self-signed certificates.
+	Fingerprint string
+
+	// CertPath - PEM encoded client certificate file path.
+	CertPath string
+
+	// KeyPath - PEM encoded client private key file path.
+	KeyPath string
+
+	// ServerName - name to verify the server certificate and to send in SNI.
+	//
+	// Remarks:
+	//  - Request hostname is used if empty.
+	ServerName string
+}
+
+// NewClientTLSConfig builds the TLS configuration for the HTTPS client.
+//
+// Remarks:
+//   - status.StatusInvalidArg is returned if the parameters are inconsistent.
+func NewClientTLSConfig(params ClientTLSParams) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: params.ServerName,
+	}
+
+	if params.CAPath != "" {
+		buf, err := os.ReadFile(params.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read CA bundle: %v",
+				status.StatusInvalidArg, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("%w: failed to parse CA bundle: path=%s",
+				status.StatusInvalidArg, params.CAPath)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if params.CertPath != "" || params.KeyPath != "" {
+		if params.CertPath == "" || params.KeyPath == "" {
+			return nil, fmt.Errorf("%w: both client certificate and key should be set",
+				status.StatusInvalidArg)
+		}
+
+		cert, err := tls.LoadX509KeyPair(params.CertPath, params.KeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to load client certificate: %v",
+				status.StatusInvalidArg, err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if params.Fingerprint != "" {
+		fingerprint, err := ParseFingerprint(params.Fingerprint)
+		if err != nil {
+			return nil, err
+		}
+
+		// If the CA bundle is set, the certificate chain is still verified against
+		// RootCAs by the default verification, and VerifyConnection() only checks
+		// the fingerprint in addition. Otherwise, the fingerprint is the only check.
+		if config.RootCAs == nil {
+			config.InsecureSkipVerify = true
+		}
+
+		config.VerifyConnection = func(state tls.ConnectionState) error {
+			return verifyFingerprint(state, fingerprint)
+		}
+	}
+
+	return config, nil
+}
+
+// ParseFingerprint parses hex encoded SHA-256 certificate fingerprint.
+func ParseFingerprint(str string) ([]byte, error) {
+	fingerprint, err := hex.DecodeString(strings.ReplaceAll(str, ":", ""))
+	if err != nil || len(fingerprint) != sha256.Size {
+		return nil, fmt.Errorf("%w: invalid SHA-256 fingerprint: %s",
+			status.StatusInvalidArg, str)
+	}
+
+	return fingerprint, nil
+}
+
+func verifyFingerprint(state tls.ConnectionState, fingerprint []byte) error {
+	if len(state.PeerCertificates) == 0 {
+		return fmt.Errorf("server certificate is missed")
+	}
+
+	sum := sha256.Sum256(state.PeerCertificates[0].Raw)
+	if !bytes.Equal(sum[:], fingerprint) {
+		return fmt.Errorf("server certificate fingerprint mismatch: actual=%s",
+			hex.EncodeToString(sum[:]))
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htcore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+func newTestTLSServer(t *testing.T) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter,
+		_ *http.Request,
+	) {
+		WriteText(w, "OK")
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func doTestTLSRequest(server *httptest.Server, params ClientTLSParams) error {
+	config, err := NewClientTLSConfig(params)
+	if err != nil {
+		return err
+	}
+
+	client := NewTLSClient(config)
+	defer client.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.Do(req)
+
+	return err
+}
+
+func TestClientTLSConfigFingerprint(t *testing.T) {
+	server := newTestTLSServer(t)
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	// Certificate isn't trusted by the system CA.
+	require.NotNil(t, doTestTLSRequest(server, ClientTLSParams{}))
+
+	require.Nil(t, doTestTLSRequest(server, ClientTLSParams{Fingerprint: fingerprint}))
+
+	// Colon separated upper case fingerprint.
+	var parts []string
+	for n := 0; n < len(fingerprint); n += 2 {
+		parts = append(parts, strings.ToUpper(fingerprint[n:n+2]))
+	}
+	require.Nil(t, doTestTLSRequest(server,
+		ClientTLSParams{Fingerprint: strings.Join(parts, ":")}))
+
+	sum[0]++
+	require.NotNil(t, doTestTLSRequest(server,
+		ClientTLSParams{Fingerprint: hex.EncodeToString(sum[:])}))
+}
+
+func TestClientTLSConfigCA(t *testing.T) {
+	server := newTestTLSServer(t)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	require.Nil(t, os.WriteFile(caPath, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	require.Nil(t, doTestTLSRequest(server, ClientTLSParams{CAPath: caPath}))
+
+	// Server name isn't in the certificate.
+	require.NotNil(t, doTestTLSRequest(server,
+		ClientTLSParams{CAPath: caPath, ServerName: "device.local"}))
+
+	require.Nil(t, doTestTLSRequest(server,
+		ClientTLSParams{CAPath: caPath, ServerName: "example.com"}))
+}
+
+func TestClientTLSConfigInvalid(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	require.Nil(t, EnsureSelfSignedCert(certPath, keyPath, []string{"localhost"}, time.Hour))
+
+	config, err := NewClientTLSConfig(ClientTLSParams{CertPath: certPath, KeyPath: keyPath})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(config.Certificates))
+
+	for _, params := range []ClientTLSParams{
+		{CAPath: filepath.Join(dir, "unknown.pem")},
+		{CAPath: keyPath},
+		{Fingerprint: "abcd"},
+		{Fingerprint: strings.Repeat("zz", sha256.Size)},
+		{CertPath: certPath},
+		{CertPath: keyPath, KeyPath: certPath},
+	} {
+		_, err := NewClientTLSConfig(params)
+		require.ErrorIs(t, err, status.StatusInvalidArg)
+	}
+}
@@ -6,6 +6,7 @@
 package htcore
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 )
@@ -24,9 +25,46 @@ func WriteText(w http.ResponseWriter, text string) {
 
 // WriteJSON writes JSON to HTTP response.
 func WriteJSON(w http.ResponseWriter, buf []byte) {
+	WriteJSONStatus(w, http.StatusOK, buf)
+}
+
+// WriteJSONStatus writes JSON to HTTP response with the provided status code.
+func WriteJSONStatus(w http.ResponseWriter, code int, buf []byte) {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Content-Length", strconv.Itoa(len(buf)))
 
+	w.WriteHeader(code)
+
+	if _, err := w.Write(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WriteJSONError writes the error to HTTP response as JSON:
+//
+//	{"code": 404, "message": "device not found"}
+func WriteJSONError(w http.ResponseWriter, code int, message string) {
+	buf, err := json.Marshal(struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{
+		Code:    code,
+		Message: message,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	WriteJSONStatus(w, code, buf)
+}
+
+// WriteCSV writes CSV to HTTP response.
+func WriteCSV(w http.ResponseWriter, buf []byte) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(buf)))
+
 	w.WriteHeader(http.StatusOK)
 
 	if _, err := w.Write(buf); err != nil {
@@ -0,0 +1,142 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package htcore
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// EnsureSelfSignedCert generates the self-signed certificate and persists it,
+// if the certificate or key file doesn't exist.
+//
+// Parameters:
+//   - certPath - PEM encoded certificate file path.
+//   - keyPath - PEM encoded private key file path.
+//   - hosts - DNS names and IP addresses the certificate is valid for.
+//   - validity - how long the certificate is valid.
+//
+// Remarks:
+//   - Existing files are never overwritten, remove them to generate a new certificate.
+func EnsureSelfSignedCert(
+	certPath string,
+	keyPath string,
+	hosts []string,
+	validity time.Duration,
+) error {
+	certExist, err := isFileExist(certPath)
+	if err != nil {
+		return err
+	}
+
+	keyExist, err := isFileExist(keyPath)
+	if err != nil {
+		return err
+	}
+
+	if certExist && keyExist {
+		return nil
+	}
+
+	if certExist || keyExist {
+		return fmt.Errorf("both certificate and key files should exist or be missed:"+
+			" cert=%s key=%s", certPath, keyPath)
+	}
+
+	certBuf, keyBuf, err := generateSelfSignedCert(hosts, validity)
+	if err != nil {
+		return fmt.Errorf("failed to generate self-signed certificate: %w", err)
+	}
+
+	if err := os.WriteFile(keyPath, keyBuf, 0600); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(certPath, certBuf, 0644); err != nil {
+		return err
+	}
+
+	syscore.LogInf.Printf("self-signed TLS certificate generated: path=%s hosts=%v",
+		certPath, hosts)
+
+	return nil
+}
+
+func generateSelfSignedCert(hosts []string, validity time.Duration) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	template := x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"device-hub"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	if len(hosts) > 0 {
+		template.Subject.CommonName = hosts[0]
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), nil
+}
+
+func isFileExist(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
@@ -6,6 +6,7 @@
 package htcore
 
 import (
+	"crypto/tls"
 	"net"
 	"net/http"
 	"strconv"
@@ -26,6 +27,9 @@ type Server struct {
 type ServerParams struct {
 	Host string
 	Port int
+
+	// TLSConfig - HTTPS is served if set, plain HTTP otherwise.
+	TLSConfig *tls.Config
 }
 
 // NewServer creates a new server.
@@ -34,6 +38,7 @@ type ServerParams struct {
 //   - The server is not started.
 //   - If host is empty, "0.0.0.0" is used.
 //   - If port is zero, a random free port is chosen.
+//   - If TLS configuration is provided, HTTPS is served and URL() returns https://.
 //
 // References:
 //   - The implementation is based on the httptest.Server.
@@ -55,14 +60,23 @@ func NewServer(handler http.Handler, params ServerParams) (*Server, error) {
 		params.Port = ln.Addr().(*net.TCPAddr).Port
 	}
 
+	scheme := "http://"
+
+	var listener net.Listener = ln
+	if params.TLSConfig != nil {
+		scheme = "https://"
+		listener = tls.NewListener(ln, params.TLSConfig)
+	}
+
 	return &Server{
 		server: http.Server{
-			Addr:    addr.String(),
-			Handler: handler,
+			Addr:      addr.String(),
+			Handler:   handler,
+			TLSConfig: params.TLSConfig,
 		},
-		ln:     ln,
+		ln:     listener,
 		doneCh: make(chan struct{}),
-		url:    "http://" + ln.Addr().String(),
+		url:    scheme + ln.Addr().String(),
 		port:   params.Port,
 	}, nil
 }
@@ -85,7 +99,8 @@ func (s *Server) Stop() error {
 	return err
 }
 
-// URL returns base URL of form http://ipaddr:port with no trailing slash.
+// URL returns base URL of form http://ipaddr:port or https://ipaddr:port with
+// no trailing slash.
 func (s *Server) URL() string {
 	return s.url
 }
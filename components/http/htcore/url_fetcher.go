@@ -15,6 +15,7 @@ import (
 // URLFetcher sends requests to the configured HTTP endpoint.
 type URLFetcher struct {
 	ctx     context.Context
+	method  string
 	url     string
 	timeout time.Duration
 	client  *HTTPClient
@@ -25,16 +26,19 @@ type URLFetcher struct {
 // Parameters:
 //   - ctx to pass to the HTTP request.
 //   - client to perform an actual HTTP request.
+//   - method - HTTP method, e.g. GET or POST.
 //   - url - HTTP URL.
 //   - timeout - HTTP request timeout.
 func NewURLFetcher(
 	ctx context.Context,
 	client *HTTPClient,
+	method string,
 	url string,
 	timeout time.Duration,
 ) *URLFetcher {
 	return &URLFetcher{
 		ctx:     ctx,
+		method:  method,
 		url:     url,
 		timeout: timeout,
 		client:  client,
@@ -46,7 +50,7 @@ func (f *URLFetcher) Fetch() ([]byte, error) {
 	ctx, cancel := context.WithTimeout(f.ctx, f.timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", f.url, nil)
+	req, err := http.NewRequestWithContext(ctx, f.method, f.url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,45 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package hthandler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+// WatchdogHandler returns the tasks which have exceeded the run deadline over HTTP.
+type WatchdogHandler struct {
+	watchdog *syssched.Watchdog
+}
+
+// NewWatchdogHandler is an initialization of WatchdogHandler.
+func NewWatchdogHandler(watchdog *syssched.Watchdog) *WatchdogHandler {
+	return &WatchdogHandler{
+		watchdog: watchdog,
+	}
+}
+
+// ServeHTTP implements an HTTP endpoint logic.
+func (h *WatchdogHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	stalls := h.watchdog.GetStalls()
+	if stalls == nil {
+		stalls = []syssched.WatchdogStall{}
+	}
+
+	buf, err := json.Marshal(stalls)
+	if err != nil {
+		htcore.WriteJSONError(w, http.StatusInternalServerError,
+			fmt.Sprintf("failed to format JSON: %v", err))
+
+		return
+	}
+
+	htcore.WriteJSON(w, buf)
+}
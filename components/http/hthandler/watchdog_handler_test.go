@@ -0,0 +1,62 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package hthandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+type testWatchdogTask struct {
+	ctx context.Context
+}
+
+func (t *testWatchdogTask) Run() error {
+	<-t.ctx.Done()
+
+	return t.ctx.Err()
+}
+
+func TestWatchdogHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchdog := syssched.NewWatchdog(syssched.WatchdogParams{
+		Deadline: time.Millisecond * 10,
+	})
+	handler := NewWatchdogHandler(watchdog)
+
+	get := func() []syssched.WatchdogStall {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/stalls", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stalls []syssched.WatchdogStall
+		require.Nil(t, json.Unmarshal(w.Body.Bytes(), &stalls))
+
+		return stalls
+	}
+
+	require.Empty(t, get())
+
+	task := watchdog.Watch(ctx, "device", func(ctx context.Context) syssched.Task {
+		return &testWatchdogTask{ctx: ctx}
+	})
+	require.ErrorIs(t, task.Run(), context.Canceled)
+
+	stalls := get()
+	require.Len(t, stalls, 1)
+	require.Equal(t, "device", stalls[0].Task)
+	require.Equal(t, 1, stalls[0].Count)
+}
@@ -0,0 +1,467 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mqtest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+const (
+	packetConnect     = 1
+	packetConnack     = 2
+	packetPublish     = 3
+	packetPuback      = 4
+	packetPubrec      = 5
+	packetPubrel      = 6
+	packetPubcomp     = 7
+	packetSubscribe   = 8
+	packetSuback      = 9
+	packetUnsubscribe = 10
+	packetUnsuback    = 11
+	packetPingreq     = 12
+	packetPingresp    = 13
+	packetDisconnect  = 14
+)
+
+// maxRemainingLength is a maximum packet size allowed by the MQTT 3.1.1 specification.
+const maxRemainingLength = 268435455
+
+// BrokerParams contains broker parameters.
+type BrokerParams struct {
+	Host string
+	Port int
+}
+
+// Broker is a minimal MQTT 3.1.1 broker.
+//
+// Remarks:
+//   - Sessions, retained messages and wills aren't supported.
+//   - Messages are always delivered to subscribers with QoS 0.
+//   - Intended for tests only, where the external broker isn't available.
+type Broker struct {
+	ln     net.Listener
+	doneCh chan struct{}
+	url    string
+	port   int
+
+	mu    sync.Mutex
+	wg    sync.WaitGroup
+	conns map[*brokerConn]struct{}
+}
+
+// NewBroker creates a new broker.
+//
+// Notes:
+//   - The broker is not started.
+//   - If host is empty, "0.0.0.0" is used.
+//   - If port is zero, a random free port is chosen.
+func NewBroker(params BrokerParams) (*Broker, error) {
+	if params.Host == "" {
+		params.Host = "0.0.0.0"
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(params.Host, strconv.Itoa(params.Port)))
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Port == 0 {
+		params.Port = ln.Addr().(*net.TCPAddr).Port
+	}
+
+	return &Broker{
+		ln:     ln,
+		doneCh: make(chan struct{}),
+		url:    "tcp://" + ln.Addr().String(),
+		port:   params.Port,
+		conns:  make(map[*brokerConn]struct{}),
+	}, nil
+}
+
+// Start runs the broker.
+func (b *Broker) Start() error {
+	go b.run()
+
+	return nil
+}
+
+// Stop closes all client connections and waits until the broker finishes.
+func (b *Broker) Stop() error {
+	err := b.ln.Close()
+
+	<-b.doneCh
+
+	b.mu.Lock()
+	for conn := range b.conns {
+		_ = conn.conn.Close()
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+
+	return err
+}
+
+// URL returns broker URL of form tcp://ipaddr:port.
+func (b *Broker) URL() string {
+	return b.url
+}
+
+// Port returns the port to which the broker socket is bound.
+func (b *Broker) Port() int {
+	return b.port
+}
+
+func (b *Broker) run() {
+	defer close(b.doneCh)
+
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				syscore.LogErr.Printf("mqtt-broker: failed to accept connection: %v", err)
+			}
+
+			return
+		}
+
+		bc := &brokerConn{
+			broker:        b,
+			conn:          conn,
+			reader:        bufio.NewReader(conn),
+			subscriptions: make(map[string]struct{}),
+		}
+
+		b.mu.Lock()
+		b.conns[bc] = struct{}{}
+		b.wg.Add(1)
+		b.mu.Unlock()
+
+		go func() {
+			defer b.wg.Done()
+
+			bc.serve()
+
+			b.mu.Lock()
+			delete(b.conns, bc)
+			b.mu.Unlock()
+		}()
+	}
+}
+
+func (b *Broker) publish(topic string, payload []byte) {
+	b.mu.Lock()
+	var subscribers []*brokerConn
+	for conn := range b.conns {
+		if conn.isSubscribed(topic) {
+			subscribers = append(subscribers, conn)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, conn := range subscribers {
+		var body []byte
+		body = appendString(body, topic)
+		body = append(body, payload...)
+
+		if err := conn.write(packetPublish<<4, body); err != nil {
+			syscore.LogWrn.Printf("mqtt-broker: failed to deliver message: topic=%s err=%v",
+				topic, err)
+		}
+	}
+}
+
+type brokerConn struct {
+	broker *Broker
+	conn   net.Conn
+	reader *bufio.Reader
+
+	writeMu sync.Mutex
+
+	subMu         sync.Mutex
+	subscriptions map[string]struct{}
+}
+
+func (c *brokerConn) serve() {
+	defer func() {
+		_ = c.conn.Close()
+	}()
+
+	connected := false
+
+	for {
+		header, body, err := c.read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				syscore.LogWrn.Printf("mqtt-broker: failed to read packet: remote=%s err=%v",
+					c.conn.RemoteAddr(), err)
+			}
+
+			return
+		}
+
+		typ := header >> 4
+
+		if !connected && typ != packetConnect {
+			syscore.LogWrn.Printf("mqtt-broker: packet received before CONNECT: remote=%s",
+				c.conn.RemoteAddr())
+
+			return
+		}
+
+		if typ == packetDisconnect {
+			return
+		}
+
+		if typ == packetConnect {
+			connected = true
+		}
+
+		if err := c.handle(header, body); err != nil {
+			syscore.LogWrn.Printf("mqtt-broker: failed to handle packet: remote=%s err=%v",
+				c.conn.RemoteAddr(), err)
+
+			return
+		}
+	}
+}
+
+func (c *brokerConn) handle(header byte, body []byte) error {
+	switch header >> 4 {
+	case packetConnect:
+		return c.write(packetConnack<<4, []byte{0x00, 0x00})
+
+	case packetPublish:
+		return c.handlePublish(header, body)
+
+	case packetPubrel:
+		if len(body) != 2 {
+			return fmt.Errorf("invalid PUBREL packet")
+		}
+
+		return c.write(packetPubcomp<<4, body)
+
+	case packetSubscribe:
+		return c.handleSubscribe(body)
+
+	case packetUnsubscribe:
+		return c.handleUnsubscribe(body)
+
+	case packetPingreq:
+		return c.write(packetPingresp<<4, nil)
+
+	default:
+		return fmt.Errorf("unsupported packet: type=%d", header>>4)
+	}
+}
+
+func (c *brokerConn) handlePublish(header byte, body []byte) error {
+	topic, rest, err := readString(body)
+	if err != nil {
+		return err
+	}
+
+	qos := (header >> 1) & 0x03
+
+	var packetID []byte
+	if qos > 0 {
+		if len(rest) < 2 {
+			return fmt.Errorf("invalid PUBLISH packet")
+		}
+
+		packetID, rest = rest[:2], rest[2:]
+	}
+
+	c.broker.publish(topic, rest)
+
+	switch qos {
+	case 1:
+		return c.write(packetPuback<<4, packetID)
+	case 2:
+		return c.write(packetPubrec<<4, packetID)
+	}
+
+	return nil
+}
+
+func (c *brokerConn) handleSubscribe(body []byte) error {
+	if len(body) < 2 {
+		return fmt.Errorf("invalid SUBSCRIBE packet")
+	}
+
+	ack := append([]byte{}, body[:2]...)
+
+	for rest := body[2:]; len(rest) > 0; {
+		topic, tail, err := readString(rest)
+		if err != nil {
+			return err
+		}
+
+		if len(tail) < 1 {
+			return fmt.Errorf("invalid SUBSCRIBE packet")
+		}
+
+		rest = tail[1:]
+
+		c.subMu.Lock()
+		c.subscriptions[topic] = struct{}{}
+		c.subMu.Unlock()
+
+		ack = append(ack, 0x00)
+	}
+
+	return c.write(packetSuback<<4, ack)
+}
+
+func (c *brokerConn) handleUnsubscribe(body []byte) error {
+	if len(body) < 2 {
+		return fmt.Errorf("invalid UNSUBSCRIBE packet")
+	}
+
+	for rest := body[2:]; len(rest) > 0; {
+		topic, tail, err := readString(rest)
+		if err != nil {
+			return err
+		}
+
+		rest = tail
+
+		c.subMu.Lock()
+		delete(c.subscriptions, topic)
+		c.subMu.Unlock()
+	}
+
+	return c.write(packetUnsuback<<4, body[:2])
+}
+
+func (c *brokerConn) isSubscribed(topic string) bool {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	for filter := range c.subscriptions {
+		if matchTopic(filter, topic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *brokerConn) read() (byte, []byte, error) {
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length := 0
+	for multiplier := 1; ; multiplier *= 128 {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		length += int(b&0x7F) * multiplier
+
+		if b&0x80 == 0 {
+			break
+		}
+
+		if multiplier > 128*128 {
+			return 0, nil, fmt.Errorf("malformed remaining length")
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return 0, nil, err
+	}
+
+	return header, body, nil
+}
+
+func (c *brokerConn) write(header byte, body []byte) error {
+	if len(body) > maxRemainingLength {
+		return fmt.Errorf("packet is too large: size=%d", len(body))
+	}
+
+	buf := []byte{header}
+
+	for length := len(body); ; {
+		b := byte(length % 128)
+		length /= 128
+
+		if length > 0 {
+			b |= 0x80
+		}
+
+		buf = append(buf, b)
+
+		if length == 0 {
+			break
+		}
+	}
+
+	buf = append(buf, body...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err := c.conn.Write(buf)
+
+	return err
+}
+
+func readString(buf []byte) (string, []byte, error) {
+	if len(buf) < 2 {
+		return "", nil, fmt.Errorf("malformed string")
+	}
+
+	size := int(binary.BigEndian.Uint16(buf))
+	if len(buf) < 2+size {
+		return "", nil, fmt.Errorf("malformed string")
+	}
+
+	return string(buf[2 : 2+size]), buf[2+size:], nil
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(s)))
+
+	return append(buf, s...)
+}
+
+// matchTopic checks if the topic matches the subscription filter, which may
+// contain single-level (+) and multi-level (#) wildcards.
+func matchTopic(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+
+		if i >= len(topicLevels) {
+			return false
+		}
+
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+
+	return len(filterLevels) == len(topicLevels)
+}
@@ -0,0 +1,40 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mqtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerMatchTopic(t *testing.T) {
+	tests := []struct {
+		filter string
+		topic  string
+		match  bool
+	}{
+		{"foo/bar", "foo/bar", true},
+		{"foo/bar", "foo/baz", false},
+		{"foo/bar", "foo/bar/baz", false},
+		{"foo/bar/baz", "foo/bar", false},
+		{"foo/+", "foo/bar", true},
+		{"foo/+", "foo/bar/baz", false},
+		{"foo/+/baz", "foo/bar/baz", true},
+		{"+/+", "foo/bar", true},
+		{"foo/#", "foo", true},
+		{"foo/#", "foo/bar", true},
+		{"foo/#", "foo/bar/baz", true},
+		{"foo/#", "bar/foo", false},
+		{"#", "foo/bar", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.filter+"-"+test.topic, func(t *testing.T) {
+			require.Equal(t, test.match, matchTopic(test.filter, test.topic))
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mqcore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// MessageHandler handles messages received from the MQTT broker.
+type MessageHandler interface {
+	// HandleMessage handles the message payload received on the topic.
+	//
+	// Remarks:
+	//  - Should be thread-safe.
+	HandleMessage(topic string, payload []byte)
+}
+
+// Publisher publishes messages to the MQTT broker.
+type Publisher interface {
+	// Publish publishes payload to the topic.
+	Publish(topic string, payload []byte) error
+}
+
+// ClientParams represents various configuration options for the MQTT client.
+type ClientParams struct {
+	// BrokerURL - MQTT broker URL, e.g. tcp://localhost:1883.
+	BrokerURL string
+
+	// ClientID - MQTT client identifier. Random ID is generated if empty.
+	ClientID string
+
+	// Timeout - how long to wait for the broker to acknowledge the operation.
+	Timeout time.Duration
+}
+
+// Client is a wrapper for the MQTT client.
+//
+// Remarks:
+//   - Connection is established in the background and is automatically restored
+//     if it's lost, all subscriptions are restored on each connection.
+type Client struct {
+	client  mqtt.Client
+	timeout time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]MessageHandler
+}
+
+// NewClient initializes MQTT client.
+//
+// Remarks:
+//   - The client is not connected until Start() is called.
+func NewClient(params ClientParams) (*Client, error) {
+	if params.ClientID == "" {
+		clientID, err := generateClientID()
+		if err != nil {
+			return nil, err
+		}
+
+		params.ClientID = clientID
+	}
+
+	c := &Client{
+		timeout:       params.Timeout,
+		subscriptions: make(map[string]MessageHandler),
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(params.BrokerURL).
+		SetClientID(params.ClientID).
+		SetCleanSession(true).
+		SetOrderMatters(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(params.Timeout).
+		SetOnConnectHandler(c.handleConnect).
+		SetConnectionLostHandler(c.handleConnectionLost)
+
+	c.client = mqtt.NewClient(opts)
+
+	return c, nil
+}
+
+// Start starts connecting to the MQTT broker.
+//
+// Remarks:
+//   - It doesn't wait for the connection to be established.
+func (c *Client) Start() error {
+	c.client.Connect()
+
+	return nil
+}
+
+// Stop disconnects from the MQTT broker.
+func (c *Client) Stop() error {
+	c.client.Disconnect(uint(c.timeout.Milliseconds()))
+
+	return nil
+}
+
+// Subscribe registers handler to handle messages published to the topic.
+//
+// Remarks:
+//   - If the client isn't connected yet, the subscription is performed after connection.
+func (c *Client) Subscribe(topic string, handler MessageHandler) error {
+	c.mu.Lock()
+	c.subscriptions[topic] = handler
+	c.mu.Unlock()
+
+	if !c.client.IsConnectionOpen() {
+		return nil
+	}
+
+	return c.subscribe(topic, handler)
+}
+
+// Unsubscribe removes the subscription for the topic.
+func (c *Client) Unsubscribe(topic string) error {
+	c.mu.Lock()
+	delete(c.subscriptions, topic)
+	c.mu.Unlock()
+
+	if !c.client.IsConnectionOpen() {
+		return nil
+	}
+
+	return c.wait(c.client.Unsubscribe(topic))
+}
+
+// Publish publishes payload to the topic with at most once delivery guarantee.
+func (c *Client) Publish(topic string, payload []byte) error {
+	if !c.client.IsConnectionOpen() {
+		return fmt.Errorf("mqtt-client: failed to publish: topic=%s err=%w",
+			topic, status.StatusInvalidState)
+	}
+
+	return c.wait(c.client.Publish(topic, 0, false, payload))
+}
+
+func (c *Client) subscribe(topic string, handler MessageHandler) error {
+	return c.wait(c.client.Subscribe(topic, 0, func(_ mqtt.Client, msg mqtt.Message) {
+		handler.HandleMessage(msg.Topic(), msg.Payload())
+	}))
+}
+
+func (c *Client) wait(token mqtt.Token) error {
+	if !token.WaitTimeout(c.timeout) {
+		return status.StatusTimeout
+	}
+
+	return token.Error()
+}
+
+func (c *Client) handleConnect(_ mqtt.Client) {
+	c.mu.Lock()
+	subscriptions := make(map[string]MessageHandler, len(c.subscriptions))
+	for topic, handler := range c.subscriptions {
+		subscriptions[topic] = handler
+	}
+	c.mu.Unlock()
+
+	for topic, handler := range subscriptions {
+		if err := c.subscribe(topic, handler); err != nil {
+			syscore.LogErr.Printf("mqtt-client: failed to subscribe: topic=%s err=%v",
+				topic, err)
+		}
+	}
+}
+
+func (c *Client) handleConnectionLost(_ mqtt.Client, err error) {
+	syscore.LogWrn.Printf("mqtt-client: connection lost: %v", err)
+}
+
+func generateClientID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return "device-hub-" + hex.EncodeToString(buf), nil
+}
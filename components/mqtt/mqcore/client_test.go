@@ -0,0 +1,165 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mqcore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/internal/mqtest"
+)
+
+type testClientMessage struct {
+	topic   string
+	payload string
+}
+
+type testClientHandler struct {
+	messageCh chan testClientMessage
+}
+
+func newTestClientHandler() *testClientHandler {
+	return &testClientHandler{
+		messageCh: make(chan testClientMessage, 16),
+	}
+}
+
+func (h *testClientHandler) HandleMessage(topic string, payload []byte) {
+	h.messageCh <- testClientMessage{
+		topic:   topic,
+		payload: string(payload),
+	}
+}
+
+func (h *testClientHandler) wait(t *testing.T) testClientMessage {
+	select {
+	case msg := <-h.messageCh:
+		return msg
+	case <-time.After(time.Second * 5):
+		require.FailNow(t, "timeout waiting for MQTT message")
+	}
+
+	return testClientMessage{}
+}
+
+func newTestClient(t *testing.T, broker *mqtest.Broker) *Client {
+	client, err := NewClient(ClientParams{
+		BrokerURL: broker.URL(),
+		Timeout:   time.Second * 5,
+	})
+	require.Nil(t, err)
+
+	return client
+}
+
+func waitTestClientSubscribed(t *testing.T, publisher *Client, topic string,
+	handler *testClientHandler,
+) {
+	require.Eventually(t, func() bool {
+		if err := publisher.Publish(topic, []byte("ping")); err != nil {
+			return false
+		}
+
+		select {
+		case <-handler.messageCh:
+			return true
+		case <-time.After(time.Millisecond * 50):
+			return false
+		}
+	}, time.Second*5, time.Millisecond*10)
+}
+
+func TestClientPublishSubscribe(t *testing.T) {
+	broker, err := mqtest.NewBroker(mqtest.BrokerParams{Host: "127.0.0.1"})
+	require.Nil(t, err)
+	require.Nil(t, broker.Start())
+	defer func() {
+		require.Nil(t, broker.Stop())
+	}()
+
+	handler := newTestClientHandler()
+
+	subscriber := newTestClient(t, broker)
+	require.Nil(t, subscriber.Subscribe("foo/+/telemetry", handler))
+	require.Nil(t, subscriber.Start())
+	defer func() {
+		require.Nil(t, subscriber.Stop())
+	}()
+
+	publisher := newTestClient(t, broker)
+	require.Nil(t, publisher.Start())
+	defer func() {
+		require.Nil(t, publisher.Stop())
+	}()
+
+	waitTestClientSubscribed(t, publisher, "foo/bar/telemetry", handler)
+
+	require.Nil(t, publisher.Publish("foo/bar/registration", []byte("registration")))
+	require.Nil(t, publisher.Publish("foo/bar/telemetry", []byte("telemetry")))
+
+	msg := handler.wait(t)
+	require.Equal(t, "foo/bar/telemetry", msg.topic)
+	require.Equal(t, "telemetry", msg.payload)
+
+	require.Nil(t, subscriber.Unsubscribe("foo/+/telemetry"))
+	require.Nil(t, publisher.Publish("foo/bar/telemetry", []byte("telemetry")))
+
+	select {
+	case msg := <-handler.messageCh:
+		require.FailNow(t, "unexpected message", msg)
+	case <-time.After(time.Millisecond * 100):
+	}
+}
+
+func TestClientPublishNotConnected(t *testing.T) {
+	client, err := NewClient(ClientParams{
+		BrokerURL: "tcp://127.0.0.1:1",
+		Timeout:   time.Millisecond * 100,
+	})
+	require.Nil(t, err)
+
+	require.NotNil(t, client.Publish("foo", []byte("bar")))
+}
+
+func TestClientSystemClock(t *testing.T) {
+	broker, err := mqtest.NewBroker(mqtest.BrokerParams{Host: "127.0.0.1"})
+	require.Nil(t, err)
+	require.Nil(t, broker.Start())
+	defer func() {
+		require.Nil(t, broker.Stop())
+	}()
+
+	handler := newTestClientHandler()
+
+	subscriber := newTestClient(t, broker)
+	require.Nil(t, subscriber.Subscribe("foo/system/time", handler))
+	require.Nil(t, subscriber.Start())
+	defer func() {
+		require.Nil(t, subscriber.Stop())
+	}()
+
+	publisher := newTestClient(t, broker)
+	require.Nil(t, publisher.Start())
+	defer func() {
+		require.Nil(t, publisher.Stop())
+	}()
+
+	waitTestClientSubscribed(t, publisher, "foo/system/time", handler)
+
+	clock := NewSystemClock(publisher, "foo/system/time")
+
+	timestamp, err := clock.GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, int64(-1), timestamp)
+
+	require.Nil(t, clock.SetTimestamp(123))
+
+	msg := handler.wait(t)
+	require.Equal(t, "foo/system/time", msg.topic)
+	require.Equal(t, "123", msg.payload)
+}
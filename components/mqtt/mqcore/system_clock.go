@@ -0,0 +1,43 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package mqcore
+
+import (
+	"strconv"
+)
+
+// SystemClock handles the UNIX time for the MQTT device.
+//
+// Remarks:
+//   - MQTT is a one-way transport, the current device UNIX time can't be requested,
+//     that's why GetTimestamp() always returns -1, which is what the device reports
+//     when its time isn't synchronized.
+type SystemClock struct {
+	publisher Publisher
+	topic     string
+}
+
+// NewSystemClock initializes SystemClock.
+//
+// Parameters:
+//   - publisher to publish time synchronization commands.
+//   - topic - MQTT topic the device listens to receive the UNIX time.
+func NewSystemClock(publisher Publisher, topic string) *SystemClock {
+	return &SystemClock{
+		publisher: publisher,
+		topic:     topic,
+	}
+}
+
+// SetTimestamp publishes the UNIX time to the device.
+func (c *SystemClock) SetTimestamp(timestamp int64) error {
+	return c.publisher.Publish(c.topic, []byte(strconv.FormatInt(timestamp, 10)))
+}
+
+// GetTimestamp always returns -1.
+func (*SystemClock) GetTimestamp() (int64, error) {
+	return -1, nil
+}
@@ -0,0 +1,239 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stcore
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// BboltQueueParams provides various configuration options for BboltQueue.
+type BboltQueueParams struct {
+	// MaxAge - items older than this age are dropped, age isn't limited if zero.
+	MaxAge time.Duration
+
+	// MaxSize - maximum total size of the items values in bytes, the earliest
+	// items are dropped if the size is exceeded, size isn't limited if zero.
+	MaxSize int64
+}
+
+// BboltQueue is a persistent queue stored in the bbolt database bucket.
+//
+// Remarks:
+//   - Item key is a big-endian timestamp followed by a big-endian sequence number,
+//     so the items are iterated in the timestamp order, and items with the same
+//     timestamp are iterated in the insertion order.
+type BboltQueue struct {
+	db     *bbolt.DB
+	bucket []byte
+	clock  syscore.SystemClock
+	params BboltQueueParams
+
+	mu    sync.Mutex
+	stats QueueStats
+}
+
+// NewBboltQueue is an initialization of BboltQueue.
+//
+// Parameters:
+//   - db - bbolt database instance.
+//   - bucket - bbolt database bucket, created if it doesn't exist.
+//   - clock to get the current UNIX time to check the items age.
+//   - params - various queue configuration options.
+func NewBboltQueue(
+	db *bbolt.DB,
+	bucket string,
+	clock syscore.SystemClock,
+	params BboltQueueParams,
+) (*BboltQueue, error) {
+	q := &BboltQueue{
+		db:     db,
+		bucket: []byte(bucket),
+		clock:  clock,
+		params: params,
+	}
+
+	err := db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(q.bucket)
+		if err != nil {
+			return err
+		}
+
+		return b.ForEach(func(_, v []byte) error {
+			q.stats.Length++
+			q.stats.Size += int64(len(v))
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bbolt-queue: failed to open bucket: %w", err)
+	}
+
+	return q, nil
+}
+
+// Push adds the item to the queue.
+//
+// Remarks:
+//   - The earliest items are dropped if the queue limits are exceeded.
+func (q *BboltQueue) Push(timestamp int64, value []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	// Statistics is updated only if the transaction is committed.
+	stats := q.stats
+
+	err := q.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(q.bucket)
+
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		key := make([]byte, 16)
+		binary.BigEndian.PutUint64(key[:8], uint64(timestamp))
+		binary.BigEndian.PutUint64(key[8:], seq)
+
+		if err := b.Put(key, value); err != nil {
+			return err
+		}
+
+		stats.Length++
+		stats.Size += int64(len(value))
+
+		return q.trim(b, &stats)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.stats = stats
+
+	return nil
+}
+
+// Front returns the item with the earliest timestamp.
+//
+// Remarks:
+//   - Items older than the maximum age are dropped.
+func (q *BboltQueue) Front() (QueueItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var item QueueItem
+
+	stats := q.stats
+
+	err := q.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(q.bucket)
+
+		if err := q.trim(b, &stats); err != nil {
+			return err
+		}
+
+		// Don't fail the transaction if the queue is empty, dropped items should be removed.
+		k, v := b.Cursor().First()
+		if k == nil {
+			return nil
+		}
+
+		item = QueueItem{
+			Timestamp: int64(binary.BigEndian.Uint64(k[:8])),
+			Value:     append([]byte(nil), v...),
+			key:       append([]byte(nil), k...),
+		}
+
+		return nil
+	})
+	if err != nil {
+		return QueueItem{}, err
+	}
+
+	q.stats = stats
+
+	if item.key == nil {
+		return QueueItem{}, status.StatusNoData
+	}
+
+	return item, nil
+}
+
+// Remove removes the item from the queue.
+func (q *BboltQueue) Remove(item QueueItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := q.stats
+
+	err := q.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(q.bucket)
+
+		v := b.Get(item.key)
+		if v == nil {
+			return nil
+		}
+
+		stats.Length--
+		stats.Size -= int64(len(v))
+
+		return b.Delete(item.key)
+	})
+	if err != nil {
+		return err
+	}
+
+	q.stats = stats
+
+	return nil
+}
+
+// Stats returns the queue statistics.
+func (q *BboltQueue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.stats
+}
+
+func (q *BboltQueue) trim(b *bbolt.Bucket, stats *QueueStats) error {
+	minTimestamp := int64(-1)
+
+	if q.params.MaxAge > 0 {
+		now, err := q.clock.GetTimestamp()
+		if err == nil && now > 0 {
+			minTimestamp = now - int64(q.params.MaxAge/time.Second)
+		}
+	}
+
+	c := b.Cursor()
+
+	for k, v := c.First(); k != nil; k, v = c.First() {
+		expired := int64(binary.BigEndian.Uint64(k[:8])) < minTimestamp
+		oversized := q.params.MaxSize > 0 && stats.Size > q.params.MaxSize
+
+		if !expired && !oversized {
+			break
+		}
+
+		stats.Length--
+		stats.Size -= int64(len(v))
+
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
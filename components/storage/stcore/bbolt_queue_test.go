@@ -0,0 +1,158 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stcore
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+type testQueueClock struct {
+	timestamp int64
+}
+
+func (c *testQueueClock) SetTimestamp(timestamp int64) error {
+	c.timestamp = timestamp
+
+	return nil
+}
+
+func (c *testQueueClock) GetTimestamp() (int64, error) {
+	return c.timestamp, nil
+}
+
+func newTestBboltDB(t *testing.T) *bbolt.DB {
+	db, err := NewBboltDB(filepath.Join(t.TempDir(), "bbolt.db"), nil)
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+
+	return db
+}
+
+func popTestBboltQueue(t *testing.T, queue *BboltQueue) QueueItem {
+	item, err := queue.Front()
+	require.Nil(t, err)
+	require.Nil(t, queue.Remove(item))
+
+	return item
+}
+
+func TestBboltQueueOrder(t *testing.T) {
+	db := newTestBboltDB(t)
+
+	queue, err := NewBboltQueue(db, "queue", &testQueueClock{}, BboltQueueParams{})
+	require.Nil(t, err)
+
+	_, err = queue.Front()
+	require.Equal(t, status.StatusNoData, err)
+
+	require.Nil(t, queue.Push(30, []byte("c")))
+	require.Nil(t, queue.Push(10, []byte("a")))
+	require.Nil(t, queue.Push(20, []byte("b1")))
+	require.Nil(t, queue.Push(20, []byte("b2")))
+	require.Equal(t, QueueStats{Length: 4, Size: 6}, queue.Stats())
+
+	for _, expected := range []QueueItem{
+		{Timestamp: 10, Value: []byte("a")},
+		{Timestamp: 20, Value: []byte("b1")},
+		{Timestamp: 20, Value: []byte("b2")},
+		{Timestamp: 30, Value: []byte("c")},
+	} {
+		item := popTestBboltQueue(t, queue)
+		require.Equal(t, expected.Timestamp, item.Timestamp)
+		require.Equal(t, expected.Value, item.Value)
+	}
+
+	require.Equal(t, QueueStats{}, queue.Stats())
+
+	_, err = queue.Front()
+	require.Equal(t, status.StatusNoData, err)
+}
+
+func TestBboltQueueRemoveTwice(t *testing.T) {
+	queue, err := NewBboltQueue(newTestBboltDB(t), "queue", &testQueueClock{},
+		BboltQueueParams{})
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Push(10, []byte("a")))
+
+	item, err := queue.Front()
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Remove(item))
+	require.Nil(t, queue.Remove(item))
+	require.Equal(t, QueueStats{}, queue.Stats())
+}
+
+func TestBboltQueueMaxSize(t *testing.T) {
+	queue, err := NewBboltQueue(newTestBboltDB(t), "queue", &testQueueClock{},
+		BboltQueueParams{MaxSize: 4})
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Push(10, []byte("aa")))
+	require.Nil(t, queue.Push(20, []byte("bb")))
+	require.Equal(t, QueueStats{Length: 2, Size: 4}, queue.Stats())
+
+	require.Nil(t, queue.Push(30, []byte("cc")))
+	require.Equal(t, QueueStats{Length: 2, Size: 4}, queue.Stats())
+
+	require.Equal(t, int64(20), popTestBboltQueue(t, queue).Timestamp)
+	require.Equal(t, int64(30), popTestBboltQueue(t, queue).Timestamp)
+}
+
+func TestBboltQueueMaxAge(t *testing.T) {
+	clock := &testQueueClock{timestamp: 100}
+
+	queue, err := NewBboltQueue(newTestBboltDB(t), "queue", clock,
+		BboltQueueParams{MaxAge: time.Second * 30})
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Push(60, []byte("a")))
+	require.Equal(t, QueueStats{}, queue.Stats())
+
+	require.Nil(t, queue.Push(80, []byte("b")))
+	require.Nil(t, queue.Push(90, []byte("c")))
+	require.Equal(t, QueueStats{Length: 2, Size: 2}, queue.Stats())
+
+	clock.timestamp = 115
+
+	require.Equal(t, int64(90), popTestBboltQueue(t, queue).Timestamp)
+	require.Equal(t, QueueStats{}, queue.Stats())
+
+	require.Nil(t, queue.Push(120, []byte("d")))
+
+	clock.timestamp = 200
+
+	_, err = queue.Front()
+	require.Equal(t, status.StatusNoData, err)
+	require.Equal(t, QueueStats{}, queue.Stats())
+}
+
+func TestBboltQueueReopen(t *testing.T) {
+	db := newTestBboltDB(t)
+
+	queue, err := NewBboltQueue(db, "queue", &testQueueClock{}, BboltQueueParams{})
+	require.Nil(t, err)
+
+	require.Nil(t, queue.Push(20, []byte("b")))
+	require.Nil(t, queue.Push(10, []byte("aa")))
+
+	queue, err = NewBboltQueue(db, "queue", &testQueueClock{}, BboltQueueParams{})
+	require.Nil(t, err)
+	require.Equal(t, QueueStats{Length: 2, Size: 3}, queue.Stats())
+
+	require.Equal(t, []byte("aa"), popTestBboltQueue(t, queue).Value)
+	require.Equal(t, []byte("b"), popTestBboltQueue(t, queue).Value)
+}
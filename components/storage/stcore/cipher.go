@@ -0,0 +1,131 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stcore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// CipherKeySize is the size of the AES-256 key, in bytes.
+const CipherKeySize = 32
+
+// Cipher encrypts the sensitive data before it's persisted.
+type Cipher interface {
+	// Encrypt returns the encrypted data.
+	Encrypt(plaintext []byte) ([]byte, error)
+
+	// Decrypt returns the decrypted data.
+	//
+	// Remarks:
+	//  - status.StatusInvalidState is returned if the data can't be authenticated,
+	//    e.g. it's encrypted with another key.
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AESCipher encrypts data with AES-256-GCM.
+//
+// Remarks:
+//   - Random nonce is prepended to each encrypted data.
+type AESCipher struct {
+	aead cipher.AEAD
+}
+
+// NewAESCipher is an initialization of AESCipher.
+//
+// Parameters:
+//   - key - AES-256 key of CipherKeySize bytes.
+func NewAESCipher(key []byte) (*AESCipher, error) {
+	if len(key) != CipherKeySize {
+		return nil, fmt.Errorf("%w: invalid key size: want=%d got=%d",
+			status.StatusInvalidArg, CipherKeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESCipher{aead: aead}, nil
+}
+
+// Encrypt encrypts and authenticates the data.
+func (c *AESCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+
+	nonce := make([]byte, nonceSize, nonceSize+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt authenticates and decrypts the data.
+func (c *AESCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := c.aead.NonceSize()
+
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("%w: encrypted data is too short", status.StatusInvalidState)
+	}
+
+	nonce, data := ciphertext[:nonceSize], ciphertext[nonceSize:]
+
+	plaintext, err := c.aead.Open(nil, nonce, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decrypt data: %v",
+			status.StatusInvalidState, err)
+	}
+
+	return plaintext, nil
+}
+
+// LoadCipherKey reads the key from the file, the random key is generated and
+// persisted if the file doesn't exist.
+//
+// Remarks:
+//   - Data encrypted with the key can't be decrypted if the file is lost.
+func LoadCipherKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path)
+	if err == nil {
+		if len(key) != CipherKeySize {
+			return nil, fmt.Errorf("%w: invalid key size: path=%s want=%d got=%d",
+				status.StatusInvalidArg, path, CipherKeySize, len(key))
+		}
+
+		return key, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	key = make([]byte, CipherKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(path, key, 0600); err != nil {
+		return nil, err
+	}
+
+	syscore.LogInf.Printf("cipher key generated: path=%s", path)
+
+	return key, nil
+}
@@ -0,0 +1,75 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stcore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+func TestAESCipher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.key")
+
+	key, err := LoadCipherKey(path)
+	require.Nil(t, err)
+	require.Equal(t, CipherKeySize, len(key))
+
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Persisted key is reused.
+	restoredKey, err := LoadCipherKey(path)
+	require.Nil(t, err)
+	require.Equal(t, key, restoredKey)
+
+	cipher, err := NewAESCipher(key)
+	require.Nil(t, err)
+
+	plaintext := []byte("secret token")
+
+	ciphertext, err := cipher.Encrypt(plaintext)
+	require.Nil(t, err)
+	require.NotContains(t, string(ciphertext), string(plaintext))
+
+	// Each encryption uses its own nonce.
+	otherCiphertext, err := cipher.Encrypt(plaintext)
+	require.Nil(t, err)
+	require.NotEqual(t, ciphertext, otherCiphertext)
+
+	decrypted, err := cipher.Decrypt(ciphertext)
+	require.Nil(t, err)
+	require.Equal(t, plaintext, decrypted)
+
+	ciphertext[len(ciphertext)-1]++
+	_, err = cipher.Decrypt(ciphertext)
+	require.ErrorIs(t, err, status.StatusInvalidState)
+
+	_, err = cipher.Decrypt([]byte{1, 2})
+	require.ErrorIs(t, err, status.StatusInvalidState)
+
+	otherCipher, err := NewAESCipher(make([]byte, CipherKeySize))
+	require.Nil(t, err)
+
+	_, err = otherCipher.Decrypt(otherCiphertext)
+	require.ErrorIs(t, err, status.StatusInvalidState)
+}
+
+func TestAESCipherInvalidKey(t *testing.T) {
+	_, err := NewAESCipher(make([]byte, 16))
+	require.ErrorIs(t, err, status.StatusInvalidArg)
+
+	path := filepath.Join(t.TempDir(), "secret.key")
+	require.Nil(t, os.WriteFile(path, []byte("short"), 0600))
+
+	_, err = LoadCipherKey(path)
+	require.ErrorIs(t, err, status.StatusInvalidArg)
+}
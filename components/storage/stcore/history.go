@@ -0,0 +1,95 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stcore
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Aggregation is a function to aggregate the samples within the query step.
+type Aggregation int
+
+const (
+	// AggregationMean - mean of the samples.
+	AggregationMean Aggregation = iota
+
+	// AggregationMin - minimum of the samples.
+	AggregationMin
+
+	// AggregationMax - maximum of the samples.
+	AggregationMax
+)
+
+// ParseAggregation parses the aggregation name: mean, min or max.
+func ParseAggregation(s string) (Aggregation, error) {
+	switch s {
+	case "mean":
+		return AggregationMean, nil
+	case "min":
+		return AggregationMin, nil
+	case "max":
+		return AggregationMax, nil
+	default:
+		return AggregationMean, fmt.Errorf("unknown aggregation: %s", s)
+	}
+}
+
+// String returns the aggregation name.
+func (a Aggregation) String() string {
+	switch a {
+	case AggregationMin:
+		return "min"
+	case AggregationMax:
+		return "max"
+	default:
+		return "mean"
+	}
+}
+
+// HistoryQuery selects the stored device data.
+type HistoryQuery struct {
+	// DeviceID - device identifier.
+	DeviceID string
+
+	// Measurement - data measurement, e.g. telemetry.
+	Measurement string
+
+	// From - UNIX time in seconds, inclusive.
+	From int64
+
+	// To - UNIX time in seconds, exclusive.
+	To int64
+
+	// Fields - flattened field names, all fields are returned if empty.
+	Fields []string
+
+	// Step - samples are aggregated within the step, not aggregated if zero.
+	Step time.Duration
+
+	// Aggregation - how the samples are aggregated within the step.
+	Aggregation Aggregation
+}
+
+// HistoryPoint is the stored device data at the particular time.
+type HistoryPoint struct {
+	// Timestamp - UNIX time in seconds, the step start for the aggregated data.
+	Timestamp int64 `json:"timestamp"`
+
+	// Fields - flattened device data fields.
+	Fields map[string]any `json:"fields"`
+}
+
+// HistoryReader reads the stored device data.
+type HistoryReader interface {
+	// ReadHistory returns the device data points in the timestamp order.
+	//
+	// Remarks:
+	//  - Only numeric fields are returned if the data is aggregated.
+	//  - Implementation should return an empty slice if there is no data.
+	ReadHistory(ctx context.Context, query HistoryQuery) ([]HistoryPoint, error)
+}
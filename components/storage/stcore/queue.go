@@ -0,0 +1,50 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stcore
+
+// QueueItem is an item stored in the queue.
+type QueueItem struct {
+	// Timestamp - UNIX time in seconds, items are ordered by the timestamp.
+	Timestamp int64
+
+	// Value - arbitrary item data.
+	Value []byte
+
+	key []byte
+}
+
+// QueueStats is the queue statistics.
+type QueueStats struct {
+	// Length - number of items in the queue.
+	Length int `json:"length"`
+
+	// Size - total size of the items values in bytes.
+	Size int64 `json:"size"`
+}
+
+// Queue is a persistent queue, ordered by the item timestamp.
+//
+// Remarks:
+//   - Implementation should be thread-safe.
+type Queue interface {
+	// Push adds the item to the queue.
+	Push(timestamp int64, value []byte) error
+
+	// Front returns the item with the earliest timestamp.
+	//
+	// Remarks:
+	//  - Implementation should return status.StatusNoData if the queue is empty.
+	Front() (QueueItem, error)
+
+	// Remove removes the item returned by Front() from the queue.
+	//
+	// Remarks:
+	//  - Implementation should return nil if the item doesn't exist.
+	Remove(item QueueItem) error
+
+	// Stats returns the queue statistics.
+	Stats() QueueStats
+}
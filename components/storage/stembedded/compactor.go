@@ -0,0 +1,94 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// CompactorParams provides various configuration options for Compactor.
+type CompactorParams struct {
+	// Retention - data older than this age is removed, data isn't removed if zero.
+	Retention time.Duration
+
+	// DownsampleAfter - data older than this age is downsampled, data isn't
+	// downsampled if zero.
+	DownsampleAfter time.Duration
+
+	// DownsampleInterval - a single point is kept per interval for the downsampled data.
+	DownsampleInterval time.Duration
+}
+
+// Compactor removes and downsamples the old data in the embedded store.
+type Compactor struct {
+	store  *Store
+	clock  syscore.SystemClock
+	params CompactorParams
+}
+
+// NewCompactor is an initialization of Compactor.
+//
+// Parameters:
+//   - store to compact.
+//   - clock to get the current UNIX time.
+//   - params - various compaction options.
+func NewCompactor(
+	store *Store,
+	clock syscore.SystemClock,
+	params CompactorParams,
+) *Compactor {
+	return &Compactor{
+		store:  store,
+		clock:  clock,
+		params: params,
+	}
+}
+
+// Run compacts the embedded store.
+func (c *Compactor) Run() error {
+	now, err := c.clock.GetTimestamp()
+	if err != nil {
+		return fmt.Errorf("embedded-compactor: failed to get UNIX time: %w", err)
+	}
+
+	if now <= 0 {
+		return fmt.Errorf("embedded-compactor: invalid UNIX time: %d", now)
+	}
+
+	if c.params.Retention > 0 {
+		removed, err := c.store.DeleteBefore(now - int64(c.params.Retention/time.Second))
+		if err != nil {
+			return err
+		}
+
+		if removed > 0 {
+			syscore.LogInf.Printf("embedded-compactor: expired data removed: count=%d",
+				removed)
+		}
+	}
+
+	if c.params.DownsampleAfter > 0 {
+		removed, err := c.store.Downsample(
+			now-int64(c.params.DownsampleAfter/time.Second), c.params.DownsampleInterval)
+		if err != nil {
+			return err
+		}
+
+		if removed > 0 {
+			syscore.LogInf.Printf("embedded-compactor: data downsampled: count=%d", removed)
+		}
+	}
+
+	return nil
+}
+
+// HandleError logs the compaction error.
+func (*Compactor) HandleError(err error) {
+	syscore.LogErr.Printf("%v", err)
+}
@@ -0,0 +1,71 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"fmt"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// DataHandler stores incoming data in the embedded store.
+type DataHandler struct {
+	clock syscore.SystemClock
+	store *Store
+}
+
+// NewDataHandler is an initialization of DataHandler.
+//
+// Parameters:
+//   - clock to update the most recent UNIX time.
+//   - store to persist the device data.
+func NewDataHandler(clock syscore.SystemClock, store *Store) *DataHandler {
+	return &DataHandler{
+		clock: clock,
+		store: store,
+	}
+}
+
+// HandleTelemetry stores telemetry data in the embedded store.
+func (h *DataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	return h.handleData("telemetry", deviceID, js)
+}
+
+// HandleRegistration stores registration data in the embedded store.
+func (h *DataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
+	return h.handleData("registration", deviceID, js)
+}
+
+// HandleStream stores the device stream data in the embedded store.
+func (h *DataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	return h.handleData(measurement, deviceID, js)
+}
+
+func (h *DataHandler) handleData(measurement string, deviceID string, js devcore.JSON) error {
+	ts, ok := js["timestamp"]
+	if !ok {
+		return fmt.Errorf("embedded-data-handler: missed timestamp field")
+	}
+
+	timestamp, ok := ts.(float64)
+	if !ok {
+		return fmt.Errorf("embedded-data-handler: invalid type for timestamp")
+	}
+
+	if err := h.store.Write(deviceID, measurement, Point{
+		Timestamp: int64(timestamp),
+		Data:      js,
+	}); err != nil {
+		return fmt.Errorf("embedded-data-handler: failed to write to DB: %w", err)
+	}
+
+	return h.clock.SetTimestamp(int64(timestamp))
+}
@@ -0,0 +1,155 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+// historySeparator - separator of the flattened keys, the same as the default
+// InfluxDB separator, so the field names don't depend on the storage backend.
+const historySeparator = "_"
+
+// HistoryReader reads the stored device data from the embedded store.
+//
+// Remarks:
+//   - Nested device data is flattened, e.g. {"sensors": {"soil": {"moisture": 42}}}
+//     is returned as the "sensors_soil_moisture" field.
+type HistoryReader struct {
+	store *Store
+}
+
+// NewHistoryReader is an initialization of HistoryReader.
+//
+// Parameters:
+//   - store to read the device data.
+func NewHistoryReader(store *Store) *HistoryReader {
+	return &HistoryReader{store: store}
+}
+
+// ReadHistory reads the device data points from the embedded store.
+func (r *HistoryReader) ReadHistory(
+	_ context.Context,
+	query stcore.HistoryQuery,
+) ([]stcore.HistoryPoint, error) {
+	points, err := r.store.Query(Query{
+		DeviceID:    query.DeviceID,
+		Measurement: query.Measurement,
+		From:        query.From,
+		To:          query.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]stcore.HistoryPoint, 0, len(points))
+
+	for _, point := range points {
+		fields := devcore.FlattenJSON(point.Data, historySeparator)
+
+		if len(query.Fields) > 0 {
+			for field := range fields {
+				if !slices.Contains(query.Fields, field) {
+					delete(fields, field)
+				}
+			}
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		history = append(history, stcore.HistoryPoint{
+			Timestamp: point.Timestamp,
+			Fields:    fields,
+		})
+	}
+
+	if query.Step > 0 {
+		return aggregateHistory(history, query.Step, query.Aggregation)
+	}
+
+	return history, nil
+}
+
+type historyWindow struct {
+	start  int64
+	values map[string][]float64
+}
+
+func aggregateHistory(
+	history []stcore.HistoryPoint,
+	step time.Duration,
+	aggregation stcore.Aggregation,
+) ([]stcore.HistoryPoint, error) {
+	seconds := int64(step / time.Second)
+	if seconds <= 0 {
+		return nil, fmt.Errorf("embedded-store: invalid step: %v", step)
+	}
+
+	var windows []*historyWindow
+
+	for _, point := range history {
+		start := point.Timestamp - point.Timestamp%seconds
+
+		if len(windows) == 0 || windows[len(windows)-1].start != start {
+			windows = append(windows, &historyWindow{
+				start:  start,
+				values: make(map[string][]float64),
+			})
+		}
+
+		w := windows[len(windows)-1]
+
+		for field, value := range point.Fields {
+			if number, ok := value.(float64); ok {
+				w.values[field] = append(w.values[field], number)
+			}
+		}
+	}
+
+	aggregated := make([]stcore.HistoryPoint, 0, len(windows))
+
+	for _, w := range windows {
+		if len(w.values) == 0 {
+			continue
+		}
+
+		fields := make(map[string]any)
+		for field, values := range w.values {
+			fields[field] = aggregateValues(values, aggregation)
+		}
+
+		aggregated = append(aggregated, stcore.HistoryPoint{
+			Timestamp: w.start,
+			Fields:    fields,
+		})
+	}
+
+	return aggregated, nil
+}
+
+func aggregateValues(values []float64, aggregation stcore.Aggregation) float64 {
+	switch aggregation {
+	case stcore.AggregationMin:
+		return slices.Min(values)
+	case stcore.AggregationMax:
+		return slices.Max(values)
+	default:
+		sum := float64(0)
+		for _, value := range values {
+			sum += value
+		}
+
+		return sum / float64(len(values))
+	}
+}
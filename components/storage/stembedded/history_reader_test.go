@@ -0,0 +1,97 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+func TestHistoryReaderFlatten(t *testing.T) {
+	store := newTestStore(t)
+
+	require.Nil(t, store.Write("0xABCD", "telemetry", Point{
+		Timestamp: 100,
+		Data: devcore.JSON{
+			"timestamp": float64(100),
+			"sensors": map[string]any{
+				"soil": map[string]any{"moisture": float64(42)},
+			},
+			"values": []any{float64(1)},
+		},
+	}))
+
+	reader := NewHistoryReader(store)
+
+	points, err := reader.ReadHistory(context.Background(), stcore.HistoryQuery{
+		DeviceID:    "0xABCD",
+		Measurement: "telemetry",
+		To:          200,
+	})
+	require.Nil(t, err)
+	require.Equal(t, []stcore.HistoryPoint{{
+		Timestamp: 100,
+		Fields: map[string]any{
+			"timestamp":             float64(100),
+			"sensors_soil_moisture": float64(42),
+			"values_0":              float64(1),
+		},
+	}}, points)
+
+	points, err = reader.ReadHistory(context.Background(), stcore.HistoryQuery{
+		DeviceID:    "0xABCD",
+		Measurement: "telemetry",
+		To:          200,
+		Fields:      []string{"values_0", "missed"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []stcore.HistoryPoint{{
+		Timestamp: 100,
+		Fields:    map[string]any{"values_0": float64(1)},
+	}}, points)
+}
+
+func TestHistoryReaderAggregate(t *testing.T) {
+	store := newTestStore(t)
+
+	// temperature = timestamp * 2.
+	writeTestPoints(t, store, 60, 70, 110, 130)
+
+	reader := NewHistoryReader(store)
+
+	tests := []struct {
+		aggregation stcore.Aggregation
+		expected    []float64
+	}{
+		{stcore.AggregationMean, []float64{160, 260}},
+		{stcore.AggregationMin, []float64{120, 260}},
+		{stcore.AggregationMax, []float64{220, 260}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.aggregation.String(), func(t *testing.T) {
+			points, err := reader.ReadHistory(context.Background(), stcore.HistoryQuery{
+				DeviceID:    "0xABCD",
+				Measurement: "telemetry",
+				To:          200,
+				Fields:      []string{"temperature", "status"},
+				Step:        time.Minute,
+				Aggregation: test.aggregation,
+			})
+			require.Nil(t, err)
+			require.Equal(t, []stcore.HistoryPoint{
+				{Timestamp: 60, Fields: map[string]any{"temperature": test.expected[0]}},
+				{Timestamp: 120, Fields: map[string]any{"temperature": test.expected[1]}},
+			}, points)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// Pipeline contains various building blocks for persisting data in the embedded store.
+type Pipeline struct {
+	store *Store
+}
+
+// NewPipeline is an initialization of Pipeline.
+//
+// Parameters:
+//   - store to persist the device data.
+func NewPipeline(store *Store) *Pipeline {
+	return &Pipeline{store: store}
+}
+
+// BuildReader builds reader that retrieves device timestamps from the embedded store.
+func (p *Pipeline) BuildReader(deviceID string) stcore.SystemClockReader {
+	return NewSystemClockReader(p.store, deviceID)
+}
+
+// BuildHistoryReader builds reader that retrieves the stored device data from
+// the embedded store.
+func (p *Pipeline) BuildHistoryReader() stcore.HistoryReader {
+	return NewHistoryReader(p.store)
+}
+
+// BuildHandler builds the data handler that stores the device data in the embedded store.
+func (p *Pipeline) BuildHandler(
+	clock syscore.SystemClock,
+	_ string,
+	_ string,
+	_ string,
+) devcore.DataHandler {
+	return NewDataHandler(clock, p.store)
+}
@@ -0,0 +1,349 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// Point is the device data stored at the particular time.
+type Point struct {
+	// Timestamp - UNIX time in seconds.
+	Timestamp int64 `json:"timestamp"`
+
+	// Data - device data.
+	Data devcore.JSON `json:"data"`
+}
+
+// Query selects the device data in the time range.
+type Query struct {
+	// DeviceID - device identifier.
+	DeviceID string
+
+	// Measurement - data measurement, e.g. telemetry.
+	Measurement string
+
+	// From - UNIX time in seconds, inclusive.
+	From int64
+
+	// To - UNIX time in seconds, exclusive, unlimited if zero.
+	To int64
+
+	// Limit - maximum number of points, unlimited if zero.
+	Limit int
+}
+
+// Store is a time-series store in the bbolt database.
+//
+// Remarks:
+//   - Data is stored in the nested buckets: root bucket, device ID, measurement.
+//   - Point key is a big-endian UNIX timestamp, so the points are iterated in
+//     the timestamp order. Point with the same timestamp is overwritten.
+type Store struct {
+	db     *bbolt.DB
+	bucket []byte
+}
+
+// NewStore is an initialization of Store.
+//
+// Parameters:
+//   - db - bbolt database instance.
+//   - bucket - root bbolt bucket, created if it doesn't exist.
+func NewStore(db *bbolt.DB, bucket string) (*Store, error) {
+	s := &Store{
+		db:     db,
+		bucket: []byte(bucket),
+	}
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(s.bucket)
+
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("embedded-store: failed to open bucket: %w", err)
+	}
+
+	return s, nil
+}
+
+// Write stores the device data point.
+func (s *Store) Write(deviceID string, measurement string, point Point) error {
+	if point.Timestamp < 0 {
+		return fmt.Errorf("embedded-store: invalid timestamp: %d", point.Timestamp)
+	}
+
+	buf, err := json.Marshal(point.Data)
+	if err != nil {
+		return fmt.Errorf("embedded-store: failed to format data: %w", err)
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		device, err := tx.Bucket(s.bucket).CreateBucketIfNotExists([]byte(deviceID))
+		if err != nil {
+			return err
+		}
+
+		series, err := device.CreateBucketIfNotExists([]byte(measurement))
+		if err != nil {
+			return err
+		}
+
+		return series.Put(encodeKey(point.Timestamp), buf)
+	})
+}
+
+// Query returns the device data points in the timestamp order.
+//
+// Remarks:
+//   - Empty slice is returned if there is no data.
+func (s *Store) Query(query Query) ([]Point, error) {
+	points := []Point{}
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		series := s.getSeries(tx, query.DeviceID, query.Measurement)
+		if series == nil {
+			return nil
+		}
+
+		c := series.Cursor()
+
+		for k, v := c.Seek(encodeKey(max(query.From, 0))); k != nil; k, v = c.Next() {
+			timestamp := decodeKey(k)
+			if query.To > 0 && timestamp >= query.To {
+				break
+			}
+
+			if query.Limit > 0 && len(points) >= query.Limit {
+				break
+			}
+
+			var data devcore.JSON
+			if err := json.Unmarshal(v, &data); err != nil {
+				return fmt.Errorf("invalid point: timestamp=%d err=%w", timestamp, err)
+			}
+
+			points = append(points, Point{Timestamp: timestamp, Data: data})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("embedded-store: failed to query data: %w", err)
+	}
+
+	return points, nil
+}
+
+// LastTimestamp returns the most recent UNIX timestamp of the device data.
+//
+// Remarks:
+//   - status.StatusNoData is returned if there is no data.
+func (s *Store) LastTimestamp(deviceID string, measurement string) (int64, error) {
+	timestamp := int64(-1)
+
+	if err := s.db.View(func(tx *bbolt.Tx) error {
+		series := s.getSeries(tx, deviceID, measurement)
+		if series == nil {
+			return nil
+		}
+
+		if k, _ := series.Cursor().Last(); k != nil {
+			timestamp = decodeKey(k)
+		}
+
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+
+	if timestamp < 0 {
+		return -1, status.StatusNoData
+	}
+
+	return timestamp, nil
+}
+
+// DeleteBefore removes all points older than the timestamp.
+//
+// Remarks:
+//   - Returns the number of removed points.
+func (s *Store) DeleteBefore(timestamp int64) (int, error) {
+	removed := 0
+
+	err := s.forEachSeries(func(series *bbolt.Bucket) error {
+		c := series.Cursor()
+
+		for k, _ := c.First(); k != nil && decodeKey(k) < timestamp; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+
+			removed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("embedded-store: failed to delete data: %w", err)
+	}
+
+	return removed, nil
+}
+
+// Downsample replaces points older than the timestamp with a single point per interval.
+//
+// Remarks:
+//   - Aggregated point is stored at the interval start.
+//   - Numeric fields are averaged, the last value is used for other fields.
+//   - Returns the number of removed points.
+func (s *Store) Downsample(before int64, interval time.Duration) (int, error) {
+	step := int64(interval / time.Second)
+	if step <= 0 {
+		return 0, fmt.Errorf("embedded-store: invalid downsample interval: %v", interval)
+	}
+
+	removed := 0
+
+	err := s.forEachSeries(func(series *bbolt.Bucket) error {
+		windows, err := collectWindows(series, before, step)
+		if err != nil {
+			return err
+		}
+
+		for _, w := range windows {
+			if len(w.keys) < 2 {
+				continue
+			}
+
+			for _, k := range w.keys {
+				if err := series.Delete(k); err != nil {
+					return err
+				}
+			}
+
+			buf, err := json.Marshal(w.aggregate())
+			if err != nil {
+				return err
+			}
+
+			if err := series.Put(encodeKey(w.start), buf); err != nil {
+				return err
+			}
+
+			removed += len(w.keys) - 1
+		}
+
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("embedded-store: failed to downsample data: %w", err)
+	}
+
+	return removed, nil
+}
+
+func (s *Store) getSeries(tx *bbolt.Tx, deviceID string, measurement string) *bbolt.Bucket {
+	device := tx.Bucket(s.bucket).Bucket([]byte(deviceID))
+	if device == nil {
+		return nil
+	}
+
+	return device.Bucket([]byte(measurement))
+}
+
+func (s *Store) forEachSeries(fn func(series *bbolt.Bucket) error) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		root := tx.Bucket(s.bucket)
+
+		return root.ForEachBucket(func(deviceID []byte) error {
+			device := root.Bucket(deviceID)
+
+			return device.ForEachBucket(func(measurement []byte) error {
+				return fn(device.Bucket(measurement))
+			})
+		})
+	})
+}
+
+type window struct {
+	start  int64
+	keys   [][]byte
+	points []devcore.JSON
+}
+
+func collectWindows(series *bbolt.Bucket, before int64, step int64) ([]*window, error) {
+	var windows []*window
+
+	c := series.Cursor()
+
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		timestamp := decodeKey(k)
+
+		start := timestamp - timestamp%step
+		if start+step > before {
+			break
+		}
+
+		var data devcore.JSON
+		if err := json.Unmarshal(v, &data); err != nil {
+			return nil, fmt.Errorf("invalid point: timestamp=%d err=%w", timestamp, err)
+		}
+
+		if len(windows) == 0 || windows[len(windows)-1].start != start {
+			windows = append(windows, &window{start: start})
+		}
+
+		w := windows[len(windows)-1]
+		w.keys = append(w.keys, append([]byte(nil), k...))
+		w.points = append(w.points, data)
+	}
+
+	return windows, nil
+}
+
+func (w *window) aggregate() devcore.JSON {
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	result := make(devcore.JSON)
+
+	for _, point := range w.points {
+		for key, value := range point {
+			if number, ok := value.(float64); ok {
+				sums[key] += number
+				counts[key]++
+			} else {
+				result[key] = value
+			}
+		}
+	}
+
+	for key, sum := range sums {
+		result[key] = sum / float64(counts[key])
+	}
+
+	result["timestamp"] = float64(w.start)
+
+	return result
+}
+
+func encodeKey(timestamp int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(timestamp))
+
+	return key
+}
+
+func decodeKey(key []byte) int64 {
+	return int64(binary.BigEndian.Uint64(key))
+}
@@ -0,0 +1,212 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+type testClock struct {
+	timestamp int64
+}
+
+func (c *testClock) SetTimestamp(timestamp int64) error {
+	c.timestamp = timestamp
+
+	return nil
+}
+
+func (c *testClock) GetTimestamp() (int64, error) {
+	return c.timestamp, nil
+}
+
+func newTestStore(t *testing.T) *Store {
+	db, err := stcore.NewBboltDB(filepath.Join(t.TempDir(), "bbolt.db"), nil)
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+
+	store, err := NewStore(db, "embedded")
+	require.Nil(t, err)
+
+	return store
+}
+
+func writeTestPoints(t *testing.T, store *Store, timestamps ...int64) {
+	for _, ts := range timestamps {
+		require.Nil(t, store.Write("0xABCD", "telemetry", Point{
+			Timestamp: ts,
+			Data: devcore.JSON{
+				"timestamp":   float64(ts),
+				"temperature": float64(ts * 2),
+				"status":      "ok",
+			},
+		}))
+	}
+}
+
+func testPointTimestamps(points []Point) []int64 {
+	timestamps := []int64{}
+	for _, point := range points {
+		timestamps = append(timestamps, point.Timestamp)
+	}
+
+	return timestamps
+}
+
+func TestStoreQuery(t *testing.T) {
+	store := newTestStore(t)
+
+	points, err := store.Query(Query{DeviceID: "0xABCD", Measurement: "telemetry"})
+	require.Nil(t, err)
+	require.Empty(t, points)
+
+	writeTestPoints(t, store, 30, 10, 20, 40)
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected []int64
+	}{
+		{"all", Query{}, []int64{10, 20, 30, 40}},
+		{"from", Query{From: 20}, []int64{20, 30, 40}},
+		{"to", Query{To: 30}, []int64{10, 20}},
+		{"range", Query{From: 15, To: 35}, []int64{20, 30}},
+		{"limit", Query{From: 20, Limit: 2}, []int64{20, 30}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.query.DeviceID = "0xABCD"
+			test.query.Measurement = "telemetry"
+
+			points, err := store.Query(test.query)
+			require.Nil(t, err)
+			require.Equal(t, test.expected, testPointTimestamps(points))
+		})
+	}
+
+	points, err = store.Query(Query{DeviceID: "0xABCD", Measurement: "telemetry", To: 11})
+	require.Nil(t, err)
+	require.Equal(t, devcore.JSON{
+		"timestamp":   float64(10),
+		"temperature": float64(20),
+		"status":      "ok",
+	}, points[0].Data)
+
+	points, err = store.Query(Query{DeviceID: "0xABCD", Measurement: "registration"})
+	require.Nil(t, err)
+	require.Empty(t, points)
+}
+
+func TestStoreLastTimestamp(t *testing.T) {
+	store := newTestStore(t)
+
+	_, err := store.LastTimestamp("0xABCD", "telemetry")
+	require.Equal(t, status.StatusNoData, err)
+
+	writeTestPoints(t, store, 20, 10)
+
+	timestamp, err := store.LastTimestamp("0xABCD", "telemetry")
+	require.Nil(t, err)
+	require.Equal(t, int64(20), timestamp)
+}
+
+func TestStoreDeleteBefore(t *testing.T) {
+	store := newTestStore(t)
+
+	writeTestPoints(t, store, 10, 20, 30)
+
+	removed, err := store.DeleteBefore(25)
+	require.Nil(t, err)
+	require.Equal(t, 2, removed)
+
+	points, err := store.Query(Query{DeviceID: "0xABCD", Measurement: "telemetry"})
+	require.Nil(t, err)
+	require.Equal(t, []int64{30}, testPointTimestamps(points))
+}
+
+func TestStoreDownsample(t *testing.T) {
+	store := newTestStore(t)
+
+	writeTestPoints(t, store, 60, 70, 80, 130, 180, 190)
+
+	// Windows [60, 120) and [120, 180) are downsampled, [180, 240) isn't complete.
+	removed, err := store.Downsample(200, time.Minute)
+	require.Nil(t, err)
+	require.Equal(t, 2, removed)
+
+	points, err := store.Query(Query{DeviceID: "0xABCD", Measurement: "telemetry"})
+	require.Nil(t, err)
+	require.Equal(t, []int64{60, 130, 180, 190}, testPointTimestamps(points))
+	require.Equal(t, devcore.JSON{
+		"timestamp":   float64(60),
+		"temperature": float64(140),
+		"status":      "ok",
+	}, points[0].Data)
+
+	_, err = store.Downsample(200, 0)
+	require.NotNil(t, err)
+}
+
+func TestCompactor(t *testing.T) {
+	store := newTestStore(t)
+	clock := &testClock{}
+
+	compactor := NewCompactor(store, clock, CompactorParams{
+		Retention:          time.Minute * 10,
+		DownsampleAfter:    time.Minute * 5,
+		DownsampleInterval: time.Minute,
+	})
+	require.NotNil(t, compactor.Run())
+
+	clock.timestamp = 900
+	writeTestPoints(t, store, 200, 360, 370, 700)
+
+	require.Nil(t, compactor.Run())
+
+	points, err := store.Query(Query{DeviceID: "0xABCD", Measurement: "telemetry"})
+	require.Nil(t, err)
+	require.Equal(t, []int64{360, 700}, testPointTimestamps(points))
+}
+
+func TestDataHandler(t *testing.T) {
+	store := newTestStore(t)
+	clock := &testClock{}
+	pipeline := NewPipeline(store)
+
+	handler := pipeline.BuildHandler(clock, "0xABCD", "bonsai-growlab", "home")
+
+	require.NotNil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{}))
+	require.NotNil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{"timestamp": "123"}))
+
+	require.Nil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{
+		"timestamp": float64(123),
+	}))
+	require.Nil(t, handler.HandleStream("0xABCD", "power", devcore.JSON{
+		"timestamp": float64(124),
+	}))
+	require.Equal(t, int64(124), clock.timestamp)
+
+	timestamp, err := pipeline.BuildReader("0xABCD").ReadTimestamp(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, int64(123), timestamp)
+
+	points, err := store.Query(Query{DeviceID: "0xABCD", Measurement: "power"})
+	require.Nil(t, err)
+	require.Equal(t, []int64{124}, testPointTimestamps(points))
+}
@@ -0,0 +1,46 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stembedded
+
+import (
+	"context"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// SystemClockReader reads the UNIX timestamp from the embedded store.
+type SystemClockReader struct {
+	store    *Store
+	deviceID string
+}
+
+// NewSystemClockReader is an initialization of SystemClockReader.
+//
+// Parameters:
+//   - store to read the device data.
+//   - deviceID - device identifier.
+func NewSystemClockReader(store *Store, deviceID string) *SystemClockReader {
+	return &SystemClockReader{
+		store:    store,
+		deviceID: deviceID,
+	}
+}
+
+// ReadTimestamp reads the most recent UNIX timestamp of the device telemetry.
+//
+// Remarks:
+//   - status.StatusNoData is returned if there is no telemetry.
+func (r *SystemClockReader) ReadTimestamp(_ context.Context) (int64, error) {
+	timestamp, err := r.store.LastTimestamp(r.deviceID, "telemetry")
+	if err != nil {
+		return -1, err
+	}
+
+	syscore.LogInf.Printf("embedded-store: read latest device UNIX timestamp: id=%v value=%v",
+		r.deviceID, timestamp)
+
+	return timestamp, nil
+}
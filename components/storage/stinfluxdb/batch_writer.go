@@ -0,0 +1,280 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+// batchWriterStopTimeout - how long to wait for the pending points to be written
+// when the writer is stopped.
+const batchWriterStopTimeout = time.Second * 5
+
+// BatchWriterParams provides various configuration options for BatchWriter.
+type BatchWriterParams struct {
+	// BatchSize - maximum number of points written in a single request.
+	BatchSize int
+
+	// BufferSize - maximum number of points waiting to be written.
+	BufferSize int
+
+	// FlushInterval - how often to write the pending points if the batch isn't full.
+	FlushInterval time.Duration
+
+	// RetryCount - how many times to retry writing the failed batch.
+	RetryCount int
+
+	// RetryInterval - delay before the first retry, doubled for each next retry.
+	RetryInterval time.Duration
+
+	// MaxRetryInterval - maximum delay between retries.
+	MaxRetryInterval time.Duration
+}
+
+// BatchError is reported when the batch can't be written to InfluxDB.
+type BatchError struct {
+	// Size - number of points in the batch.
+	Size int
+
+	// Attempts - number of writing attempts.
+	Attempts int
+
+	// Err - the last writing error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("influxdb-batch-writer: failed to write batch: size=%d attempts=%d"+
+		" err=%v", e.Size, e.Attempts, e.Err)
+}
+
+// Unwrap returns the last writing error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchWriter accumulates points and writes them to InfluxDB in batches,
+// in the standalone goroutine.
+//
+// Remarks:
+//   - The batch is written once it's full, or on each flush interval.
+//   - Failed batch is retried with the exponential backoff, while the new points
+//     are accumulated in the buffer.
+//   - Rejected batch isn't retried, its points are written one by one instead, so
+//     only the rejected points are dropped.
+//   - WritePoint() fails if the buffer is full.
+//   - Pending points are written once more when the parent context is canceled.
+type BatchWriter struct {
+	ctx     context.Context
+	client  api.WriteAPIBlocking
+	params  BatchWriterParams
+	handler syssched.ErrorHandler
+	flushCh chan struct{}
+	doneCh  chan struct{}
+
+	mu     sync.Mutex
+	points []batchPoint
+}
+
+type batchPoint struct {
+	point *write.Point
+	done  func(error)
+}
+
+// NewBatchWriter is an initialization of BatchWriter.
+//
+// Parameters:
+//   - ctx - parent context.
+//   - client to write points to InfluxDB.
+//   - params - various batching configuration options.
+func NewBatchWriter(
+	ctx context.Context,
+	client api.WriteAPIBlocking,
+	params BatchWriterParams,
+) *BatchWriter {
+	return &BatchWriter{
+		ctx:     ctx,
+		client:  client,
+		params:  params,
+		flushCh: make(chan struct{}, 1),
+		doneCh:  make(chan struct{}),
+	}
+}
+
+// SetErrorHandler sets the handler to report failed batches.
+//
+// Remarks:
+//   - Failed batches are logged if the handler isn't set.
+//   - Should be called before Start().
+func (w *BatchWriter) SetErrorHandler(handler syssched.ErrorHandler) {
+	w.handler = handler
+}
+
+// WritePoint adds the point to the pending batch.
+func (w *BatchWriter) WritePoint(point *write.Point, done func(error)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.points) >= w.params.BufferSize {
+		return fmt.Errorf("influxdb-batch-writer: buffer is full: size=%d err=%w",
+			len(w.points), status.StatusError)
+	}
+
+	w.points = append(w.points, batchPoint{point: point, done: done})
+
+	if len(w.points) >= w.params.BatchSize {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Start starts writing batches in the standalone goroutine.
+func (w *BatchWriter) Start() error {
+	go w.run()
+
+	return nil
+}
+
+// Stop waits for the pending points to be written.
+//
+// Remarks:
+//   - Parent context should be canceled before calling Stop().
+func (w *BatchWriter) Stop() error {
+	<-w.doneCh
+
+	return nil
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.doneCh)
+
+	ticker := time.NewTicker(w.params.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush(w.ctx, false)
+
+		case <-w.flushCh:
+			w.flush(w.ctx, true)
+
+		case <-w.ctx.Done():
+			ctx, cancel := context.WithTimeout(
+				context.WithoutCancel(w.ctx), batchWriterStopTimeout)
+			w.flush(ctx, false)
+			cancel()
+
+			return
+		}
+	}
+}
+
+func (w *BatchWriter) flush(ctx context.Context, fullOnly bool) {
+	for {
+		batch := w.takeBatch(fullOnly)
+		if len(batch) == 0 {
+			return
+		}
+
+		w.writeBatch(ctx, batch)
+	}
+}
+
+func (w *BatchWriter) takeBatch(fullOnly bool) []batchPoint {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	size := min(len(w.points), w.params.BatchSize)
+	if fullOnly && size < w.params.BatchSize {
+		return nil
+	}
+
+	batch := w.points[:size:size]
+	w.points = w.points[size:]
+
+	return batch
+}
+
+func (w *BatchWriter) writeBatch(ctx context.Context, batch []batchPoint) {
+	points := make([]*write.Point, 0, len(batch))
+	for _, p := range batch {
+		points = append(points, p.point)
+	}
+
+	attempts, err := w.writePoints(ctx, points)
+
+	// InfluxDB rejects the whole batch if any of its points is invalid, so the points
+	// are written one by one to drop only the offending ones.
+	if len(batch) > 1 && errors.Is(err, status.StatusInvalidArg) {
+		for _, p := range batch {
+			w.writeBatch(ctx, []batchPoint{p})
+		}
+
+		return
+	}
+
+	if err != nil {
+		w.handleError(&BatchError{Size: len(batch), Attempts: attempts, Err: err})
+	}
+
+	for _, p := range batch {
+		p.done(err)
+	}
+}
+
+func (w *BatchWriter) writePoints(ctx context.Context, points []*write.Point) (int, error) {
+	interval := w.params.RetryInterval
+	attempts := 0
+
+	for {
+		attempts++
+
+		err := makeWriteError(w.client.WritePoint(ctx, points...))
+		if err == nil || errors.Is(err, status.StatusInvalidArg) ||
+			attempts > w.params.RetryCount || !w.wait(ctx, interval) {
+			return attempts, err
+		}
+
+		interval = min(interval*2, w.params.MaxRetryInterval)
+	}
+}
+
+func (*BatchWriter) wait(ctx context.Context, interval time.Duration) bool {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *BatchWriter) handleError(err error) {
+	if w.handler != nil {
+		w.handler.HandleError(err)
+	} else {
+		syscore.LogErr.Printf("%v", err)
+	}
+}
@@ -0,0 +1,388 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	influxhttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+type testWriteClient struct {
+	mu      sync.Mutex
+	err     error
+	calls   int
+	batches [][]*write.Point
+
+	// rejected - points with this timestamp are rejected as invalid.
+	rejected int64
+}
+
+func (c *testWriteClient) WriteRecord(_ context.Context, _ ...string) error {
+	return errors.New("not supported")
+}
+
+func (c *testWriteClient) WritePoint(_ context.Context, points ...*write.Point) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+
+	if c.err != nil {
+		return c.err
+	}
+
+	for _, point := range points {
+		if c.rejected != 0 && point.Time().Unix() == c.rejected {
+			return &influxhttp.Error{
+				StatusCode: http.StatusBadRequest,
+				Message:    "field type conflict",
+			}
+		}
+	}
+
+	c.batches = append(c.batches, points)
+
+	return nil
+}
+
+func (*testWriteClient) EnableBatching() {
+}
+
+func (*testWriteClient) Flush(_ context.Context) error {
+	return nil
+}
+
+func (c *testWriteClient) setError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = err
+}
+
+func (c *testWriteClient) getBatches() [][]*write.Point {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.batches
+}
+
+func (c *testWriteClient) getCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls
+}
+
+type testBatchErrorHandler struct {
+	errCh chan error
+}
+
+func (h *testBatchErrorHandler) HandleError(err error) {
+	h.errCh <- err
+}
+
+type testBatchClock struct {
+	mu        sync.Mutex
+	timestamp int64
+}
+
+func (c *testBatchClock) SetTimestamp(timestamp int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.timestamp = timestamp
+
+	return nil
+}
+
+func (c *testBatchClock) GetTimestamp() (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.timestamp, nil
+}
+
+func newTestPoint(timestamp int64) *write.Point {
+	return influxdb2.NewPoint("telemetry", map[string]string{"device_id": "0xABCD"},
+		map[string]any{"timestamp": float64(timestamp)}, time.Unix(timestamp, 0))
+}
+
+func startTestBatchWriter(
+	t *testing.T,
+	client *testWriteClient,
+	params BatchWriterParams,
+	handler *testBatchErrorHandler,
+) (*BatchWriter, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	writer := NewBatchWriter(ctx, client, params)
+	if handler != nil {
+		writer.SetErrorHandler(handler)
+	}
+	require.Nil(t, writer.Start())
+
+	t.Cleanup(func() {
+		cancel()
+		require.Nil(t, writer.Stop())
+	})
+
+	return writer, cancel
+}
+
+func TestBatchWriterFullBatch(t *testing.T) {
+	client := &testWriteClient{}
+
+	writer, _ := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:     2,
+		BufferSize:    10,
+		FlushInterval: time.Hour,
+	}, nil)
+
+	doneCh := make(chan error, 3)
+	done := func(err error) {
+		doneCh <- err
+	}
+
+	require.Nil(t, writer.WritePoint(newTestPoint(1), done))
+	require.Nil(t, writer.WritePoint(newTestPoint(2), done))
+	require.Nil(t, writer.WritePoint(newTestPoint(3), done))
+
+	for n := 0; n < 2; n++ {
+		select {
+		case err := <-doneCh:
+			require.Nil(t, err)
+		case <-time.After(time.Second):
+			require.FailNow(t, "batch isn't written")
+		}
+	}
+
+	// Partial batch is written only on flush.
+	select {
+	case <-doneCh:
+		require.FailNow(t, "partial batch shouldn't be written")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	batches := client.getBatches()
+	require.Equal(t, 1, len(batches))
+	require.Equal(t, 2, len(batches[0]))
+}
+
+func TestBatchWriterFlushInterval(t *testing.T) {
+	client := &testWriteClient{}
+
+	writer, _ := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:     100,
+		BufferSize:    100,
+		FlushInterval: time.Millisecond * 10,
+	}, nil)
+
+	doneCh := make(chan error, 1)
+	require.Nil(t, writer.WritePoint(newTestPoint(1), func(err error) {
+		doneCh <- err
+	}))
+
+	select {
+	case err := <-doneCh:
+		require.Nil(t, err)
+	case <-time.After(time.Second):
+		require.FailNow(t, "batch isn't flushed")
+	}
+
+	require.Equal(t, 1, len(client.getBatches()))
+}
+
+func TestBatchWriterRetry(t *testing.T) {
+	client := &testWriteClient{err: errors.New("influxdb is offline")}
+	handler := &testBatchErrorHandler{errCh: make(chan error, 1)}
+
+	writer, _ := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:        1,
+		BufferSize:       10,
+		FlushInterval:    time.Hour,
+		RetryCount:       2,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: time.Millisecond * 2,
+	}, handler)
+
+	doneCh := make(chan error, 1)
+	require.Nil(t, writer.WritePoint(newTestPoint(1), func(err error) {
+		doneCh <- err
+	}))
+
+	select {
+	case err := <-handler.errCh:
+		var batchErr *BatchError
+		require.True(t, errors.As(err, &batchErr))
+		require.Equal(t, 1, batchErr.Size)
+		require.Equal(t, 3, batchErr.Attempts)
+	case <-time.After(time.Second):
+		require.FailNow(t, "batch error isn't reported")
+	}
+
+	require.NotNil(t, <-doneCh)
+	require.Equal(t, 3, client.getCalls())
+
+	// Batch succeeds after the retry.
+	client.setError(nil)
+
+	require.Nil(t, writer.WritePoint(newTestPoint(2), func(err error) {
+		doneCh <- err
+	}))
+	require.Nil(t, <-doneCh)
+}
+
+func TestBatchWriterRejected(t *testing.T) {
+	client := &testWriteClient{err: &influxhttp.Error{
+		StatusCode: http.StatusBadRequest,
+		Message:    "field type conflict",
+	}}
+	handler := &testBatchErrorHandler{errCh: make(chan error, 1)}
+
+	writer, _ := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:        1,
+		BufferSize:       10,
+		FlushInterval:    time.Hour,
+		RetryCount:       2,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: time.Millisecond * 2,
+	}, handler)
+
+	doneCh := make(chan error, 1)
+	require.Nil(t, writer.WritePoint(newTestPoint(1), func(err error) {
+		doneCh <- err
+	}))
+
+	<-handler.errCh
+
+	// Rejected batch isn't retried.
+	require.ErrorIs(t, <-doneCh, status.StatusInvalidArg)
+	require.Equal(t, 1, client.getCalls())
+}
+
+func TestBatchWriterRejectedPoint(t *testing.T) {
+	client := &testWriteClient{rejected: 2}
+	handler := &testBatchErrorHandler{errCh: make(chan error, 1)}
+
+	writer, _ := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:        3,
+		BufferSize:       10,
+		FlushInterval:    time.Hour,
+		RetryCount:       2,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: time.Millisecond * 2,
+	}, handler)
+
+	doneChs := make([]chan error, 3)
+	for n := range doneChs {
+		doneCh := make(chan error, 1)
+		doneChs[n] = doneCh
+
+		require.Nil(t, writer.WritePoint(newTestPoint(int64(n+1)), func(err error) {
+			doneCh <- err
+		}))
+	}
+
+	var batchErr *BatchError
+	require.ErrorAs(t, <-handler.errCh, &batchErr)
+	require.Equal(t, 1, batchErr.Size)
+
+	// Only the rejected point is dropped.
+	require.Nil(t, <-doneChs[0])
+	require.ErrorIs(t, <-doneChs[1], status.StatusInvalidArg)
+	require.Nil(t, <-doneChs[2])
+
+	// The whole batch, and then each point separately.
+	require.Equal(t, 4, client.getCalls())
+
+	batches := client.getBatches()
+	require.Equal(t, 2, len(batches))
+	require.Equal(t, int64(1), batches[0][0].Time().Unix())
+	require.Equal(t, int64(3), batches[1][0].Time().Unix())
+}
+
+func TestBatchWriterBufferFull(t *testing.T) {
+	client := &testWriteClient{}
+
+	writer := NewBatchWriter(context.Background(), client, BatchWriterParams{
+		BatchSize:     10,
+		BufferSize:    2,
+		FlushInterval: time.Hour,
+	})
+
+	require.Nil(t, writer.WritePoint(newTestPoint(1), func(error) {}))
+	require.Nil(t, writer.WritePoint(newTestPoint(2), func(error) {}))
+	require.NotNil(t, writer.WritePoint(newTestPoint(3), func(error) {}))
+}
+
+func TestBatchWriterStop(t *testing.T) {
+	client := &testWriteClient{}
+
+	writer, cancel := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:     100,
+		BufferSize:    100,
+		FlushInterval: time.Hour,
+	}, nil)
+
+	require.Nil(t, writer.WritePoint(newTestPoint(1), func(error) {}))
+
+	cancel()
+	require.Nil(t, writer.Stop())
+
+	require.Equal(t, 1, len(client.getBatches()))
+}
+
+func TestDataHandlerBatchClock(t *testing.T) {
+	client := &testWriteClient{err: errors.New("influxdb is offline")}
+	handler := &testBatchErrorHandler{errCh: make(chan error, 1)}
+
+	writer, _ := startTestBatchWriter(t, client, BatchWriterParams{
+		BatchSize:     1,
+		BufferSize:    10,
+		FlushInterval: time.Hour,
+	}, handler)
+
+	clock := &testBatchClock{}
+	dataHandler := NewDataHandler(clock, writer, DataHandlerParams{
+		Schema: DefaultPointSchema(),
+	})
+
+	require.Nil(t, dataHandler.HandleTelemetry("0xABCD", devcore.JSON{
+		"timestamp": float64(123),
+	}))
+
+	<-handler.errCh
+
+	// UNIX time isn't advanced, since the data isn't written.
+	timestamp, err := clock.GetTimestamp()
+	require.Nil(t, err)
+	require.Equal(t, int64(0), timestamp)
+
+	client.setError(nil)
+
+	require.Nil(t, dataHandler.HandleTelemetry("0xABCD", devcore.JSON{
+		"timestamp": float64(124),
+	}))
+
+	require.Eventually(t, func() bool {
+		timestamp, err := clock.GetTimestamp()
+		require.Nil(t, err)
+
+		return timestamp == 124
+	}, time.Second, time.Millisecond*10)
+}
@@ -6,77 +6,145 @@
 package stinfluxdb
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
-	"github.com/influxdata/influxdb-client-go/v2/api"
-
 	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/status"
 	"github.com/tendry-lab/device-hub/components/system/syscore"
 )
 
+// DataHandlerParams provides various configuration options for DataHandler.
+type DataHandlerParams struct {
+	// Schema - how the device data is converted to the InfluxDB point.
+	Schema PointSchema
+
+	// Type - device type.
+	Type string
+
+	// Desc - device description.
+	Desc string
+
+	// Fallback - handles the data which failed to be written after the handler has
+	// returned, e.g. buffers it to be written later.
+	//
+	// Remarks:
+	//  - Data failed to be written asynchronously is dropped if nil.
+	//  - Data rejected by InfluxDB isn't passed to the fallback.
+	Fallback devcore.DataHandler
+}
+
 // DataHandler stores incoming data in influxDB.
 //
 // References:
 //   - https://docs.influxdata.com/influxdb/cloud/get-started
 //   - https://docs.influxdata.com/influxdb/cloud/api-guide/client-libraries/go/
 type DataHandler struct {
-	ctx    context.Context
 	clock  syscore.SystemClock
-	client api.WriteAPIBlocking
+	writer PointWriter
+	params DataHandlerParams
 }
 
 // NewDataHandler initializes influxDB handler.
 //
 // Parameters:
-//   - ctx - parent context.
-//   - clock to update the most recent UNIX time.
-//   - client to write data to the influxdb.
+//   - clock to update the most recent UNIX time, once the data is written.
+//   - writer to write data to the influxdb.
+//   - params - how the device data is written to the influxdb.
 func NewDataHandler(
-	ctx context.Context,
 	clock syscore.SystemClock,
-	client api.WriteAPIBlocking,
+	writer PointWriter,
+	params DataHandlerParams,
 ) *DataHandler {
 	return &DataHandler{
-		ctx:    ctx,
 		clock:  clock,
-		client: client,
+		writer: writer,
+		params: params,
 	}
 }
 
 // HandleTelemetry stores telemetry data in influxDB.
 func (h *DataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
-	return h.handleData("telemetry", deviceID, js)
+	return h.handleData("telemetry", deviceID, js, func(fallback devcore.DataHandler) error {
+		return fallback.HandleTelemetry(deviceID, js)
+	})
 }
 
 // HandleRegistration stores registration data in influxDB.
 func (h *DataHandler) HandleRegistration(deviceID string, js devcore.JSON) error {
-	return h.handleData("registration", deviceID, js)
+	return h.handleData("registration", deviceID, js,
+		func(fallback devcore.DataHandler) error {
+			return fallback.HandleRegistration(deviceID, js)
+		})
+}
+
+// HandleStream stores the device stream data in influxDB.
+func (h *DataHandler) HandleStream(
+	deviceID string,
+	measurement string,
+	js devcore.JSON,
+) error {
+	return h.handleData(measurement, deviceID, js, func(fallback devcore.DataHandler) error {
+		return fallback.HandleStream(deviceID, measurement, js)
+	})
 }
 
-func (h *DataHandler) handleData(dataID string, deviceID string, js devcore.JSON) error {
+func (h *DataHandler) handleData(
+	dataID string,
+	deviceID string,
+	js devcore.JSON,
+	fallback func(devcore.DataHandler) error,
+) error {
 	ts, ok := js["timestamp"]
 	if !ok {
-		return fmt.Errorf("influxdb-data-handler: missed timestamp field")
+		return fmt.Errorf("%w: influxdb-data-handler: missed timestamp field",
+			status.StatusInvalidArg)
 	}
 
 	timestamp, ok := ts.(float64)
 	if !ok {
-		return fmt.Errorf("influxdb-data-handler: invalid type for timestamp")
+		return fmt.Errorf("%w: influxdb-data-handler: invalid type for timestamp",
+			status.StatusInvalidArg)
 	}
 
 	unixTimestamp := time.Unix(int64(timestamp), 0)
 
-	point := influxdb2.NewPoint(dataID,
-		map[string]string{"device_id": deviceID},
-		js,
-		unixTimestamp)
+	point := h.params.Schema.makePoint(
+		dataID, deviceID, h.params.Type, h.params.Desc, js, unixTimestamp)
 
-	if err := h.client.WritePoint(h.ctx, point); err != nil {
+	if err := h.writer.WritePoint(point, func(err error) {
+		if err != nil {
+			h.handleFailure(dataID, deviceID, err, fallback)
+
+			return
+		}
+
+		// The most recent UNIX time is advanced only when the data is durable.
+		if err := h.clock.SetTimestamp(unixTimestamp.Unix()); err != nil {
+			syscore.LogErr.Printf("influxdb-data-handler: failed to update UNIX time:"+
+				" device_id=%s err=%v", deviceID, err)
+		}
+	}); err != nil {
 		return fmt.Errorf("influxdb-data-handler: failed to write to DB: %w", err)
 	}
 
-	return h.clock.SetTimestamp(unixTimestamp.Unix())
+	return nil
+}
+
+func (h *DataHandler) handleFailure(
+	dataID string,
+	deviceID string,
+	err error,
+	fallback func(devcore.DataHandler) error,
+) {
+	// The writing error itself is reported by the writer.
+	if h.params.Fallback == nil || errors.Is(err, status.StatusInvalidArg) {
+		return
+	}
+
+	if err := fallback(h.params.Fallback); err != nil {
+		syscore.LogErr.Printf("influxdb-data-handler: fallback failed, data dropped:"+
+			" device_id=%s data=%s err=%v", deviceID, dataID, err)
+	}
 }
@@ -0,0 +1,130 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+// HistoryReader reads the stored device data from the influxdb.
+type HistoryReader struct {
+	bucket string
+	client api.QueryAPI
+}
+
+// NewHistoryReader is an initialization of HistoryReader.
+//
+// Parameters:
+//   - client to query InfluxDB.
+//   - bucket - InfluxDB bucket name.
+func NewHistoryReader(client api.QueryAPI, bucket string) *HistoryReader {
+	return &HistoryReader{
+		bucket: bucket,
+		client: client,
+	}
+}
+
+// ReadHistory reads the device data points from the influxdb.
+func (r *HistoryReader) ReadHistory(
+	ctx context.Context,
+	query stcore.HistoryQuery,
+) ([]stcore.HistoryPoint, error) {
+	result, err := r.client.Query(ctx, r.makeQuery(query))
+	if err != nil {
+		return nil, fmt.Errorf("influxdb: query failed: %w", err)
+	}
+	defer result.Close()
+
+	points := make(map[int64]map[string]any)
+
+	for result.Next() {
+		record := result.Record()
+
+		timestamp := record.Time().Unix()
+
+		fields, ok := points[timestamp]
+		if !ok {
+			fields = make(map[string]any)
+			points[timestamp] = fields
+		}
+
+		fields[record.Field()] = record.Value()
+	}
+
+	if result.Err() != nil {
+		return nil, fmt.Errorf("influxdb: query error: %w", result.Err())
+	}
+
+	history := make([]stcore.HistoryPoint, 0, len(points))
+	for timestamp, fields := range points {
+		history = append(history, stcore.HistoryPoint{
+			Timestamp: timestamp,
+			Fields:    fields,
+		})
+	}
+
+	slices.SortFunc(history, func(a, b stcore.HistoryPoint) int {
+		return cmp.Compare(a.Timestamp, b.Timestamp)
+	})
+
+	return history, nil
+}
+
+func (r *HistoryReader) makeQuery(query stcore.HistoryQuery) string {
+	var b strings.Builder
+
+	if query.Step > 0 {
+		b.WriteString("import \"types\"\n\n")
+	}
+
+	fmt.Fprintf(&b, `from(bucket: %s)
+  |> range(start: %s, stop: %s)
+  |> filter(fn: (r) => r["_measurement"] == %s and r["device_id"] == %s)`,
+		fluxString(r.bucket),
+		fluxTime(query.From), fluxTime(query.To),
+		fluxString(query.Measurement), fluxString(query.DeviceID))
+
+	if len(query.Fields) > 0 {
+		conditions := make([]string, 0, len(query.Fields))
+		for _, field := range query.Fields {
+			conditions = append(conditions, `r["_field"] == `+fluxString(field))
+		}
+
+		fmt.Fprintf(&b, "\n  |> filter(fn: (r) => %s)", strings.Join(conditions, " or "))
+	}
+
+	if query.Step > 0 {
+		// Samples of all tag sets are aggregated together, e.g. if the device
+		// firmware version is changed within the step.
+		fmt.Fprintf(&b, `
+  |> filter(fn: (r) => types.isNumeric(v: r._value))
+  |> group(columns: ["_field"])
+  |> aggregateWindow(every: %ds, fn: %s, timeSrc: "_start", createEmpty: false)`,
+			int64(query.Step/time.Second), query.Aggregation)
+	}
+
+	b.WriteString(`
+  |> keep(columns: ["_time", "_field", "_value"])`)
+
+	return b.String()
+}
+
+func fluxString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`).Replace(s) + `"`
+}
+
+func fluxTime(timestamp int64) string {
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
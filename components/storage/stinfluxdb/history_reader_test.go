@@ -0,0 +1,50 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+func TestHistoryReaderQuery(t *testing.T) {
+	reader := NewHistoryReader(nil, "device-hub")
+
+	require.Equal(t, `from(bucket: "device-hub")
+  |> range(start: 2025-01-01T00:00:00Z, stop: 2025-01-01T01:00:00Z)
+  |> filter(fn: (r) => r["_measurement"] == "telemetry" and r["device_id"] == "0xABCD")
+  |> keep(columns: ["_time", "_field", "_value"])`,
+		reader.makeQuery(stcore.HistoryQuery{
+			DeviceID:    "0xABCD",
+			Measurement: "telemetry",
+			From:        1735689600,
+			To:          1735693200,
+		}))
+
+	require.Equal(t, `import "types"
+
+from(bucket: "device-hub")
+  |> range(start: 2025-01-01T00:00:00Z, stop: 2025-01-01T01:00:00Z)
+  |> filter(fn: (r) => r["_measurement"] == "telemetry" and r["device_id"] == "0x\"AB\"")
+  |> filter(fn: (r) => r["_field"] == "temperature" or r["_field"] == "humidity")
+  |> filter(fn: (r) => types.isNumeric(v: r._value))
+  |> group(columns: ["_field"])
+  |> aggregateWindow(every: 300s, fn: max, timeSrc: "_start", createEmpty: false)
+  |> keep(columns: ["_time", "_field", "_value"])`,
+		reader.makeQuery(stcore.HistoryQuery{
+			DeviceID:    `0x"AB"`,
+			Measurement: "telemetry",
+			From:        1735689600,
+			To:          1735693200,
+			Fields:      []string{"temperature", "humidity"},
+			Step:        time.Minute * 5,
+			Aggregation: stcore.AggregationMax,
+		}))
+}
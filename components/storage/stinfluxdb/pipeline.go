@@ -7,6 +7,8 @@ package stinfluxdb
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/api"
@@ -32,6 +34,9 @@ type DBParams struct {
 
 	// TimestampRestoreRange - number of days to use for the timestamp lookup.
 	TimestampRestoreRange int
+
+	// Batch - batching options, points are written one by one if the batch size is zero.
+	Batch BatchWriterParams
 }
 
 // Pipeline contains various building blocks for persisting data in influxdb.
@@ -40,7 +45,11 @@ type Pipeline struct {
 	ctx         context.Context
 	dbClient    influxdb2.Client
 	queryClient api.QueryAPI
-	writeClient api.WriteAPIBlocking
+	writer      PointWriter
+	batchWriter *BatchWriter
+
+	mu      sync.RWMutex
+	schemas map[string]PointSchema
 }
 
 // NewPipeline initializes all components associated with the influxdb subsystem.
@@ -53,13 +62,53 @@ func NewPipeline(ctx context.Context, params DBParams) *Pipeline {
 	writeClient := dbClient.WriteAPIBlocking(params.Org, params.Bucket)
 	queryClient := dbClient.QueryAPI(params.Org)
 
-	return &Pipeline{
+	p := &Pipeline{
 		params:      params,
 		ctx:         ctx,
 		dbClient:    dbClient,
 		queryClient: queryClient,
-		writeClient: writeClient,
+		schemas:     make(map[string]PointSchema),
+	}
+
+	if params.Batch.BatchSize > 0 {
+		p.batchWriter = NewBatchWriter(ctx, writeClient, params.Batch)
+		p.writer = p.batchWriter
+	} else {
+		p.writer = NewBlockingPointWriter(ctx, writeClient)
+	}
+
+	return p
+}
+
+// Start starts writing batches, if batching is enabled.
+func (p *Pipeline) Start() error {
+	if p.batchWriter != nil {
+		return p.batchWriter.Start()
+	}
+
+	return nil
+}
+
+// RegisterSchema registers the point schema for the device type.
+//
+// Remarks:
+//   - DefaultPointSchema() is used for the types without schema.
+//   - Should be called before the handlers for the device type are built.
+func (p *Pipeline) RegisterSchema(typ string, schema PointSchema) error {
+	if typ == "" {
+		return fmt.Errorf("influxdb-pipeline: empty device type")
+	}
+
+	if err := schema.Validate(); err != nil {
+		return fmt.Errorf("influxdb-pipeline: invalid schema: type=%s err=%w", typ, err)
 	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.schemas[typ] = schema
+
+	return nil
 }
 
 // BuildReader builds reader that retrieves device timestamps from InfluxDB.
@@ -71,16 +120,70 @@ func (p *Pipeline) BuildReader(deviceID string) stcore.SystemClockReader {
 	})
 }
 
+// BuildHistoryReader builds reader that retrieves the stored device data from InfluxDB.
+func (p *Pipeline) BuildHistoryReader() stcore.HistoryReader {
+	return NewHistoryReader(p.queryClient, p.params.Bucket)
+}
+
 // BuildHandler builds the data handler that stores the device data in InfluxDB.
 func (p *Pipeline) BuildHandler(
+	clock syscore.SystemClock,
+	deviceID string,
+	typ string,
+	desc string,
+) devcore.DataHandler {
+	return p.BuildFallbackHandler(clock, deviceID, typ, desc, nil)
+}
+
+// BuildFallbackHandler builds the data handler that stores the device data in
+// InfluxDB, and passes the data failed to be written in batch to the fallback.
+//
+// Remarks:
+//   - Fallback isn't used if batching is disabled, since the writing error is
+//     returned by the handler itself.
+func (p *Pipeline) BuildFallbackHandler(
 	clock syscore.SystemClock,
 	_ string,
+	typ string,
+	desc string,
+	fallback devcore.DataHandler,
 ) devcore.DataHandler {
-	return NewDataHandler(p.ctx, clock, p.writeClient)
+	params := DataHandlerParams{
+		Schema: p.getSchema(typ),
+		Type:   typ,
+		Desc:   desc,
+	}
+
+	if p.batchWriter != nil {
+		params.Fallback = fallback
+	}
+
+	return NewDataHandler(clock, p.writer, params)
+}
+
+func (p *Pipeline) getSchema(typ string) PointSchema {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	schema, ok := p.schemas[typ]
+	if !ok {
+		return DefaultPointSchema()
+	}
+
+	return schema
 }
 
 // Stop stops writing data to the DB.
+//
+// Remarks:
+//   - Pending batches are written before the DB client is closed.
 func (p *Pipeline) Stop() error {
+	if p.batchWriter != nil {
+		if err := p.batchWriter.Stop(); err != nil {
+			return err
+		}
+	}
+
 	p.dbClient.Close()
 
 	return nil
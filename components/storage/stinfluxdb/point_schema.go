@@ -0,0 +1,281 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// FieldType is a type to which the field value is coerced before writing to InfluxDB.
+type FieldType int
+
+const (
+	// FieldTypeFloat - 64-bit floating point number.
+	FieldTypeFloat FieldType = iota
+
+	// FieldTypeInt - 64-bit signed integer, fractional part is truncated.
+	FieldTypeInt
+
+	// FieldTypeBool - boolean value.
+	FieldTypeBool
+
+	// FieldTypeString - string value.
+	FieldTypeString
+)
+
+// ParseFieldType parses the field type from its name: float, int, bool or string.
+func ParseFieldType(s string) (FieldType, error) {
+	switch s {
+	case "float":
+		return FieldTypeFloat, nil
+	case "int":
+		return FieldTypeInt, nil
+	case "bool":
+		return FieldTypeBool, nil
+	case "string":
+		return FieldTypeString, nil
+	default:
+		return FieldTypeFloat, fmt.Errorf("unknown field type: %s", s)
+	}
+}
+
+// PointSchema describes how the device data is converted to the InfluxDB point.
+//
+// Remarks:
+//   - Nested objects and arrays are flattened, e.g. {"sensors": {"soil": {"moisture": 42}}}
+//     is written as the "sensors_soil_moisture" field.
+//   - Fields with null values are skipped.
+//   - Field names in Tags and Fields are the flattened names.
+type PointSchema struct {
+	// Separator - separator of the flattened keys of the nested objects and arrays.
+	Separator string
+
+	// Tags - fields promoted to tags, e.g. firmware version.
+	Tags []string
+
+	// TypeTag to store the device type in the "type" tag.
+	TypeTag bool
+
+	// DescTag to store the device description in the "desc" tag.
+	DescTag bool
+
+	// Fields - types to which the field values are coerced, keyed by the field name.
+	//
+	// Remarks:
+	//  - Values of the missed fields are written as is.
+	//  - Fields with values that can't be coerced are skipped.
+	Fields map[string]FieldType
+}
+
+// DefaultPointSchema returns the schema which only flattens the nested device data.
+func DefaultPointSchema() PointSchema {
+	return PointSchema{
+		Separator: "_",
+	}
+}
+
+// Validate ensures the schema is correctly configured.
+func (s PointSchema) Validate() error {
+	if s.Separator == "" {
+		return fmt.Errorf("empty separator")
+	}
+
+	tags := make(map[string]struct{})
+
+	for _, tag := range s.Tags {
+		if tag == "" {
+			return fmt.Errorf("empty tag")
+		}
+
+		if tag == "device_id" || tag == "type" || tag == "desc" {
+			return fmt.Errorf("reserved tag: %s", tag)
+		}
+
+		if _, ok := tags[tag]; ok {
+			return fmt.Errorf("duplicate tag: %s", tag)
+		}
+
+		if _, ok := s.Fields[tag]; ok {
+			return fmt.Errorf("tag can't be coerced: %s", tag)
+		}
+
+		tags[tag] = struct{}{}
+	}
+
+	for field, typ := range s.Fields {
+		if field == "" {
+			return fmt.Errorf("empty field")
+		}
+
+		if field == "timestamp" {
+			return fmt.Errorf("timestamp field can't be coerced")
+		}
+
+		if typ < FieldTypeFloat || typ > FieldTypeString {
+			return fmt.Errorf("invalid field type: field=%s type=%d", field, typ)
+		}
+	}
+
+	return nil
+}
+
+func (s PointSchema) makePoint(
+	measurement string,
+	deviceID string,
+	typ string,
+	desc string,
+	js devcore.JSON,
+	ts time.Time,
+) *write.Point {
+	fields := make(map[string]any)
+
+	// Iterate in the sorted order, so the colliding flattened keys are resolved
+	// in the same way for each point.
+	keys := make([]string, 0, len(js))
+	for key := range js {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s.flatten(fields, key, js[key])
+	}
+
+	tags := map[string]string{"device_id": deviceID}
+
+	if s.TypeTag {
+		tags["type"] = typ
+	}
+	if s.DescTag {
+		tags["desc"] = desc
+	}
+
+	for _, tag := range s.Tags {
+		value, ok := fields[tag]
+		if !ok {
+			continue
+		}
+
+		str, err := coerceField(value, FieldTypeString)
+		if err != nil {
+			continue
+		}
+
+		tags[tag] = str.(string)
+		delete(fields, tag)
+	}
+
+	for field, fieldType := range s.Fields {
+		value, ok := fields[field]
+		if !ok {
+			continue
+		}
+
+		coerced, err := coerceField(value, fieldType)
+		if err != nil {
+			syscore.LogWrn.Printf("influxdb-data-handler: skip field: measurement=%s"+
+				" device_id=%s field=%s err=%v", measurement, deviceID, field, err)
+
+			delete(fields, field)
+
+			continue
+		}
+
+		fields[field] = coerced
+	}
+
+	return influxdb2.NewPoint(measurement, tags, fields, ts)
+}
+
+func (s PointSchema) flatten(fields map[string]any, key string, value any) {
+	switch v := value.(type) {
+	case nil:
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			s.flatten(fields, key+s.Separator+k, v[k])
+		}
+	case []any:
+		for n, item := range v {
+			s.flatten(fields, key+s.Separator+strconv.Itoa(n), item)
+		}
+	default:
+		fields[key] = value
+	}
+}
+
+func coerceField(value any, typ FieldType) (any, error) {
+	switch typ {
+	case FieldTypeFloat:
+		return coerceFloat(value)
+
+	case FieldTypeInt:
+		f, err := coerceFloat(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if math.IsNaN(f) || f > math.MaxInt64 || f < math.MinInt64 {
+			return nil, fmt.Errorf("integer overflow: %v", value)
+		}
+
+		return int64(f), nil
+
+	case FieldTypeBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case float64:
+			return v != 0, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+
+	case FieldTypeString:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported value: %v", value)
+}
+
+func coerceFloat(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+
+		return 0, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+
+	return 0, fmt.Errorf("unsupported value: %v", value)
+}
@@ -0,0 +1,160 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+)
+
+func testPointFields(point *write.Point) map[string]any {
+	fields := make(map[string]any)
+	for _, field := range point.FieldList() {
+		fields[field.Key] = field.Value
+	}
+
+	return fields
+}
+
+func testPointTags(point *write.Point) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range point.TagList() {
+		tags[tag.Key] = tag.Value
+	}
+
+	return tags
+}
+
+func TestPointSchemaFlatten(t *testing.T) {
+	js := devcore.JSON{
+		"timestamp": float64(123),
+		"sensors": map[string]any{
+			"soil": map[string]any{
+				"moisture": float64(42),
+				"status":   "wet",
+			},
+		},
+		"values": []any{float64(1), float64(2)},
+		"empty":  nil,
+	}
+
+	point := DefaultPointSchema().makePoint(
+		"telemetry", "0xABCD", "bonsai-growlab", "home", js, time.Unix(123, 0))
+
+	require.Equal(t, "telemetry", point.Name())
+	require.Equal(t, time.Unix(123, 0), point.Time())
+	require.Equal(t, map[string]string{"device_id": "0xABCD"}, testPointTags(point))
+	require.Equal(t, map[string]any{
+		"timestamp":             float64(123),
+		"sensors_soil_moisture": float64(42),
+		"sensors_soil_status":   "wet",
+		"values_0":              float64(1),
+		"values_1":              float64(2),
+	}, testPointFields(point))
+}
+
+func TestPointSchemaTags(t *testing.T) {
+	js := devcore.JSON{
+		"timestamp": float64(123),
+		"firmware": map[string]any{
+			"version": "1.2.3",
+			"build":   float64(42),
+		},
+	}
+
+	schema := PointSchema{
+		Separator: ".",
+		Tags:      []string{"firmware.version", "firmware.build", "missed"},
+		TypeTag:   true,
+		DescTag:   true,
+	}
+	require.Nil(t, schema.Validate())
+
+	point := schema.makePoint(
+		"registration", "0xABCD", "bonsai-growlab", "home", js, time.Unix(123, 0))
+
+	require.Equal(t, map[string]string{
+		"device_id":        "0xABCD",
+		"type":             "bonsai-growlab",
+		"desc":             "home",
+		"firmware.version": "1.2.3",
+		"firmware.build":   "42",
+	}, testPointTags(point))
+	require.Equal(t, map[string]any{
+		"timestamp": float64(123),
+	}, testPointFields(point))
+}
+
+func TestPointSchemaCoerce(t *testing.T) {
+	js := devcore.JSON{
+		"timestamp":   float64(123),
+		"count":       float64(42.7),
+		"temperature": "21.5",
+		"enabled":     float64(1),
+		"code":        float64(404),
+		"invalid":     "foo",
+		"untouched":   true,
+	}
+
+	schema := DefaultPointSchema()
+	schema.Fields = map[string]FieldType{
+		"count":       FieldTypeInt,
+		"temperature": FieldTypeFloat,
+		"enabled":     FieldTypeBool,
+		"code":        FieldTypeString,
+		"invalid":     FieldTypeInt,
+		"missed":      FieldTypeInt,
+	}
+	require.Nil(t, schema.Validate())
+
+	point := schema.makePoint(
+		"telemetry", "0xABCD", "bonsai-growlab", "home", js, time.Unix(123, 0))
+
+	require.Equal(t, map[string]any{
+		"timestamp":   float64(123),
+		"count":       int64(42),
+		"temperature": float64(21.5),
+		"enabled":     true,
+		"code":        "404",
+		"untouched":   true,
+	}, testPointFields(point))
+}
+
+func TestPointSchemaValidateInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema PointSchema
+	}{
+		{"empty separator", PointSchema{}},
+		{"empty tag", PointSchema{Separator: "_", Tags: []string{""}}},
+		{"reserved tag", PointSchema{Separator: "_", Tags: []string{"device_id"}}},
+		{"duplicate tag", PointSchema{Separator: "_", Tags: []string{"foo", "foo"}}},
+		{"coerced tag", PointSchema{
+			Separator: "_",
+			Tags:      []string{"foo"},
+			Fields:    map[string]FieldType{"foo": FieldTypeInt},
+		}},
+		{"coerced timestamp", PointSchema{
+			Separator: "_",
+			Fields:    map[string]FieldType{"timestamp": FieldTypeInt},
+		}},
+		{"invalid field type", PointSchema{
+			Separator: "_",
+			Fields:    map[string]FieldType{"foo": FieldType(42)},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NotNil(t, test.schema.Validate())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stinfluxdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	influxhttp "github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+// PointWriter writes points to InfluxDB.
+type PointWriter interface {
+	// WritePoint writes the point to InfluxDB.
+	//
+	// Parameters:
+	//  - point to write.
+	//  - done is called once the point is written, or writing has failed.
+	//
+	// Remarks:
+	//  - Implementation may return before the point is written, in which case
+	//    the writing error is reported only to done.
+	//  - status.StatusInvalidArg is returned if the point is rejected by InfluxDB,
+	//    and writing it again won't succeed.
+	WritePoint(point *write.Point, done func(error)) error
+}
+
+// BlockingPointWriter writes each point in a separate request, before returning.
+type BlockingPointWriter struct {
+	ctx    context.Context
+	client api.WriteAPIBlocking
+}
+
+// NewBlockingPointWriter is an initialization of BlockingPointWriter.
+//
+// Parameters:
+//   - ctx - parent context.
+//   - client to write points to InfluxDB.
+func NewBlockingPointWriter(
+	ctx context.Context,
+	client api.WriteAPIBlocking,
+) *BlockingPointWriter {
+	return &BlockingPointWriter{
+		ctx:    ctx,
+		client: client,
+	}
+}
+
+// WritePoint writes the point to InfluxDB.
+func (w *BlockingPointWriter) WritePoint(point *write.Point, done func(error)) error {
+	err := makeWriteError(w.client.WritePoint(w.ctx, point))
+
+	done(err)
+
+	return err
+}
+
+// makeWriteError marks the error with status.StatusInvalidArg if the points are
+// rejected by InfluxDB, e.g. due to the field type conflict.
+func makeWriteError(err error) error {
+	var httpErr *influxhttp.Error
+
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusBadRequest ||
+		httpErr.StatusCode == http.StatusUnprocessableEntity) {
+		return fmt.Errorf("%w: points rejected: %w", status.StatusInvalidArg, err)
+	}
+
+	return err
+}
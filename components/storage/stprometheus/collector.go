@@ -0,0 +1,268 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stprometheus
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/device/devstore"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+const (
+	// metricPrefix - prefix of the device telemetry metric names.
+	metricPrefix = "device_telemetry_"
+
+	// fieldSeparator - separator of the flattened nested keys.
+	fieldSeparator = "_"
+)
+
+// CollectorParams provides various configuration options for Collector.
+type CollectorParams struct {
+	// StaleInterval - device telemetry not updated within this interval isn't
+	// exposed, telemetry doesn't become stale if zero.
+	StaleInterval time.Duration
+}
+
+// Collector keeps the latest numeric device telemetry in memory, and exposes it
+// in the OpenMetrics format.
+//
+// Remarks:
+//   - Each telemetry field is exposed as a gauge, named after the flattened field
+//     with the "device_telemetry_" prefix, e.g. device_telemetry_sensors_soil_moisture.
+//   - Each sample is labeled with the device_id, type and desc of the device.
+//   - Boolean fields are exposed as 0 or 1, non-numeric fields are skipped.
+//   - Fields which map to the same metric name, e.g. "a.b" and "a_b", are skipped,
+//     since their samples can't be told apart.
+type Collector struct {
+	clock  syscore.MonotonicClock
+	params CollectorParams
+
+	mu      sync.Mutex
+	devices map[string]*deviceTelemetry
+}
+
+type deviceTelemetry struct {
+	updatedAt time.Time
+
+	// fields - field values keyed by the metric name.
+	fields map[string]float64
+
+	// collisions - metric names which several fields map to.
+	collisions map[string]bool
+}
+
+// NewCollector is an initialization of Collector.
+//
+// Parameters:
+//   - clock to detect the stale telemetry.
+//   - params - various collector options.
+func NewCollector(clock syscore.MonotonicClock, params CollectorParams) *Collector {
+	return &Collector{
+		clock:   clock,
+		params:  params,
+		devices: make(map[string]*deviceTelemetry),
+	}
+}
+
+// BuildHandler builds the data handler that keeps the latest device telemetry.
+//
+// Remarks:
+//   - The handler doesn't update the clock, it's expected to be updated by the
+//     persistent storage handler.
+func (c *Collector) BuildHandler(
+	_ syscore.SystemClock,
+	_ string,
+	_ string,
+	_ string,
+) devcore.DataHandler {
+	return &collectorDataHandler{collector: c}
+}
+
+func (c *Collector) update(deviceID string, js devcore.JSON) {
+	fields := make(map[string]float64)
+	collisions := make(map[string]bool)
+
+	for field, value := range devcore.FlattenJSON(js, fieldSeparator) {
+		var v float64
+
+		switch value := value.(type) {
+		case float64:
+			v = value
+		case bool:
+			if value {
+				v = 1
+			}
+		default:
+			continue
+		}
+
+		name := metricPrefix + sanitizeName(field)
+
+		if _, ok := fields[name]; ok || collisions[name] {
+			collisions[name] = true
+			delete(fields, name)
+
+			continue
+		}
+
+		fields[name] = v
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Collision is logged once, and logged again only if it goes away and reappears.
+	var prevCollisions map[string]bool
+	if prev, ok := c.devices[deviceID]; ok {
+		prevCollisions = prev.collisions
+	}
+
+	for name := range collisions {
+		if !prevCollisions[name] {
+			syscore.LogWrn.Printf("telemetry fields skipped, metric name collision:"+
+				" device_id=%s metric=%s", deviceID, name)
+		}
+	}
+
+	c.devices[deviceID] = &deviceTelemetry{
+		updatedAt:  c.clock.Now(),
+		fields:     fields,
+		collisions: collisions,
+	}
+}
+
+// format formats the telemetry of the provided devices in the OpenMetrics format.
+//
+// Remarks:
+//   - Telemetry of the devices which aren't provided is dropped, since they're removed.
+func (c *Collector) format(items []devstore.StoreItem) []byte {
+	// Metric family samples should be grouped together.
+	families := make(map[string][]string)
+
+	for _, sample := range c.collect(items) {
+		labels := fmt.Sprintf(`{device_id="%s",type="%s",desc="%s"}`,
+			escapeLabel(sample.item.ID),
+			escapeLabel(sample.item.Type),
+			escapeLabel(sample.item.Desc))
+
+		for name, value := range sample.fields {
+			families[name] = append(families[name],
+				name+labels+" "+strconv.FormatFloat(value, 'g', -1, 64))
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var buf bytes.Buffer
+
+	for _, name := range names {
+		lines := families[name]
+		slices.Sort(lines)
+
+		fmt.Fprintf(&buf, "# TYPE %s gauge\n", name)
+
+		for _, line := range lines {
+			buf.WriteString(line)
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.Bytes()
+}
+
+type collectorSample struct {
+	item   devstore.StoreItem
+	fields map[string]float64
+}
+
+func (c *Collector) collect(storeItems []devstore.StoreItem) []collectorSample {
+	items := make(map[string]devstore.StoreItem)
+	for _, item := range storeItems {
+		if item.ID == "" {
+			continue
+		}
+
+		if _, ok := items[item.ID]; !ok {
+			items[item.ID] = item
+		}
+	}
+
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var samples []collectorSample
+
+	for deviceID, telemetry := range c.devices {
+		item, ok := items[deviceID]
+		if !ok {
+			// The device is removed.
+			delete(c.devices, deviceID)
+
+			continue
+		}
+
+		if c.params.StaleInterval > 0 &&
+			now.Sub(telemetry.updatedAt) > c.params.StaleInterval {
+			delete(c.devices, deviceID)
+
+			continue
+		}
+
+		samples = append(samples, collectorSample{
+			item:   item,
+			fields: telemetry.fields,
+		})
+	}
+
+	return samples
+}
+
+func sanitizeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			r == '_' {
+			return r
+		}
+
+		return '_'
+	}, s)
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+type collectorDataHandler struct {
+	collector *Collector
+}
+
+func (h *collectorDataHandler) HandleTelemetry(deviceID string, js devcore.JSON) error {
+	h.collector.update(deviceID, js)
+
+	return nil
+}
+
+func (*collectorDataHandler) HandleRegistration(string, devcore.JSON) error {
+	return nil
+}
+
+func (*collectorDataHandler) HandleStream(string, string, devcore.JSON) error {
+	return nil
+}
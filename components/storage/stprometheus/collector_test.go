@@ -0,0 +1,158 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stprometheus
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/device/devstore"
+)
+
+type testStore struct {
+	items []devstore.StoreItem
+}
+
+func (*testStore) Add(string, string, string, devstore.DeviceOptions) error {
+	return nil
+}
+
+func (*testStore) Remove(string) error {
+	return nil
+}
+
+func (*testStore) Update(string, string, string, string) error {
+	return nil
+}
+
+func (s *testStore) GetDesc() []devstore.StoreItem {
+	return s.items
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func formatTestCollector(t *testing.T, collector *Collector, store *testStore) string {
+	var buf bytes.Buffer
+	require.Nil(t, NewMetricsSource(collector, store).WriteMetrics(&buf))
+
+	return buf.String()
+}
+
+func TestCollectorFormat(t *testing.T) {
+	store := &testStore{items: []devstore.StoreItem{
+		{ID: "0xABCD", Type: "bonsai-growlab", Desc: `room "plant"`},
+		{ID: "0xBCDE", Type: "bonsai-growlab", Desc: "kitchen"},
+		{ID: "", Type: "bonsai-growlab", Desc: "unregistered"},
+	}}
+
+	collector := NewCollector(&testClock{}, CollectorParams{})
+
+	handler := collector.BuildHandler(nil, "0xABCD", "bonsai-growlab", "home")
+	require.Nil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{
+		"sensors": map[string]any{
+			"soil-moisture": float64(42),
+		},
+		"enabled": true,
+		"status":  "ok",
+	}))
+	require.Nil(t, handler.HandleRegistration("0xABCD", devcore.JSON{
+		"version": float64(1),
+	}))
+
+	handler = collector.BuildHandler(nil, "0xBCDE", "bonsai-growlab", "kitchen")
+	require.Nil(t, handler.HandleTelemetry("0xBCDE", devcore.JSON{
+		"sensors": map[string]any{
+			"soil-moisture": float64(0.5),
+		},
+	}))
+
+	labels := `{device_id="0xABCD",type="bonsai-growlab",desc="room \"plant\""}`
+
+	require.Equal(t, "# TYPE device_telemetry_enabled gauge\n"+
+		"device_telemetry_enabled"+labels+" 1\n"+
+		"# TYPE device_telemetry_sensors_soil_moisture gauge\n"+
+		"device_telemetry_sensors_soil_moisture"+labels+" 42\n"+
+		"device_telemetry_sensors_soil_moisture"+
+		`{device_id="0xBCDE",type="bonsai-growlab",desc="kitchen"} 0.5`+"\n",
+		formatTestCollector(t, collector, store))
+}
+
+func TestCollectorRemovedDevice(t *testing.T) {
+	store := &testStore{items: []devstore.StoreItem{
+		{ID: "0xABCD", Type: "bonsai-growlab", Desc: "home"},
+	}}
+
+	collector := NewCollector(&testClock{}, CollectorParams{})
+
+	handler := collector.BuildHandler(nil, "0xABCD", "bonsai-growlab", "home")
+	require.Nil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{
+		"temperature": float64(21.5),
+	}))
+	require.Contains(t, formatTestCollector(t, collector, store), "device_telemetry_temperature")
+
+	store.items = nil
+	require.Empty(t, formatTestCollector(t, collector, store))
+	require.Empty(t, collector.devices)
+
+	// Telemetry of the removed device isn't exposed, once the device is added again.
+	store.items = []devstore.StoreItem{{ID: "0xABCD"}}
+	require.Empty(t, formatTestCollector(t, collector, store))
+}
+
+func TestCollectorStaleTelemetry(t *testing.T) {
+	store := &testStore{items: []devstore.StoreItem{
+		{ID: "0xABCD", Type: "bonsai-growlab", Desc: "home"},
+	}}
+	clock := &testClock{now: time.Unix(100, 0)}
+
+	collector := NewCollector(clock, CollectorParams{
+		StaleInterval: time.Minute,
+	})
+
+	handler := collector.BuildHandler(nil, "0xABCD", "bonsai-growlab", "home")
+	require.Nil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{
+		"temperature": float64(21.5),
+	}))
+
+	clock.now = clock.now.Add(time.Minute)
+	require.Contains(t, formatTestCollector(t, collector, store), "device_telemetry_temperature")
+
+	clock.now = clock.now.Add(time.Second)
+	require.Empty(t, formatTestCollector(t, collector, store))
+}
+
+func TestCollectorNameCollision(t *testing.T) {
+	store := &testStore{items: []devstore.StoreItem{
+		{ID: "0xABCD", Type: "bonsai-growlab", Desc: "home"},
+	}}
+
+	collector := NewCollector(&testClock{}, CollectorParams{})
+
+	handler := collector.BuildHandler(nil, "0xABCD", "bonsai-growlab", "home")
+	require.Nil(t, handler.HandleTelemetry("0xABCD", devcore.JSON{
+		"sensors": map[string]any{
+			"temperature": float64(21.5),
+		},
+		"sensors_temperature": float64(22.5),
+		"sensors-temperature": float64(23.5),
+		"humidity":            float64(40),
+	}))
+
+	// Colliding fields are skipped, since their samples can't be told apart.
+	require.Equal(t, "# TYPE device_telemetry_humidity gauge\n"+
+		`device_telemetry_humidity{device_id="0xABCD",type="bonsai-growlab",desc="home"}`+
+		" 40\n", formatTestCollector(t, collector, store))
+}
@@ -0,0 +1,41 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package stprometheus
+
+import (
+	"io"
+
+	"github.com/tendry-lab/device-hub/components/device/devstore"
+)
+
+// MetricsSource exposes the latest telemetry of the added devices.
+//
+// Remarks:
+//   - Only the devices in the store are exposed, the telemetry of the removed
+//     devices is dropped.
+type MetricsSource struct {
+	collector *Collector
+	store     devstore.Store
+}
+
+// NewMetricsSource is an initialization of MetricsSource.
+//
+// Parameters:
+//   - collector to get the latest device telemetry.
+//   - store to get the description of the added devices.
+func NewMetricsSource(collector *Collector, store devstore.Store) *MetricsSource {
+	return &MetricsSource{
+		collector: collector,
+		store:     store,
+	}
+}
+
+// WriteMetrics writes the latest device telemetry in the OpenMetrics format.
+func (s *MetricsSource) WriteMetrics(w io.Writer) error {
+	_, err := w.Write(s.collector.format(s.store.GetDesc()))
+
+	return err
+}
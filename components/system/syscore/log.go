@@ -42,3 +42,14 @@ func LogCrash(err any) {
 
 	LogErr.Printf("crash: %#v, %s", err, trace[:traceSize])
 }
+
+// LogStall logs the stalled operation with the stack traces of all goroutines.
+//
+// Remarks:
+//   - Stack traces are truncated if they don't fit into 1MB.
+func LogStall(msg string) {
+	trace := make([]byte, 1024*1024)
+	traceSize := runtime.Stack(trace, true)
+
+	LogErr.Printf("stall: %s, %s", msg, trace[:traceSize])
+}
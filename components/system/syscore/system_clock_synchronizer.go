@@ -7,6 +7,7 @@ package syscore
 
 import (
 	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
 )
 
 // SystemClockSynchronizer synchronizes the UNIX time between local and remote resources.
@@ -14,6 +15,9 @@ type SystemClockSynchronizer struct {
 	local      SystemClock
 	remoteLast SystemClock
 	remoteCurr SystemClock
+
+	attempts *sysmetrics.Counter
+	failures *sysmetrics.Counter
 }
 
 // NewSystemClockSynchronizer initializes the component for the UNIX time synchronization.
@@ -34,8 +38,32 @@ func NewSystemClockSynchronizer(
 	}
 }
 
+// SetMetrics sets the registry to report the synchronization attempts.
+//
+// Remarks:
+//   - Metrics aren't reported if not set.
+//   - Should be called before SyncTime().
+func (s *SystemClockSynchronizer) SetMetrics(registry *sysmetrics.Registry) {
+	s.attempts = registry.Counter("device_hub_clock_sync_attempts",
+		"Number of the device time synchronization attempts.")
+	s.failures = registry.Counter("device_hub_clock_sync_failures",
+		"Number of the failed device time synchronization attempts.")
+}
+
 // SyncTime synchronizes the UNIX time between local and remote resources.
 func (s *SystemClockSynchronizer) SyncTime() error {
+	s.attempts.Inc()
+
+	if err := s.syncTime(); err != nil {
+		s.failures.Inc()
+
+		return err
+	}
+
+	return nil
+}
+
+func (s *SystemClockSynchronizer) syncTime() error {
 	localTs, err := s.local.GetTimestamp()
 	if err != nil {
 		return err
@@ -12,6 +12,7 @@ import (
 	"github.com/tendry-lab/zeroconf"
 
 	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
 )
 
 // ZeroconfBrowserParams represents various options for zeroconf mDNS browser.
@@ -43,6 +44,7 @@ type ZeroconfBrowser struct {
 	params  ZeroconfBrowserParams
 	ctx     context.Context
 	handler ServiceHandler
+	entries *sysmetrics.Counter
 }
 
 // NewZeroconfBrowser is an initialization of ZeroconfBrowser.
@@ -58,6 +60,17 @@ func NewZeroconfBrowser(
 	}
 }
 
+// SetMetrics sets the registry to report the browsed entries.
+//
+// Remarks:
+//   - Metrics aren't reported if not set.
+//   - Should be called before Run().
+func (b *ZeroconfBrowser) SetMetrics(registry *sysmetrics.Registry) {
+	b.entries = registry.Counter("device_hub_mdns_browse_entries",
+		"Number of the mDNS entries seen while browsing.",
+		sysmetrics.Label{Name: "service", Value: b.params.Service})
+}
+
 // Run executes a single mDNS lookup operation.
 func (b *ZeroconfBrowser) Run() error {
 	resolver, err := zeroconf.NewResolver(b.params.Opts...)
@@ -97,6 +110,8 @@ func (b *ZeroconfBrowser) HandleError(err error) {
 }
 
 func (b *ZeroconfBrowser) handleEntry(entry *zeroconf.ServiceEntry) {
+	b.entries.Inc()
+
 	service := &Service{
 		Instance:   entry.Instance,
 		Name:       entry.Service,
@@ -0,0 +1,67 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sysmetrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const contentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Source writes the metric families in the OpenMetrics format.
+type Source interface {
+	// WriteMetrics writes the metric families, without the "# EOF" marker.
+	WriteMetrics(w io.Writer) error
+}
+
+// HTTPHandler exposes the metrics over HTTP API in the OpenMetrics format.
+type HTTPHandler struct {
+	sources []Source
+}
+
+// Add adds the metrics source.
+//
+// Remarks:
+//   - Metric names should be unique across all sources.
+//   - Should be called before the metrics are served.
+func (h *HTTPHandler) Add(source Source) {
+	h.sources = append(h.sources, source)
+}
+
+// HandleMetrics returns the metrics of all sources.
+func (h *HTTPHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "error: unsupported method", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	var buf bytes.Buffer
+
+	for _, source := range h.sources {
+		if err := source.WriteMetrics(&buf); err != nil {
+			http.Error(w, fmt.Sprintf("error: failed to format metrics: %v", err),
+				http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	buf.WriteString("# EOF\n")
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sysmetrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	value atomic.Uint64
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by the provided value.
+func (c *Counter) Add(value uint64) {
+	if c == nil {
+		return
+	}
+
+	c.value.Add(value)
+}
+
+// Get returns the counter value.
+func (c *Counter) Get() uint64 {
+	if c == nil {
+		return 0
+	}
+
+	return c.value.Load()
+}
+
+func (c *Counter) write(w io.Writer, name string, labels string) error {
+	_, err := fmt.Fprintf(w, "%s_total%s %d\n", name, labels, c.Get())
+
+	return err
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set sets the gauge value.
+func (g *Gauge) Set(value float64) {
+	if g == nil {
+		return
+	}
+
+	g.bits.Store(math.Float64bits(value))
+}
+
+// Get returns the gauge value.
+func (g *Gauge) Get() float64 {
+	if g == nil {
+		return 0
+	}
+
+	return math.Float64frombits(g.bits.Load())
+}
+
+func (g *Gauge) write(w io.Writer, name string, labels string) error {
+	_, err := fmt.Fprintf(w, "%s%s %s\n", name, labels, formatFloat(g.Get()))
+
+	return err
+}
+
+// Summary tracks the number and the sum of the observed values.
+type Summary struct {
+	mu    sync.Mutex
+	count uint64
+	sum   float64
+}
+
+// Observe adds the value to the summary.
+func (s *Summary) Observe(value float64) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	s.sum += value
+}
+
+// ObserveDuration adds the duration in seconds to the summary.
+func (s *Summary) ObserveDuration(d time.Duration) {
+	s.Observe(d.Seconds())
+}
+
+// Get returns the number and the sum of the observed values.
+func (s *Summary) Get() (uint64, float64) {
+	if s == nil {
+		return 0, 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.count, s.sum
+}
+
+func (s *Summary) write(w io.Writer, name string, labels string) error {
+	count, sum := s.Get()
+
+	_, err := fmt.Fprintf(w, "%s_count%s %d\n%s_sum%s %s\n",
+		name, labels, count, name, labels, formatFloat(sum))
+
+	return err
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
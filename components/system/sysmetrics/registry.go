@@ -0,0 +1,208 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sysmetrics
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Label is a metric label.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Registry holds the metrics and exposes them in the OpenMetrics format.
+//
+// Remarks:
+//   - Metric with the same name and labels is created only once, and returned
+//     on each next call.
+//   - All methods can be called on the nil registry, the returned metrics are nil,
+//     and updating the nil metric is a no-op. So the metrics can be disabled by
+//     passing the nil registry.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+type metricType string
+
+const (
+	metricTypeCounter metricType = "counter"
+	metricTypeGauge   metricType = "gauge"
+	metricTypeSummary metricType = "summary"
+)
+
+type metric interface {
+	write(w io.Writer, name string, labels string) error
+}
+
+type family struct {
+	name   string
+	help   string
+	typ    metricType
+	series map[string]metric
+}
+
+// NewRegistry is an initialization of Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]*family),
+	}
+}
+
+// Counter returns the counter metric.
+//
+// Parameters:
+//   - name - metric name, without the "_total" suffix.
+//   - help - metric description.
+//   - labels - metric labels.
+func (r *Registry) Counter(name string, help string, labels ...Label) *Counter {
+	if r == nil {
+		return nil
+	}
+
+	return r.getMetric(name, help, metricTypeCounter, labels, func() metric {
+		return &Counter{}
+	}).(*Counter)
+}
+
+// Gauge returns the gauge metric.
+//
+// Parameters:
+//   - name - metric name.
+//   - help - metric description.
+//   - labels - metric labels.
+func (r *Registry) Gauge(name string, help string, labels ...Label) *Gauge {
+	if r == nil {
+		return nil
+	}
+
+	return r.getMetric(name, help, metricTypeGauge, labels, func() metric {
+		return &Gauge{}
+	}).(*Gauge)
+}
+
+// Summary returns the summary metric, which tracks the number and the sum of
+// the observed values, e.g. durations.
+//
+// Parameters:
+//   - name - metric name, without the "_count" and "_sum" suffixes.
+//   - help - metric description.
+//   - labels - metric labels.
+func (r *Registry) Summary(name string, help string, labels ...Label) *Summary {
+	if r == nil {
+		return nil
+	}
+
+	return r.getMetric(name, help, metricTypeSummary, labels, func() metric {
+		return &Summary{}
+	}).(*Summary)
+}
+
+// WriteMetrics writes all metrics in the OpenMetrics format.
+//
+// Remarks:
+//   - The "# EOF" marker isn't written.
+func (r *Registry) WriteMetrics(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		f := r.families[name]
+
+		if _, err := fmt.Fprintf(w, "# TYPE %s %s\n# HELP %s %s\n",
+			f.name, f.typ, f.name, escapeHelp(f.help)); err != nil {
+			return err
+		}
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			if err := f.series[key].write(w, f.name, key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) getMetric(
+	name string,
+	help string,
+	typ metricType,
+	labels []Label,
+	makeMetric func() metric,
+) metric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{
+			name:   name,
+			help:   help,
+			typ:    typ,
+			series: make(map[string]metric),
+		}
+		r.families[name] = f
+	}
+
+	if f.typ != typ {
+		panic(fmt.Sprintf("sysmetrics: metric type mismatch: name=%s want=%s got=%s",
+			name, f.typ, typ))
+	}
+
+	key := formatLabels(labels)
+
+	m, ok := f.series[key]
+	if !ok {
+		m = makeMetric()
+		f.series[key] = m
+	}
+
+	return m
+}
+
+func formatLabels(labels []Label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(labels))
+	for _, label := range labels {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, label.Name, escapeLabel(label.Value)))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
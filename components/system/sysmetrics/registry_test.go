@@ -0,0 +1,119 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package sysmetrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWriteMetrics(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.Counter("foo_runs", "Number of runs.", Label{"task", "b"}).Add(2)
+	registry.Counter("foo_runs", "Number of runs.", Label{"task", "a"}).Inc()
+	registry.Gauge("bar_entries", "Number of entries.").Set(1.5)
+	registry.Summary("baz_duration_seconds", "Run duration.").
+		ObserveDuration(time.Millisecond * 500)
+
+	var buf bytes.Buffer
+	require.Nil(t, registry.WriteMetrics(&buf))
+
+	require.Equal(t, "# TYPE bar_entries gauge\n"+
+		"# HELP bar_entries Number of entries.\n"+
+		"bar_entries 1.5\n"+
+		"# TYPE baz_duration_seconds summary\n"+
+		"# HELP baz_duration_seconds Run duration.\n"+
+		"baz_duration_seconds_count 1\n"+
+		"baz_duration_seconds_sum 0.5\n"+
+		"# TYPE foo_runs counter\n"+
+		"# HELP foo_runs Number of runs.\n"+
+		"foo_runs_total{task=\"a\"} 1\n"+
+		"foo_runs_total{task=\"b\"} 2\n",
+		buf.String())
+}
+
+func TestRegistrySameMetric(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.Counter("foo", "", Label{"kind", "a"})
+	counter.Inc()
+
+	require.Equal(t, counter, registry.Counter("foo", "", Label{"kind", "a"}))
+	require.Equal(t, uint64(1), registry.Counter("foo", "", Label{"kind", "a"}).Get())
+	require.Equal(t, uint64(0), registry.Counter("foo", "", Label{"kind", "b"}).Get())
+}
+
+func TestRegistryEscapeLabel(t *testing.T) {
+	registry := NewRegistry()
+	registry.Gauge("foo", "", Label{"desc", "a \"b\"\n"}).Set(1)
+
+	var buf bytes.Buffer
+	require.Nil(t, registry.WriteMetrics(&buf))
+
+	require.Equal(t, "# TYPE foo gauge\n# HELP foo \nfoo{desc=\"a \\\"b\\\"\\n\"} 1\n",
+		buf.String())
+}
+
+func TestRegistryTypeMismatch(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("foo", "")
+
+	require.Panics(t, func() {
+		registry.Gauge("foo", "")
+	})
+}
+
+func TestRegistryNil(t *testing.T) {
+	var registry *Registry
+
+	counter := registry.Counter("foo", "")
+	require.Nil(t, counter)
+	counter.Inc()
+	require.Equal(t, uint64(0), counter.Get())
+
+	gauge := registry.Gauge("bar", "")
+	require.Nil(t, gauge)
+	gauge.Set(1)
+	require.Equal(t, float64(0), gauge.Get())
+
+	summary := registry.Summary("baz", "")
+	require.Nil(t, summary)
+	summary.Observe(1)
+	count, sum := summary.Get()
+	require.Equal(t, uint64(0), count)
+	require.Equal(t, float64(0), sum)
+
+	var buf bytes.Buffer
+	require.Nil(t, registry.WriteMetrics(&buf))
+	require.Empty(t, buf.String())
+}
+
+func TestHTTPHandlerMetrics(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("foo", "Foo.").Inc()
+
+	handler := &HTTPHandler{}
+	handler.Add(registry)
+
+	recorder := httptest.NewRecorder()
+	handler.HandleMetrics(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+	require.Equal(t, "# TYPE foo counter\n# HELP foo Foo.\nfoo_total 1\n# EOF\n",
+		recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	handler.HandleMetrics(recorder, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+
+	require.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -12,14 +12,19 @@ import (
 
 	"github.com/tendry-lab/device-hub/components/status"
 	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
 )
 
 // ResolveStore caches the result of hostname resolving.
 type ResolveStore struct {
 	updateCh chan struct{}
 
+	hits     *sysmetrics.Counter
+	misses   *sysmetrics.Counter
+	timeouts *sysmetrics.Counter
+
 	mu            sync.Mutex
-	knownHosts    map[string]struct{}
+	knownHosts    map[string]int
 	resolvedAddrs map[string]net.Addr
 }
 
@@ -27,11 +32,25 @@ type ResolveStore struct {
 func NewResolveStore() *ResolveStore {
 	return &ResolveStore{
 		updateCh:      make(chan struct{}, 1),
-		knownHosts:    make(map[string]struct{}),
+		knownHosts:    make(map[string]int),
 		resolvedAddrs: make(map[string]net.Addr),
 	}
 }
 
+// SetMetrics sets the registry to report the resolving results.
+//
+// Remarks:
+//   - Metrics aren't reported if not set.
+//   - Should be called before Resolve().
+func (s *ResolveStore) SetMetrics(registry *sysmetrics.Registry) {
+	s.hits = registry.Counter("device_hub_resolve_hits",
+		"Number of the hostnames resolved from the cache.")
+	s.misses = registry.Counter("device_hub_resolve_misses",
+		"Number of the hostnames not found in the cache.")
+	s.timeouts = registry.Counter("device_hub_resolve_timeouts",
+		"Number of the hostnames not resolved in time.")
+}
+
 // HandleResolve caches known resolved addresses.
 //
 // Remarks:
@@ -67,18 +86,31 @@ func (s *ResolveStore) HandleResolve(hostname string, addr net.Addr) {
 //   - Resolving an unknown hostname will always fail.
 func (s *ResolveStore) Resolve(ctx context.Context, hostname string) (net.Addr, error) {
 	if addr, err := s.getAddr(hostname); err == nil {
+		s.hits.Inc()
+
 		return addr, nil
 	}
 
-	return s.waitAddr(ctx, hostname)
+	s.misses.Inc()
+
+	addr, err := s.waitAddr(ctx, hostname)
+	if err == status.StatusTimeout {
+		s.timeouts.Inc()
+	}
+
+	return addr, err
 }
 
 // Add adds hostname to the list of known hosts.
+//
+// Remarks:
+//   - Hostname can be added multiple times, it's known until it's removed
+//     the same number of times.
 func (s *ResolveStore) Add(hostname string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.knownHosts[hostname] = struct{}{}
+	s.knownHosts[hostname]++
 }
 
 // Remove removes hostname from the list of known hosts.
@@ -86,8 +118,21 @@ func (s *ResolveStore) Remove(hostname string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.knownHosts, hostname)
-	delete(s.resolvedAddrs, hostname)
+	s.knownHosts[hostname]--
+
+	if s.knownHosts[hostname] <= 0 {
+		delete(s.knownHosts, hostname)
+		delete(s.resolvedAddrs, hostname)
+	}
+}
+
+// Lookup returns the resolved address of the hostname, without waiting for it
+// to be resolved.
+//
+// Remarks:
+//   - status.StatusNoData is returned if the hostname isn't resolved yet.
+func (s *ResolveStore) Lookup(hostname string) (net.Addr, error) {
+	return s.getAddr(hostname)
 }
 
 func (s *ResolveStore) getAddr(hostname string) (net.Addr, error) {
@@ -125,3 +125,26 @@ func TestResolveStoreResolveAfterRemove(t *testing.T) {
 	require.Equal(t, status.StatusNoData, err)
 	require.Nil(t, addr)
 }
+
+func TestResolveStoreAddTwiceRemoveOnce(t *testing.T) {
+	store := NewResolveStore()
+
+	mdnsHostName := "foo.bar.local"
+	netAddr := net.IPAddr{IP: net.IPv4(192, 168, 4, 2)}
+
+	store.Add(mdnsHostName)
+	store.Add(mdnsHostName)
+	store.HandleResolve(mdnsHostName, &netAddr)
+
+	store.Remove(mdnsHostName)
+
+	addr, err := store.Lookup(mdnsHostName)
+	require.Nil(t, err)
+	require.Equal(t, netAddr.String(), addr.String())
+
+	store.Remove(mdnsHostName)
+
+	addr, err = store.Lookup(mdnsHostName)
+	require.Equal(t, status.StatusNoData, err)
+	require.Nil(t, addr)
+}
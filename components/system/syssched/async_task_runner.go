@@ -7,7 +7,13 @@ package syssched
 
 import (
 	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
 )
 
 // AsyncTaskRunnerParams represents various configuration options for AsyncTaskRunner.
@@ -20,16 +26,45 @@ type AsyncTaskRunnerParams struct {
 
 	// DisableRecoverOnPanic is used to disable automatic panic-recovery mechanism.
 	DisableRecoverOnPanic bool
+
+	// MaxBackoffInterval - maximum interval between the failed runs.
+	//
+	// Remarks:
+	//  - Interval is doubled after each consecutive failure, and is reset to
+	//    UpdateInterval after the successful run.
+	//  - Interval isn't changed on failures if zero.
+	MaxBackoffInterval time.Duration
+
+	// StartJitter - maximum random delay before the first run, no delay if zero.
+	//
+	// Remarks:
+	//  - Allows to spread the runs of the tasks started at the same time.
+	StartJitter time.Duration
+
+	// Jitter - maximum random delay added to each interval, no delay if zero.
+	Jitter time.Duration
+
+	// BreakerThreshold - number of consecutive failures to open the circuit breaker.
+	//
+	// Remarks:
+	//  - Circuit breaker is disabled if zero.
+	//  - Task is run once per BreakerInterval while the breaker is open, errors
+	//    aren't reported until the breaker is closed by the successful run.
+	//  - Awake() closes the breaker and runs the task immediately.
+	BreakerThreshold int
+
+	// BreakerInterval - how often to run the task while the circuit breaker is open,
+	// UpdateInterval is used if it's less.
+	BreakerInterval time.Duration
 }
 
 // AsyncTaskRunner periodically runs task in the standalone goroutine.
 type AsyncTaskRunner struct {
-	ctx     context.Context
-	doneCh  chan struct{}
-	awakeCh chan struct{}
-	task    Task
-	handler ErrorHandler
-	params  AsyncTaskRunnerParams
+	ctx      context.Context
+	doneCh   chan struct{}
+	awakeCh  chan struct{}
+	executor *taskExecutor
+	started  atomic.Bool
 }
 
 // NewAsyncTaskRunner is an initialization of AsyncTaskRunner.
@@ -39,39 +74,59 @@ func NewAsyncTaskRunner(
 	handler ErrorHandler,
 	params AsyncTaskRunnerParams,
 ) *AsyncTaskRunner {
-	var runnerTask Task
-
-	if params.DisableRecoverOnPanic {
-		runnerTask = task
-	} else {
-		runnerTask = NewCrashTask(task)
-	}
-
 	return &AsyncTaskRunner{
-		ctx:     ctx,
-		doneCh:  make(chan struct{}),
-		awakeCh: make(chan struct{}, 1),
-		task:    runnerTask,
-		handler: handler,
-		params:  params,
+		ctx:      ctx,
+		doneCh:   make(chan struct{}),
+		awakeCh:  make(chan struct{}, 1),
+		executor: newTaskExecutor(task, handler, params),
 	}
 }
 
+// SetMetrics sets the registry to report the task runs.
+//
+// Parameters:
+//   - registry to report the metrics, metrics aren't reported if nil.
+//   - task - task name, used as the metrics label.
+//
+// Remarks:
+//   - Should be called before Start().
+func (r *AsyncTaskRunner) SetMetrics(registry *sysmetrics.Registry, task string) {
+	r.executor.setMetrics(registry, task)
+}
+
 // Start begins asynchronous task processing.
+//
+// Remarks:
+//   - Subsequent calls are no-op.
 func (r *AsyncTaskRunner) Start() error {
+	if !r.started.CompareAndSwap(false, true) {
+		return nil
+	}
+
 	go r.run()
 
 	return nil
 }
 
 // Stop ends asynchronous task processing.
+//
+// Remarks:
+//   - Context should be canceled before calling Stop().
+//   - Returns immediately if the runner isn't started.
 func (r *AsyncTaskRunner) Stop() error {
+	if !r.started.Load() {
+		return nil
+	}
+
 	<-r.doneCh
 
 	return nil
 }
 
 // Awake wakes up the underlying goroutine.
+//
+// Remarks:
+//   - Backoff interval and circuit breaker are reset.
 func (r *AsyncTaskRunner) Awake() {
 	select {
 	case r.awakeCh <- struct{}{}:
@@ -82,39 +137,162 @@ func (r *AsyncTaskRunner) Awake() {
 func (r *AsyncTaskRunner) run() {
 	defer close(r.doneCh)
 
-	ticker := time.NewTicker(r.params.UpdateInterval)
-	defer ticker.Stop()
-
-	if r.runTask() {
-		return
-	}
+	timer := time.NewTimer(r.executor.startDelay())
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			if r.runTask() {
-				return
-			}
+		case <-timer.C:
 
 		case <-r.awakeCh:
-			if r.runTask() {
-				return
-			}
+			r.executor.reset()
 
 		case <-r.ctx.Done():
 			return
 		}
+
+		start := time.Now()
+
+		if r.executor.run() {
+			return
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(time.Until(start.Add(r.executor.nextInterval())))
 	}
 }
 
-func (r *AsyncTaskRunner) runTask() bool {
-	if err := r.task.Run(); err != nil {
-		if r.handler != nil {
-			r.handler.HandleError(err)
+// taskExecutor runs the task, reports the result, and calculates the interval
+// before the next run.
+type taskExecutor struct {
+	task    Task
+	handler ErrorHandler
+	params  AsyncTaskRunnerParams
+
+	runs         *sysmetrics.Counter
+	errors       *sysmetrics.Counter
+	duration     *sysmetrics.Summary
+	breakerOpens *sysmetrics.Counter
+
+	mu       sync.Mutex
+	failures int
+}
+
+func newTaskExecutor(
+	task Task,
+	handler ErrorHandler,
+	params AsyncTaskRunnerParams,
+) *taskExecutor {
+	if !params.DisableRecoverOnPanic {
+		task = NewCrashTask(task)
+	}
+
+	return &taskExecutor{
+		task:    task,
+		handler: handler,
+		params:  params,
+	}
+}
+
+func (e *taskExecutor) setMetrics(registry *sysmetrics.Registry, task string) {
+	label := sysmetrics.Label{Name: "task", Value: task}
+
+	e.runs = registry.Counter("device_hub_task_runs",
+		"Number of the task runs.", label)
+	e.errors = registry.Counter("device_hub_task_errors",
+		"Number of the failed task runs.", label)
+	e.duration = registry.Summary("device_hub_task_run_duration_seconds",
+		"Duration of the task runs.", label)
+	e.breakerOpens = registry.Counter("device_hub_task_breaker_opens",
+		"Number of times the task circuit breaker was opened.", label)
+}
+
+// run runs the task once, and returns true if the task shouldn't be run anymore.
+func (e *taskExecutor) run() bool {
+	start := time.Now()
+	err := e.task.Run()
+
+	e.runs.Inc()
+	e.duration.ObserveDuration(time.Since(start))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err != nil {
+		e.errors.Inc()
+		e.failures++
+
+		if e.breakerOpen() {
+			if e.failures != e.params.BreakerThreshold {
+				return false
+			}
+
+			e.breakerOpens.Inc()
+
+			err = fmt.Errorf("circuit breaker is open: failures=%d: %w", e.failures, err)
+		}
+
+		if e.handler != nil {
+			e.handler.HandleError(err)
 		}
 
 		return false
 	}
 
-	return r.params.ExitOnSuccess
+	e.failures = 0
+
+	return e.params.ExitOnSuccess
+}
+
+// reset closes the circuit breaker, and resets the backoff interval.
+func (e *taskExecutor) reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.failures = 0
+}
+
+// startDelay returns the delay before the first run.
+func (e *taskExecutor) startDelay() time.Duration {
+	return jitter(e.params.StartJitter)
+}
+
+// nextInterval returns the interval between the last and the next run.
+func (e *taskExecutor) nextInterval() time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	interval := e.params.UpdateInterval
+
+	switch {
+	case e.breakerOpen():
+		interval = max(interval, e.params.BreakerInterval)
+
+	case e.failures > 0 && e.params.MaxBackoffInterval > interval:
+		for n := 0; n < e.failures && interval < e.params.MaxBackoffInterval; n++ {
+			interval *= 2
+		}
+
+		interval = min(interval, e.params.MaxBackoffInterval)
+	}
+
+	return interval + jitter(e.params.Jitter)
+}
+
+func (e *taskExecutor) breakerOpen() bool {
+	return e.params.BreakerThreshold > 0 && e.failures >= e.params.BreakerThreshold
+}
+
+// jitter returns the random duration in [0, max).
+func jitter(maxDuration time.Duration) time.Duration {
+	if maxDuration <= 0 {
+		return 0
+	}
+
+	return rand.N(maxDuration)
 }
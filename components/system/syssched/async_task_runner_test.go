@@ -7,6 +7,7 @@ package syssched
 
 import (
 	"context"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -67,3 +68,111 @@ func TestAsyncTaskRunnerExitOnSuccess(t *testing.T) {
 	task.setError(nil)
 	require.Nil(t, runner.Stop())
 }
+
+type testAsyncTaskRunnerErrorHandler struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+func (h *testAsyncTaskRunnerErrorHandler) HandleError(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.errors = append(h.errors, err)
+}
+
+func (h *testAsyncTaskRunnerErrorHandler) getErrors() []error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return slices.Clone(h.errors)
+}
+
+func TestAsyncTaskRunnerBackoff(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{err: status.StatusError}
+
+	executor := newTaskExecutor(task, nil, AsyncTaskRunnerParams{
+		UpdateInterval:     time.Second,
+		MaxBackoffInterval: time.Second * 5,
+	})
+
+	for _, interval := range []time.Duration{
+		time.Second * 2,
+		time.Second * 4,
+		time.Second * 5,
+		time.Second * 5,
+	} {
+		require.False(t, executor.run())
+		require.Equal(t, interval, executor.nextInterval())
+	}
+
+	// Interval is reset after the successful run.
+	task.setError(nil)
+	require.False(t, executor.run())
+	require.Equal(t, time.Second, executor.nextInterval())
+}
+
+func TestAsyncTaskRunnerJitter(t *testing.T) {
+	executor := newTaskExecutor(&testAsyncTaskRunnerTestTask{}, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Second,
+		StartJitter:    time.Second * 3,
+		Jitter:         time.Millisecond * 100,
+	})
+
+	for n := 0; n < 100; n++ {
+		delay := executor.startDelay()
+		require.GreaterOrEqual(t, delay, time.Duration(0))
+		require.Less(t, delay, time.Second*3)
+
+		interval := executor.nextInterval()
+		require.GreaterOrEqual(t, interval, time.Second)
+		require.Less(t, interval, time.Second+time.Millisecond*100)
+	}
+}
+
+func TestAsyncTaskRunnerBreaker(t *testing.T) {
+	task := &testAsyncTaskRunnerTestTask{err: status.StatusError}
+	handler := &testAsyncTaskRunnerErrorHandler{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner := NewAsyncTaskRunner(ctx, task, handler, AsyncTaskRunnerParams{
+		UpdateInterval:   time.Millisecond * 10,
+		BreakerThreshold: 3,
+		BreakerInterval:  time.Hour,
+	})
+	require.Nil(t, runner.Start())
+
+	require.Eventually(t, func() bool {
+		return task.getCallCount() == 3
+	}, time.Second, time.Millisecond*10)
+
+	// Task isn't run while the breaker is open.
+	time.Sleep(time.Millisecond * 50)
+	require.Equal(t, 3, task.getCallCount())
+
+	errs := handler.getErrors()
+	require.Equal(t, 3, len(errs))
+	require.ErrorIs(t, errs[2], status.StatusError)
+	require.Contains(t, errs[2].Error(), "circuit breaker is open")
+
+	// Breaker is reset once the runner is awakened.
+	task.setError(nil)
+	runner.Awake()
+
+	require.Eventually(t, func() bool {
+		return task.getCallCount() > 3
+	}, time.Second, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, runner.Stop())
+}
+
+func TestAsyncTaskRunnerStopNotStarted(t *testing.T) {
+	runner := NewAsyncTaskRunner(context.Background(), &testAsyncTaskRunnerTestTask{}, nil,
+		AsyncTaskRunnerParams{
+			UpdateInterval: time.Millisecond * 10,
+		})
+	require.Nil(t, runner.Stop())
+}
@@ -5,15 +5,47 @@
 
 package syssched
 
+import (
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
 // FanoutStarter to start all at once.
+//
+// Remarks:
+//   - Already started starters which implement Stopper are stopped in the reverse
+//     order if any starter fails.
 type FanoutStarter struct {
-	starters []Starter
+	cancel      func()
+	stopTimeout time.Duration
+	starters    []Starter
+}
+
+// SetCancel sets the function which is called before the started starters are
+// stopped on rollback, e.g. to cancel the context the starters are running in.
+//
+// Remarks:
+//   - Should be called before Start().
+func (s *FanoutStarter) SetCancel(cancel func()) {
+	s.cancel = cancel
+}
+
+// SetStopTimeout sets how long to wait for a single starter to stop on rollback.
+//
+// Remarks:
+//   - Starters are waited forever if zero.
+//   - Should be called before Start().
+func (s *FanoutStarter) SetStopTimeout(timeout time.Duration) {
+	s.stopTimeout = timeout
 }
 
 // Start starts all the registered starters.
 func (s *FanoutStarter) Start() error {
-	for _, starter := range s.starters {
+	for n, starter := range s.starters {
 		if err := starter.Start(); err != nil {
+			s.rollback(n)
+
 			return err
 		}
 	}
@@ -25,3 +57,21 @@ func (s *FanoutStarter) Start() error {
 func (s *FanoutStarter) Add(starter Starter) {
 	s.starters = append(s.starters, starter)
 }
+
+// rollback stops first count starters in the reverse order.
+func (s *FanoutStarter) rollback(count int) {
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	for n := count - 1; n >= 0; n-- {
+		stopper, ok := s.starters[n].(Stopper)
+		if !ok {
+			continue
+		}
+
+		if err := stopWithTimeout(stopper, s.stopTimeout); err != nil {
+			syscore.LogErr.Printf("failed to roll back: err=%v", err)
+		}
+	}
+}
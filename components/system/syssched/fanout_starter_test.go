@@ -0,0 +1,45 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testFanoutStarter struct {
+	events *testLifecycleEvents
+}
+
+func (s *testFanoutStarter) Start() error {
+	s.events.add("start-starter")
+
+	return nil
+}
+
+func TestFanoutStarterRollback(t *testing.T) {
+	events := &testLifecycleEvents{}
+	startErr := errors.New("failed to start")
+
+	starter := &FanoutStarter{}
+	starter.SetCancel(func() {
+		events.add("cancel")
+	})
+	starter.Add(&testLifecycleComponent{id: "a", events: events})
+	starter.Add(&testFanoutStarter{events: events})
+	starter.Add(&testLifecycleComponent{id: "b", events: events})
+	starter.Add(&testLifecycleComponent{id: "c", events: events, startErr: startErr})
+	starter.Add(&testLifecycleComponent{id: "d", events: events})
+
+	require.ErrorIs(t, starter.Start(), startErr)
+
+	// Started starters which implement Stopper are stopped in the reverse order.
+	require.Equal(t, []string{
+		"start-a", "start-starter", "start-b", "cancel", "stop-b", "stop-a",
+	}, events.get())
+}
@@ -5,11 +5,29 @@
 
 package syssched
 
-import "github.com/tendry-lab/device-hub/components/system/syscore"
+import (
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
 
 // FanoutStopper propagates stop call to the underlying stoppers.
+//
+// Remarks:
+//   - Each stopper is stopped with the timeout, stoppers which fail to stop in
+//     time are logged and left behind, so they don't block the remaining ones.
 type FanoutStopper struct {
-	nodes []node
+	timeout time.Duration
+	nodes   []node
+}
+
+// SetTimeout sets how long to wait for a single stopper to stop.
+//
+// Remarks:
+//   - Stoppers are waited forever if zero.
+//   - Should be called before Stop().
+func (s *FanoutStopper) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
 }
 
 // Add addes stopper with id to be notified when the stop event is happened.
@@ -20,7 +38,7 @@ func (s *FanoutStopper) Add(id string, stopper Stopper) {
 // Stop stops all registered stoppers.
 func (s *FanoutStopper) Stop() error {
 	for _, node := range s.nodes {
-		if err := node.s.Stop(); err != nil {
+		if err := stopWithTimeout(node.s, s.timeout); err != nil {
 			syscore.LogErr.Printf("failed to stop: id=%s err=%v", node.id, err)
 		}
 	}
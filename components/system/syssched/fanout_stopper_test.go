@@ -0,0 +1,35 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanoutStopperTimeout(t *testing.T) {
+	events := &testLifecycleEvents{}
+
+	stopper := &FanoutStopper{}
+	stopper.SetTimeout(time.Millisecond * 20)
+
+	stopper.Add("a", &testLifecycleComponent{id: "a", events: events})
+	stopper.Add("b", &testLifecycleComponent{
+		id:        "b",
+		events:    events,
+		stopDelay: time.Second,
+	})
+	stopper.Add("c", &testLifecycleComponent{id: "c", events: events})
+
+	start := time.Now()
+
+	// Hung stopper doesn't block the remaining ones.
+	require.Nil(t, stopper.Stop())
+	require.Less(t, time.Since(start), time.Millisecond*500)
+	require.Equal(t, []string{"stop-a", "stop-c"}, events.get())
+}
@@ -0,0 +1,248 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/status"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+// LifecycleParams represents various configuration options for Lifecycle.
+type LifecycleParams struct {
+	// StopTimeout - how long to wait for a single component to stop.
+	//
+	// Remarks:
+	//  - Used for the components without their own timeout.
+	//  - Components are waited forever if zero.
+	StopTimeout time.Duration
+
+	// Cancel is called before the started components are rolled back, e.g. to
+	// cancel the context the components are running in.
+	//
+	// Remarks:
+	//  - Optional, the components are rolled back without cancellation if nil.
+	Cancel func()
+}
+
+// LifecycleComponent is a single component managed by Lifecycle.
+type LifecycleComponent struct {
+	// ID - unique component identifier.
+	ID string
+
+	// Starter to start the component, the component doesn't require starting if nil.
+	Starter Starter
+
+	// Stopper to stop the component, the component doesn't require stopping if nil.
+	Stopper Stopper
+
+	// DependsOn - identifiers of the components which should be started before,
+	// and stopped after this component.
+	DependsOn []string
+
+	// StopTimeout - overrides LifecycleParams.StopTimeout if positive.
+	StopTimeout time.Duration
+}
+
+// Lifecycle starts and stops the components in the order of their dependencies.
+//
+// Remarks:
+//   - Components are started in the topological order, independent components are
+//     started in the order they were added.
+//   - Components are stopped in the reverse order.
+//   - Already started components are rolled back if any component fails to start.
+//   - Each component is stopped with the timeout, components which fail to stop
+//     are logged and reported by Stop().
+type Lifecycle struct {
+	params     LifecycleParams
+	components []LifecycleComponent
+
+	mu      sync.Mutex
+	started []LifecycleComponent
+}
+
+// NewLifecycle is an initialization of Lifecycle.
+func NewLifecycle(params LifecycleParams) *Lifecycle {
+	return &Lifecycle{params: params}
+}
+
+// Add adds the component to be started on Start() and stopped on Stop().
+//
+// Remarks:
+//   - Should be called before Start().
+func (l *Lifecycle) Add(component LifecycleComponent) {
+	l.components = append(l.components, component)
+}
+
+// Start starts all components in the order of their dependencies.
+//
+// Remarks:
+//   - status.StatusInvalidArg is returned if the component ID is duplicated, or
+//     the dependency is unknown or cyclic.
+//   - Started components are stopped in the reverse order if any component fails
+//     to start.
+func (l *Lifecycle) Start() error {
+	ordered, err := l.sort()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, c := range ordered {
+		if c.Starter != nil {
+			if err := c.Starter.Start(); err != nil {
+				err = fmt.Errorf("failed to start component: id=%s err=%w", c.ID, err)
+
+				if l.params.Cancel != nil {
+					l.params.Cancel()
+				}
+
+				if stopErr := l.stopLocked(); stopErr != nil {
+					return errors.Join(err, stopErr)
+				}
+
+				return err
+			}
+		}
+
+		l.started = append(l.started, c)
+
+		syscore.LogInf.Printf("component started: id=%s", c.ID)
+	}
+
+	return nil
+}
+
+// Stop stops the started components in the reverse order.
+//
+// Remarks:
+//   - All components are stopped even if some of them fail to stop, the errors
+//     of all failed components are returned.
+func (l *Lifecycle) Stop() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.stopLocked()
+}
+
+func (l *Lifecycle) stopLocked() error {
+	var errs []error
+
+	for i := len(l.started) - 1; i >= 0; i-- {
+		c := l.started[i]
+		if c.Stopper == nil {
+			continue
+		}
+
+		if err := l.stopComponent(c); err != nil {
+			syscore.LogErr.Printf("failed to stop component: id=%s err=%v", c.ID, err)
+
+			errs = append(errs, fmt.Errorf("failed to stop component: id=%s err=%w",
+				c.ID, err))
+
+			continue
+		}
+
+		syscore.LogInf.Printf("component stopped: id=%s", c.ID)
+	}
+
+	l.started = nil
+
+	return errors.Join(errs...)
+}
+
+func (l *Lifecycle) stopComponent(c LifecycleComponent) error {
+	timeout := l.params.StopTimeout
+	if c.StopTimeout > 0 {
+		timeout = c.StopTimeout
+	}
+
+	return stopWithTimeout(c.Stopper, timeout)
+}
+
+// stopWithTimeout stops the stopper, and returns status.StatusTimeout if it isn't
+// stopped in the provided timeout. Stopper is waited forever if timeout is zero.
+func stopWithTimeout(stopper Stopper, timeout time.Duration) error {
+	if timeout <= 0 {
+		return stopper.Stop()
+	}
+
+	// Buffered, the hung component shouldn't block the goroutine forever once
+	// it's finally stopped.
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- stopper.Stop()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-errCh:
+		return err
+
+	case <-timer.C:
+		return fmt.Errorf("%w: component didn't stop in %s", status.StatusTimeout, timeout)
+	}
+}
+
+// sort returns the components in the topological order of their dependencies.
+func (l *Lifecycle) sort() ([]LifecycleComponent, error) {
+	ids := make(map[string]bool, len(l.components))
+
+	for _, c := range l.components {
+		if ids[c.ID] {
+			return nil, fmt.Errorf("%w: duplicated component: id=%s",
+				status.StatusInvalidArg, c.ID)
+		}
+
+		ids[c.ID] = true
+	}
+
+	for _, c := range l.components {
+		for _, dep := range c.DependsOn {
+			if !ids[dep] {
+				return nil, fmt.Errorf("%w: unknown dependency: id=%s dependency=%s",
+					status.StatusInvalidArg, c.ID, dep)
+			}
+		}
+	}
+
+	var ordered []LifecycleComponent
+
+	placed := make(map[string]bool, len(l.components))
+	pending := slices.Clone(l.components)
+
+	for len(pending) != 0 {
+		pos := slices.IndexFunc(pending, func(c LifecycleComponent) bool {
+			for _, dep := range c.DependsOn {
+				if !placed[dep] {
+					return false
+				}
+			}
+
+			return true
+		})
+		if pos < 0 {
+			return nil, fmt.Errorf("%w: cyclic dependency: id=%s",
+				status.StatusInvalidArg, pending[0].ID)
+		}
+
+		placed[pending[pos].ID] = true
+		ordered = append(ordered, pending[pos])
+		pending = slices.Delete(pending, pos, pos+1)
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,237 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+type testLifecycleEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *testLifecycleEvents) add(event string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.events = append(e.events, event)
+}
+
+func (e *testLifecycleEvents) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]string(nil), e.events...)
+}
+
+type testLifecycleComponent struct {
+	id        string
+	events    *testLifecycleEvents
+	startErr  error
+	stopErr   error
+	stopDelay time.Duration
+}
+
+func (c *testLifecycleComponent) Start() error {
+	if c.startErr != nil {
+		return c.startErr
+	}
+
+	c.events.add("start-" + c.id)
+
+	return nil
+}
+
+func (c *testLifecycleComponent) Stop() error {
+	time.Sleep(c.stopDelay)
+
+	c.events.add("stop-" + c.id)
+
+	return c.stopErr
+}
+
+func addTestLifecycleComponent(
+	lifecycle *Lifecycle,
+	c *testLifecycleComponent,
+	deps ...string,
+) {
+	lifecycle.Add(LifecycleComponent{
+		ID:        c.id,
+		Starter:   c,
+		Stopper:   c,
+		DependsOn: deps,
+	})
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	events := &testLifecycleEvents{}
+	lifecycle := NewLifecycle(LifecycleParams{})
+
+	addTestLifecycleComponent(lifecycle,
+		&testLifecycleComponent{id: "server", events: events}, "store", "db")
+	addTestLifecycleComponent(lifecycle,
+		&testLifecycleComponent{id: "store", events: events}, "db")
+	addTestLifecycleComponent(lifecycle,
+		&testLifecycleComponent{id: "db", events: events})
+	addTestLifecycleComponent(lifecycle,
+		&testLifecycleComponent{id: "browser", events: events})
+
+	require.Nil(t, lifecycle.Start())
+	require.Equal(t, []string{
+		"start-db", "start-store", "start-server", "start-browser",
+	}, events.get())
+
+	require.Nil(t, lifecycle.Stop())
+	require.Equal(t, []string{
+		"start-db", "start-store", "start-server", "start-browser",
+		"stop-browser", "stop-server", "stop-store", "stop-db",
+	}, events.get())
+
+	// Components are stopped once.
+	require.Nil(t, lifecycle.Stop())
+	require.Len(t, events.get(), 8)
+}
+
+func TestLifecycleInvalidDependencies(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		deps map[string][]string
+	}{
+		{"unknown", map[string][]string{"a": {"b"}}},
+		{"cyclic", map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"a"}}},
+		{"self", map[string][]string{"a": {"a"}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			events := &testLifecycleEvents{}
+			lifecycle := NewLifecycle(LifecycleParams{})
+
+			for _, id := range []string{"a", "b", "c"} {
+				if deps, ok := test.deps[id]; ok || id == "a" {
+					addTestLifecycleComponent(lifecycle,
+						&testLifecycleComponent{id: id, events: events}, deps...)
+				}
+			}
+
+			require.ErrorIs(t, lifecycle.Start(), status.StatusInvalidArg)
+			require.Empty(t, events.get())
+		})
+	}
+}
+
+func TestLifecycleDuplicatedComponent(t *testing.T) {
+	events := &testLifecycleEvents{}
+	lifecycle := NewLifecycle(LifecycleParams{})
+
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{id: "a", events: events})
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{id: "a", events: events})
+
+	require.ErrorIs(t, lifecycle.Start(), status.StatusInvalidArg)
+}
+
+func TestLifecycleRollback(t *testing.T) {
+	events := &testLifecycleEvents{}
+	canceled := false
+
+	lifecycle := NewLifecycle(LifecycleParams{
+		Cancel: func() {
+			canceled = true
+		},
+	})
+
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{id: "a", events: events})
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{id: "b", events: events})
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{
+		id:       "c",
+		events:   events,
+		startErr: status.StatusError,
+	})
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{id: "d", events: events})
+
+	require.ErrorIs(t, lifecycle.Start(), status.StatusError)
+	require.True(t, canceled)
+	require.Equal(t, []string{"start-a", "start-b", "stop-b", "stop-a"}, events.get())
+
+	// Rolled back components aren't stopped again.
+	require.Nil(t, lifecycle.Stop())
+	require.Len(t, events.get(), 4)
+}
+
+func TestLifecycleStopTimeout(t *testing.T) {
+	events := &testLifecycleEvents{}
+
+	lifecycle := NewLifecycle(LifecycleParams{
+		StopTimeout: time.Millisecond * 50,
+	})
+
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{id: "a", events: events})
+	addTestLifecycleComponent(lifecycle, &testLifecycleComponent{
+		id:        "hung",
+		events:    events,
+		stopDelay: time.Second,
+	})
+	lifecycle.Add(LifecycleComponent{
+		ID: "slow",
+		Starter: &testLifecycleComponent{
+			id:     "slow",
+			events: events,
+		},
+		Stopper: &testLifecycleComponent{
+			id:        "slow",
+			events:    events,
+			stopDelay: time.Millisecond * 100,
+		},
+		StopTimeout: time.Second,
+	})
+
+	require.Nil(t, lifecycle.Start())
+
+	start := time.Now()
+	err := lifecycle.Stop()
+	require.Less(t, time.Since(start), time.Second)
+
+	require.ErrorIs(t, err, status.StatusTimeout)
+	require.ErrorContains(t, err, "id=hung")
+	require.NotContains(t, err.Error(), "id=slow")
+
+	// Components after the hung component are stopped.
+	require.Contains(t, events.get(), "stop-slow")
+	require.Contains(t, events.get(), "stop-a")
+}
+
+func TestLifecycleStopErrors(t *testing.T) {
+	events := &testLifecycleEvents{}
+	lifecycle := NewLifecycle(LifecycleParams{})
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	addTestLifecycleComponent(lifecycle,
+		&testLifecycleComponent{id: "a", events: events, stopErr: errA})
+	addTestLifecycleComponent(lifecycle,
+		&testLifecycleComponent{id: "b", events: events, stopErr: errB})
+	lifecycle.Add(LifecycleComponent{ID: "release-only", Stopper: FuncStopper(func() error {
+		events.add("stop-release-only")
+
+		return nil
+	})})
+
+	require.Nil(t, lifecycle.Start())
+
+	err := lifecycle.Stop()
+	require.ErrorIs(t, err, errA)
+	require.ErrorIs(t, err, errB)
+	require.Equal(t, []string{
+		"start-a", "start-b", "stop-release-only", "stop-b", "stop-a",
+	}, events.get())
+}
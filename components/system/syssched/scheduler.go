@@ -0,0 +1,437 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
+)
+
+// SchedulerParams represents various configuration options for Scheduler.
+type SchedulerParams struct {
+	// Workers - maximum number of tasks run at the same time, should be positive.
+	Workers int
+}
+
+// Scheduler runs periodic tasks on the bounded pool of goroutines.
+//
+// Remarks:
+//   - Tasks are ordered by their next run time in the priority queue, the single
+//     timer is used for all tasks.
+//   - Tasks are delayed if all workers are busy.
+//   - Tasks are submitted with the runners built by NewRunner().
+type Scheduler struct {
+	ctx     context.Context
+	params  SchedulerParams
+	readyCh chan *ScheduledTaskRunner
+	wakeCh  chan struct{}
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	queue   schedulerQueue
+	stopped bool
+
+	tasks *sysmetrics.Gauge
+	delay *sysmetrics.Summary
+}
+
+// NewScheduler is an initialization of Scheduler.
+//
+// Parameters:
+//   - ctx - parent context, scheduler is stopped once it's canceled.
+//   - params - various scheduling configuration options.
+func NewScheduler(ctx context.Context, params SchedulerParams) *Scheduler {
+	return &Scheduler{
+		ctx:     ctx,
+		params:  params,
+		readyCh: make(chan *ScheduledTaskRunner),
+		wakeCh:  make(chan struct{}, 1),
+	}
+}
+
+// SetMetrics sets the registry to report the scheduling metrics.
+//
+// Remarks:
+//   - Metrics aren't reported if the registry is nil.
+//   - Should be called before Start().
+func (s *Scheduler) SetMetrics(registry *sysmetrics.Registry) {
+	s.tasks = registry.Gauge("device_hub_scheduler_tasks",
+		"Number of the tasks waiting for the next run.")
+	s.delay = registry.Summary("device_hub_scheduler_delay_seconds",
+		"Delay between the scheduled and the actual start of the task run.")
+}
+
+// NewRunner returns the runner to run the task periodically on the scheduler.
+//
+// Parameters:
+//   - ctx - runner context, the task isn't run once it's canceled.
+//   - task to run.
+//   - handler to handle the task errors, errors are ignored if nil.
+//   - params - the same options as for AsyncTaskRunner.
+//
+// Remarks:
+//   - Runner context should be derived from the scheduler context.
+func (s *Scheduler) NewRunner(
+	ctx context.Context,
+	task Task,
+	handler ErrorHandler,
+	params AsyncTaskRunnerParams,
+) *ScheduledTaskRunner {
+	return &ScheduledTaskRunner{
+		ctx:       ctx,
+		scheduler: s,
+		executor:  newTaskExecutor(task, handler, params),
+		doneCh:    make(chan struct{}),
+		index:     -1,
+	}
+}
+
+// Start starts the workers.
+func (s *Scheduler) Start() error {
+	s.wg.Add(s.params.Workers + 1)
+
+	for n := 0; n < s.params.Workers; n++ {
+		go s.runWorker()
+	}
+
+	go s.runDispatcher()
+
+	return nil
+}
+
+// Stop waits for the workers to finish the running tasks.
+//
+// Remarks:
+//   - Parent context should be canceled before calling Stop().
+func (s *Scheduler) Stop() error {
+	s.wg.Wait()
+
+	return nil
+}
+
+func (s *Scheduler) runWorker() {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case r := <-s.readyCh:
+			r.runOnce()
+
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) runDispatcher() {
+	defer s.wg.Done()
+	defer s.stop()
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		ready, next := s.takeReady(time.Now())
+
+		for n, r := range ready {
+			select {
+			case s.readyCh <- r:
+			case <-s.ctx.Done():
+				for _, r := range ready[n:] {
+					r.finish()
+				}
+
+				return
+			}
+		}
+
+		if len(ready) != 0 {
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(next)
+
+		select {
+		case <-timer.C:
+		case <-s.wakeCh:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// takeReady returns the runners which should be run now, and how long to wait
+// for the next runner.
+func (s *Scheduler) takeReady(now time.Time) ([]*ScheduledTaskRunner, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ready []*ScheduledTaskRunner
+
+	for len(s.queue) != 0 {
+		r := s.queue[0]
+		if r.runAt.After(now) {
+			break
+		}
+
+		heap.Pop(&s.queue)
+
+		r.running = true
+		s.delay.ObserveDuration(now.Sub(r.runAt))
+
+		ready = append(ready, r)
+	}
+
+	s.tasks.Set(float64(len(s.queue)))
+
+	if len(s.queue) == 0 {
+		return ready, time.Hour
+	}
+
+	return ready, s.queue[0].runAt.Sub(now)
+}
+
+// schedule adds the runner to the queue, or changes its next run time.
+//
+// Remarks:
+//   - Should be called with the mutex locked.
+func (s *Scheduler) schedule(r *ScheduledTaskRunner, runAt time.Time) {
+	r.runAt = runAt
+
+	if r.index >= 0 {
+		heap.Fix(&s.queue, r.index)
+	} else {
+		heap.Push(&s.queue, r)
+	}
+
+	if r.index == 0 {
+		select {
+		case s.wakeCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// stop finishes the queued runners, since they won't be run anymore.
+func (s *Scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+
+	for _, r := range s.queue {
+		r.index = -1
+		r.finishLocked()
+	}
+
+	s.queue = nil
+}
+
+// ScheduledTaskRunner periodically runs task on the Scheduler.
+//
+// Remarks:
+//   - Has the same semantics as AsyncTaskRunner: the task is run once the runner
+//     is started, then with the configured interval, or once the runner is awakened.
+type ScheduledTaskRunner struct {
+	ctx       context.Context
+	scheduler *Scheduler
+	executor  *taskExecutor
+	doneCh    chan struct{}
+	stopFunc  func() bool
+
+	// Protected by the scheduler mutex.
+	index   int
+	runAt   time.Time
+	running bool
+	awake   bool
+	done    bool
+}
+
+// SetMetrics sets the registry to report the task runs.
+//
+// Parameters:
+//   - registry to report the metrics, metrics aren't reported if nil.
+//   - task - task name, used as the metrics label.
+//
+// Remarks:
+//   - Should be called before Start().
+func (r *ScheduledTaskRunner) SetMetrics(registry *sysmetrics.Registry, task string) {
+	r.executor.setMetrics(registry, task)
+}
+
+// Start submits the task to the scheduler.
+//
+// Remarks:
+//   - Subsequent calls are no-op.
+func (r *ScheduledTaskRunner) Start() error {
+	r.scheduler.mu.Lock()
+	defer r.scheduler.mu.Unlock()
+
+	if r.stopFunc != nil {
+		return nil
+	}
+
+	r.stopFunc = context.AfterFunc(r.ctx, r.cancel)
+
+	if r.scheduler.stopped {
+		r.finishLocked()
+	} else if !r.done {
+		r.scheduler.schedule(r, time.Now().Add(r.executor.startDelay()))
+	}
+
+	return nil
+}
+
+// Stop waits for the task processing to end.
+//
+// Remarks:
+//   - Runner context should be canceled before calling Stop().
+//   - Returns immediately if the runner isn't started.
+func (r *ScheduledTaskRunner) Stop() error {
+	r.scheduler.mu.Lock()
+	started := r.stopFunc != nil || r.done
+	r.scheduler.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	<-r.doneCh
+
+	return nil
+}
+
+// Awake runs the task as soon as possible.
+//
+// Remarks:
+//   - The task is run once more after the current run if it's running now.
+//   - Backoff interval and circuit breaker are reset.
+func (r *ScheduledTaskRunner) Awake() {
+	r.executor.reset()
+
+	r.scheduler.mu.Lock()
+	defer r.scheduler.mu.Unlock()
+
+	switch {
+	case r.done:
+	case r.running:
+		r.awake = true
+	case r.index >= 0:
+		r.scheduler.schedule(r, time.Now())
+	}
+}
+
+func (r *ScheduledTaskRunner) runOnce() {
+	if r.ctx.Err() != nil {
+		r.finish()
+
+		return
+	}
+
+	start := time.Now()
+	exit := r.executor.run()
+
+	r.scheduler.mu.Lock()
+	defer r.scheduler.mu.Unlock()
+
+	r.running = false
+
+	if exit || r.ctx.Err() != nil || r.scheduler.stopped {
+		r.finishLocked()
+
+		return
+	}
+
+	runAt := start.Add(r.executor.nextInterval())
+	if now := time.Now(); r.awake || runAt.Before(now) {
+		runAt = now
+	}
+	r.awake = false
+
+	r.scheduler.schedule(r, runAt)
+}
+
+// cancel finishes the runner once its context is canceled.
+func (r *ScheduledTaskRunner) cancel() {
+	r.scheduler.mu.Lock()
+	defer r.scheduler.mu.Unlock()
+
+	// Running task is finished once the run is completed.
+	if r.running {
+		return
+	}
+
+	if r.index >= 0 {
+		heap.Remove(&r.scheduler.queue, r.index)
+	}
+
+	r.finishLocked()
+}
+
+func (r *ScheduledTaskRunner) finish() {
+	r.scheduler.mu.Lock()
+	defer r.scheduler.mu.Unlock()
+
+	r.finishLocked()
+}
+
+func (r *ScheduledTaskRunner) finishLocked() {
+	if r.done {
+		return
+	}
+
+	r.done = true
+	r.running = false
+	close(r.doneCh)
+
+	if r.stopFunc != nil {
+		r.stopFunc()
+	}
+}
+
+// schedulerQueue is a priority queue of runners ordered by the next run time.
+type schedulerQueue []*ScheduledTaskRunner
+
+func (q schedulerQueue) Len() int {
+	return len(q)
+}
+
+func (q schedulerQueue) Less(i, j int) bool {
+	return q[i].runAt.Before(q[j].runAt)
+}
+
+func (q schedulerQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *schedulerQueue) Push(x any) {
+	r := x.(*ScheduledTaskRunner)
+	r.index = len(*q)
+	*q = append(*q, r)
+}
+
+func (q *schedulerQueue) Pop() any {
+	old := *q
+	n := len(old)
+	r := old[n-1]
+	old[n-1] = nil
+	r.index = -1
+	*q = old[:n-1]
+
+	return r
+}
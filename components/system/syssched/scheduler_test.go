@@ -0,0 +1,293 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+type testFuncTask func() error
+
+func (t testFuncTask) Run() error {
+	return t()
+}
+
+type testSchedulerErrorHandler struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+func (h *testSchedulerErrorHandler) HandleError(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.errors = append(h.errors, err)
+}
+
+func (h *testSchedulerErrorHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.errors)
+}
+
+type testSchedulerBlockingTask struct {
+	running atomic.Int32
+	maxRuns atomic.Int32
+	calls   atomic.Int32
+	delay   time.Duration
+}
+
+func (t *testSchedulerBlockingTask) Run() error {
+	running := t.running.Add(1)
+	defer t.running.Add(-1)
+
+	for {
+		curr := t.maxRuns.Load()
+		if running <= curr || t.maxRuns.CompareAndSwap(curr, running) {
+			break
+		}
+	}
+
+	t.calls.Add(1)
+	time.Sleep(t.delay)
+
+	return nil
+}
+
+func startTestScheduler(t *testing.T, workers int) (*Scheduler, context.Context) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	scheduler := NewScheduler(ctx, SchedulerParams{Workers: workers})
+	require.Nil(t, scheduler.Start())
+
+	t.Cleanup(func() {
+		cancel()
+		require.Nil(t, scheduler.Stop())
+	})
+
+	return scheduler, ctx
+}
+
+func TestSchedulerRun(t *testing.T) {
+	scheduler, parentCtx := startTestScheduler(t, 2)
+
+	ctx, cancel := context.WithCancel(parentCtx)
+
+	task := &testAsyncTaskRunnerTestTask{err: status.StatusError}
+	handler := &testSchedulerErrorHandler{}
+
+	runner := scheduler.NewRunner(ctx, task, handler, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 10,
+	})
+	require.Nil(t, runner.Start())
+
+	require.Eventually(t, func() bool {
+		return task.getCallCount() >= 3 && handler.count() >= 3
+	}, time.Second, time.Millisecond*10)
+
+	cancel()
+	require.Nil(t, runner.Stop())
+
+	// Task isn't run once the runner is stopped.
+	calls := task.getCallCount()
+	time.Sleep(time.Millisecond * 50)
+	require.Equal(t, calls, task.getCallCount())
+}
+
+func TestSchedulerExitOnSuccess(t *testing.T) {
+	scheduler, ctx := startTestScheduler(t, 1)
+
+	task := &testAsyncTaskRunnerTestTask{err: status.StatusNotSupported}
+
+	runner := scheduler.NewRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Millisecond * 10,
+		ExitOnSuccess:  true,
+	})
+	require.Nil(t, runner.Start())
+
+	require.Eventually(t, func() bool {
+		return task.getCallCount() >= 2
+	}, time.Second, time.Millisecond*10)
+
+	task.setError(nil)
+	require.Nil(t, runner.Stop())
+}
+
+func TestSchedulerAwake(t *testing.T) {
+	scheduler, ctx := startTestScheduler(t, 1)
+
+	task := &testAsyncTaskRunnerTestTask{}
+
+	runner := scheduler.NewRunner(ctx, task, nil, AsyncTaskRunnerParams{
+		UpdateInterval: time.Hour,
+	})
+	require.Nil(t, runner.Start())
+
+	require.Eventually(t, func() bool {
+		return task.getCallCount() == 1
+	}, time.Second, time.Millisecond*10)
+
+	runner.Awake()
+
+	require.Eventually(t, func() bool {
+		return task.getCallCount() == 2
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestSchedulerWorkers(t *testing.T) {
+	scheduler, ctx := startTestScheduler(t, 2)
+
+	task := &testSchedulerBlockingTask{delay: time.Millisecond * 10}
+
+	for n := 0; n < 10; n++ {
+		runner := scheduler.NewRunner(ctx, task, nil, AsyncTaskRunnerParams{
+			UpdateInterval: time.Millisecond,
+		})
+		require.Nil(t, runner.Start())
+	}
+
+	require.Eventually(t, func() bool {
+		return task.calls.Load() >= 20
+	}, time.Second*5, time.Millisecond*10)
+
+	// Number of the concurrent runs is bounded by the number of workers.
+	require.Equal(t, int32(2), task.maxRuns.Load())
+}
+
+func TestSchedulerStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	scheduler := NewScheduler(ctx, SchedulerParams{Workers: 1})
+	require.Nil(t, scheduler.Start())
+
+	var runners []*ScheduledTaskRunner
+
+	for n := 0; n < 10; n++ {
+		runner := scheduler.NewRunner(ctx, &testAsyncTaskRunnerTestTask{}, nil,
+			AsyncTaskRunnerParams{
+				UpdateInterval: time.Hour,
+			})
+		require.Nil(t, runner.Start())
+
+		runners = append(runners, runner)
+	}
+
+	cancel()
+	require.Nil(t, scheduler.Stop())
+
+	// All runners are stopped with the scheduler, queued or not.
+	for _, runner := range runners {
+		require.Nil(t, runner.Stop())
+	}
+}
+
+// benchmarkDevices is the number of simulated devices.
+const benchmarkDevices = 5000
+
+type benchmarkTaskRunner interface {
+	Starter
+	Stopper
+}
+
+// benchmarkRunners polls the simulated devices until b.N polls are done, and
+// reports the number of goroutines and the memory used by the runners.
+func benchmarkRunners(
+	b *testing.B,
+	newRunner func(ctx context.Context, task Task) benchmarkTaskRunner,
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var (
+		before runtime.MemStats
+		after  runtime.MemStats
+	)
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	goroutines := runtime.NumGoroutine()
+
+	doneCh := make(chan struct{})
+
+	var calls atomic.Int64
+
+	task := testFuncTask(func() error {
+		if calls.Add(1) == int64(b.N) {
+			close(doneCh)
+		}
+
+		return nil
+	})
+
+	b.ResetTimer()
+
+	var runners []benchmarkTaskRunner
+
+	for n := 0; n < benchmarkDevices; n++ {
+		runner := newRunner(ctx, task)
+		if err := runner.Start(); err != nil {
+			b.Fatal(err)
+		}
+
+		runners = append(runners, runner)
+	}
+
+	<-doneCh
+
+	b.StopTimer()
+
+	runtime.ReadMemStats(&after)
+
+	b.ReportMetric(float64(runtime.NumGoroutine()-goroutines), "goroutines")
+	b.ReportMetric(float64(after.HeapInuse+after.StackInuse)-
+		float64(before.HeapInuse+before.StackInuse), "mem-bytes")
+
+	cancel()
+
+	for _, runner := range runners {
+		if err := runner.Stop(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAsyncTaskRunnerDevices(b *testing.B) {
+	benchmarkRunners(b, func(ctx context.Context, task Task) benchmarkTaskRunner {
+		return NewAsyncTaskRunner(ctx, task, nil, AsyncTaskRunnerParams{
+			UpdateInterval: time.Millisecond * 100,
+		})
+	})
+}
+
+func BenchmarkSchedulerDevices(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	scheduler := NewScheduler(ctx, SchedulerParams{Workers: 64})
+	if err := scheduler.Start(); err != nil {
+		b.Fatal(err)
+	}
+
+	benchmarkRunners(b, func(runnerCtx context.Context, task Task) benchmarkTaskRunner {
+		return scheduler.NewRunner(runnerCtx, task, nil, AsyncTaskRunnerParams{
+			UpdateInterval: time.Millisecond * 100,
+		})
+	})
+
+	cancel()
+
+	if err := scheduler.Stop(); err != nil {
+		b.Fatal(err)
+	}
+}
@@ -0,0 +1,18 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import "github.com/tendry-lab/device-hub/components/system/sysmetrics"
+
+// TaskRunner periodically runs the task, e.g. AsyncTaskRunner or ScheduledTaskRunner.
+type TaskRunner interface {
+	Starter
+	Stopper
+	Awakener
+
+	// SetMetrics sets the registry to report the task runs.
+	SetMetrics(registry *sysmetrics.Registry, task string)
+}
@@ -0,0 +1,247 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
+)
+
+// WatchdogParams represents various configuration options for Watchdog.
+type WatchdogParams struct {
+	// Deadline - maximum duration of a single task run, should be positive.
+	Deadline time.Duration
+}
+
+// WatchdogStall describes the task which has exceeded the run deadline.
+type WatchdogStall struct {
+	// Task - task name.
+	Task string `json:"task"`
+
+	// Stalled - true if the task is still running after the deadline.
+	Stalled bool `json:"stalled"`
+
+	// Count - number of the runs which have exceeded the deadline.
+	Count int `json:"count"`
+
+	// LastStall - time the task has exceeded the deadline for the last time.
+	LastStall string `json:"last_stall"`
+}
+
+// Watchdog detects the task runs which last longer than the configured deadline.
+//
+// Remarks:
+//   - Stalled run is logged with the stack traces of all goroutines, and the
+//     context of the run is canceled, so the task can interrupt the blocking
+//     operations derived from it.
+//   - Task is rebuilt with the new context before the run which follows the stalled
+//     one, the context of the stalled run stays canceled.
+//   - Operations which don't depend on the run context can't be interrupted, the
+//     stall is still reported for them.
+type Watchdog struct {
+	params WatchdogParams
+	stalls *sysmetrics.Counter
+
+	mu    sync.Mutex
+	tasks map[*WatchdogTask]struct{}
+}
+
+// NewWatchdog is an initialization of Watchdog.
+func NewWatchdog(params WatchdogParams) *Watchdog {
+	return &Watchdog{
+		params: params,
+		tasks:  make(map[*WatchdogTask]struct{}),
+	}
+}
+
+// SetMetrics sets the registry to report the number of stalled runs.
+//
+// Remarks:
+//   - Metrics aren't reported if the registry is nil.
+//   - Should be called before Watch().
+func (w *Watchdog) SetMetrics(registry *sysmetrics.Registry) {
+	w.stalls = registry.Counter("device_hub_watchdog_stalls",
+		"Number of the task runs which have exceeded the deadline.")
+}
+
+// Watch returns the task which runs the built task with the run deadline.
+//
+// Parameters:
+//   - ctx - parent context of the run contexts, task is no longer watched once
+//     it's canceled.
+//   - name - task name, used to report the stalls.
+//   - build - builds the task, the provided context should be used for all task
+//     operations which may block. Task is built again once its run is stalled.
+func (w *Watchdog) Watch(
+	ctx context.Context,
+	name string,
+	build func(ctx context.Context) Task,
+) *WatchdogTask {
+	runCtx, cancel := context.WithCancel(ctx)
+
+	t := &WatchdogTask{
+		watchdog: w,
+		name:     name,
+		ctx:      ctx,
+		build:    build,
+		task:     build(runCtx),
+		cancel:   cancel,
+	}
+
+	w.mu.Lock()
+	w.tasks[t] = struct{}{}
+	w.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.tasks, t)
+	})
+
+	return t
+}
+
+// GetStalls returns the watched tasks which have exceeded the deadline at least once.
+func (w *Watchdog) GetStalls() []WatchdogStall {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var stalls []WatchdogStall
+
+	for t := range w.tasks {
+		if t.count == 0 {
+			continue
+		}
+
+		stalls = append(stalls, WatchdogStall{
+			Task:      t.name,
+			Stalled:   t.stalled,
+			Count:     t.count,
+			LastStall: t.lastStall.Format(time.RFC1123),
+		})
+	}
+
+	slices.SortFunc(stalls, func(a, b WatchdogStall) int {
+		return strings.Compare(a.Task, b.Task)
+	})
+
+	return stalls
+}
+
+// WatchdogTask runs the underlying task with the run deadline.
+type WatchdogTask struct {
+	watchdog *Watchdog
+	name     string
+	ctx      context.Context
+	build    func(ctx context.Context) Task
+	task     Task
+
+	// Protected by the watchdog mutex.
+	cancel    context.CancelFunc
+	canceled  bool
+	run       uint64
+	running   bool
+	stalled   bool
+	count     int
+	lastStall time.Time
+}
+
+// Run runs the underlying task, and reports the run if it exceeds the deadline.
+func (t *WatchdogTask) Run() error {
+	t.rebuild()
+
+	run := t.begin()
+
+	timer := time.AfterFunc(t.watchdog.params.Deadline, func() {
+		t.stall(run)
+	})
+
+	err := t.task.Run()
+
+	timer.Stop()
+	t.end()
+
+	return err
+}
+
+// rebuild builds the task with the new context, if the context of the previous
+// run was canceled, and the parent context isn't canceled.
+func (t *WatchdogTask) rebuild() {
+	t.watchdog.mu.Lock()
+	canceled := t.canceled
+	t.watchdog.mu.Unlock()
+
+	if !canceled || t.ctx.Err() != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	task := t.build(ctx)
+
+	t.watchdog.mu.Lock()
+	defer t.watchdog.mu.Unlock()
+
+	t.task = task
+	t.cancel = cancel
+	t.canceled = false
+}
+
+func (t *WatchdogTask) begin() uint64 {
+	t.watchdog.mu.Lock()
+	defer t.watchdog.mu.Unlock()
+
+	t.run++
+	t.running = true
+
+	return t.run
+}
+
+func (t *WatchdogTask) end() {
+	t.watchdog.mu.Lock()
+	defer t.watchdog.mu.Unlock()
+
+	t.running = false
+
+	if t.stalled {
+		t.stalled = false
+
+		syscore.LogInf.Printf("stalled task completed: task=%s", t.name)
+	}
+}
+
+func (t *WatchdogTask) stall(run uint64) {
+	t.watchdog.mu.Lock()
+
+	if !t.running || t.run != run {
+		t.watchdog.mu.Unlock()
+
+		return
+	}
+
+	t.stalled = true
+	t.canceled = true
+	t.count++
+	t.lastStall = time.Now()
+
+	cancel := t.cancel
+
+	t.watchdog.mu.Unlock()
+
+	t.watchdog.stalls.Inc()
+
+	syscore.LogStall(fmt.Sprintf("task exceeded deadline: task=%s deadline=%s",
+		t.name, t.watchdog.params.Deadline))
+
+	cancel()
+}
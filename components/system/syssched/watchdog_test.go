@@ -0,0 +1,177 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package syssched
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/status"
+)
+
+type testWatchdogTask struct {
+	ctx   context.Context
+	block atomic.Bool
+	calls atomic.Int32
+}
+
+func (t *testWatchdogTask) Run() error {
+	t.calls.Add(1)
+
+	if !t.block.Load() {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(t.ctx, time.Second*5)
+	defer cancel()
+
+	<-ctx.Done()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return status.StatusTimeout
+	}
+
+	return ctx.Err()
+}
+
+func TestWatchdogStall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchdog := NewWatchdog(WatchdogParams{Deadline: time.Millisecond * 50})
+
+	task := &testWatchdogTask{}
+	task.block.Store(true)
+
+	builds := 0
+
+	watchdogTask := watchdog.Watch(ctx, "device", func(ctx context.Context) Task {
+		builds++
+		task.ctx = ctx
+
+		return task
+	})
+	require.Empty(t, watchdog.GetStalls())
+	require.Equal(t, 1, builds)
+
+	stalledCtx := task.ctx
+	start := time.Now()
+
+	// Blocking operation is interrupted once the deadline is exceeded.
+	require.ErrorIs(t, watchdogTask.Run(), context.Canceled)
+	require.Less(t, time.Since(start), time.Second)
+
+	stalls := watchdog.GetStalls()
+	require.Len(t, stalls, 1)
+	require.Equal(t, "device", stalls[0].Task)
+	require.Equal(t, 1, stalls[0].Count)
+	require.False(t, stalls[0].Stalled)
+	require.NotEmpty(t, stalls[0].LastStall)
+
+	// Task is rebuilt with the new context, the stalled one stays canceled.
+	task.block.Store(false)
+	require.Nil(t, watchdogTask.Run())
+	require.Equal(t, 2, builds)
+	require.Nil(t, task.ctx.Err())
+	require.ErrorIs(t, stalledCtx.Err(), context.Canceled)
+
+	require.Nil(t, watchdogTask.Run())
+	require.Equal(t, 2, builds)
+
+	stalls = watchdog.GetStalls()
+	require.Len(t, stalls, 1)
+	require.Equal(t, 1, stalls[0].Count)
+}
+
+func TestWatchdogStalled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchdog := NewWatchdog(WatchdogParams{Deadline: time.Millisecond * 20})
+
+	releaseCh := make(chan struct{})
+
+	watchdogTask := watchdog.Watch(ctx, "device", func(context.Context) Task {
+		// Task ignores the run context.
+		return testFuncTask(func() error {
+			<-releaseCh
+
+			return nil
+		})
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- watchdogTask.Run()
+	}()
+
+	require.Eventually(t, func() bool {
+		stalls := watchdog.GetStalls()
+
+		return len(stalls) == 1 && stalls[0].Stalled
+	}, time.Second, time.Millisecond*10)
+
+	close(releaseCh)
+	require.Nil(t, <-errCh)
+
+	stalls := watchdog.GetStalls()
+	require.Len(t, stalls, 1)
+	require.False(t, stalls[0].Stalled)
+}
+
+func TestWatchdogNoStall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchdog := NewWatchdog(WatchdogParams{Deadline: time.Millisecond * 20})
+
+	task := &testWatchdogTask{}
+
+	watchdogTask := watchdog.Watch(ctx, "device", func(ctx context.Context) Task {
+		task.ctx = ctx
+
+		return task
+	})
+
+	for n := 0; n < 3; n++ {
+		require.Nil(t, watchdogTask.Run())
+	}
+
+	time.Sleep(time.Millisecond * 50)
+
+	require.Empty(t, watchdog.GetStalls())
+	require.Nil(t, task.ctx.Err())
+}
+
+func TestWatchdogParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	watchdog := NewWatchdog(WatchdogParams{Deadline: time.Millisecond * 20})
+
+	task := &testWatchdogTask{}
+	task.block.Store(true)
+
+	watchdogTask := watchdog.Watch(ctx, "device", func(ctx context.Context) Task {
+		task.ctx = ctx
+
+		return task
+	})
+	require.ErrorIs(t, watchdogTask.Run(), context.Canceled)
+	require.Len(t, watchdog.GetStalls(), 1)
+
+	cancel()
+
+	// Task isn't watched once the parent context is canceled.
+	require.Eventually(t, func() bool {
+		return len(watchdog.GetStalls()) == 0
+	}, time.Second, time.Millisecond*10)
+
+	require.ErrorIs(t, task.ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,920 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/device/devstore"
+	"github.com/tendry-lab/device-hub/components/http/htauth"
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/storage/stinfluxdb"
+)
+
+const (
+	storageBackendInfluxDB = "influxdb"
+	storageBackendEmbedded = "embedded"
+)
+
+// Config represents the device-hub configuration file.
+type Config struct {
+	Log struct {
+		// Path is a log file path, stderr is used if empty.
+		Path string `yaml:"path"`
+	} `yaml:"log"`
+
+	Lifecycle struct {
+		// StopTimeout - how long to wait for a single component to stop on shutdown,
+		// or for a single device task to stop once the device is removed or updated,
+		// components are waited forever if zero.
+		StopTimeout time.Duration `yaml:"stop_timeout"`
+	} `yaml:"lifecycle"`
+
+	HTTP struct {
+		// Host is the HTTP server listen address, "0.0.0.0" is used if empty.
+		Host string `yaml:"host"`
+
+		// Port is the HTTP server listen port, random port is used if zero.
+		Port int `yaml:"port"`
+
+		Auth struct {
+			// Enable to require API tokens for the HTTP API.
+			//
+			// Remarks:
+			//  - Requires storage.path, tokens are persisted in the bbolt database.
+			Enable bool `yaml:"enable"`
+
+			// AuditLogPath - file to record the API calls changing the hub state,
+			// the calls are recorded in the hub log if empty.
+			AuditLogPath string `yaml:"audit_log_path"`
+
+			// IngestToken to require the token with the ingest scope for the data
+			// pushed by devices, devices push data without the token if false.
+			IngestToken bool `yaml:"ingest_token"`
+		} `yaml:"auth"`
+
+		TLS struct {
+			// Enable to serve HTTPS instead of HTTP.
+			Enable bool `yaml:"enable"`
+
+			// CertPath - PEM encoded server certificate file path.
+			CertPath string `yaml:"cert_path"`
+
+			// KeyPath - PEM encoded server private key file path.
+			KeyPath string `yaml:"key_path"`
+
+			// SelfSigned to generate the self-signed certificate, if the certificate
+			// or key file doesn't exist.
+			SelfSigned bool `yaml:"self_signed"`
+
+			// SelfSignedValidity - how long the generated certificate is valid.
+			SelfSignedValidity time.Duration `yaml:"self_signed_validity"`
+
+			// ReloadInterval - how often to check whether the certificate files are
+			// changed, to reload the certificate without restart.
+			ReloadInterval time.Duration `yaml:"reload_interval"`
+
+			ClientAuth struct {
+				// CAPath - PEM encoded CA certificates file path to verify the client
+				// certificates, client certificates aren't verified if empty.
+				CAPath string `yaml:"ca_path"`
+
+				// Scope - requests with this or higher scope require the verified
+				// client certificate.
+				Scope string `yaml:"scope"`
+			} `yaml:"client_auth"`
+		} `yaml:"tls"`
+	} `yaml:"http"`
+
+	Storage struct {
+		// Path is a bbolt database file path to persist registered devices.
+		//
+		// Remarks:
+		//  - Registered devices aren't persisted if empty.
+		//  - Device data isn't buffered if empty.
+		Path string `yaml:"path"`
+
+		// KeyPath - file path of the key to encrypt the persisted device credentials.
+		//
+		// Remarks:
+		//  - storage.path with the ".key" suffix is used if empty.
+		//  - Key is generated if the file doesn't exist.
+		KeyPath string `yaml:"key_path"`
+
+		// Backend - where the device data is stored: influxdb or embedded.
+		//
+		// Remarks:
+		//  - embedded backend stores the device data in the bbolt database, and
+		//    requires storage.path.
+		//  - influxdb section is ignored for the embedded backend.
+		Backend string `yaml:"backend"`
+
+		Embedded struct {
+			// Retention - data older than this age is removed, unlimited if zero.
+			Retention time.Duration `yaml:"retention"`
+
+			// DownsampleAfter - data older than this age is downsampled,
+			// data isn't downsampled if zero.
+			DownsampleAfter time.Duration `yaml:"downsample_after"`
+
+			// DownsampleInterval - a single point is kept per interval for the
+			// downsampled data.
+			DownsampleInterval time.Duration `yaml:"downsample_interval"`
+
+			// CompactInterval - how often to remove and downsample the old data.
+			CompactInterval time.Duration `yaml:"compact_interval"`
+		} `yaml:"embedded"`
+
+		Queue struct {
+			// Disable to drop the device data if InfluxDB is unreachable.
+			//
+			// Remarks:
+			//  - Device data isn't buffered for the embedded backend.
+			Disable bool `yaml:"disable"`
+
+			// MaxAge - buffered data older than this age is dropped, unlimited if zero.
+			MaxAge time.Duration `yaml:"max_age"`
+
+			// MaxSize - maximum size of the buffered data in bytes, the earliest data
+			// is dropped if the size is exceeded, unlimited if zero.
+			MaxSize int64 `yaml:"max_size"`
+
+			// ReplayInterval - how often to replay the buffered data.
+			ReplayInterval time.Duration `yaml:"replay_interval"`
+
+			// MaxAttempts - how many times to replay the entry before it's dropped,
+			// entry is replayed until max_age if zero.
+			//
+			// Remarks:
+			//  - Failures while InfluxDB is unreachable are counted too, rejected
+			//    entries are dropped immediately regardless of this option.
+			MaxAttempts int `yaml:"max_attempts"`
+		} `yaml:"queue"`
+	} `yaml:"storage"`
+
+	InfluxDB struct {
+		// URL - InfluxDB URL.
+		URL string `yaml:"url"`
+
+		// Org - InfluxDB organisation name.
+		Org string `yaml:"org"`
+
+		// Token - InfluxDB API token.
+		Token string `yaml:"token"`
+
+		// Bucket - InfluxDB bucket name.
+		Bucket string `yaml:"bucket"`
+
+		// TimestampRestoreRange - number of days to use for the timestamp lookup.
+		TimestampRestoreRange int `yaml:"timestamp_restore_range"`
+
+		Batch struct {
+			// Enable to write points in batches, in the standalone goroutine.
+			Enable bool `yaml:"enable"`
+
+			// Size - maximum number of points written in a single request.
+			Size int `yaml:"size"`
+
+			// BufferSize - maximum number of points waiting to be written.
+			BufferSize int `yaml:"buffer_size"`
+
+			// FlushInterval - how often to write the pending points if the batch isn't full.
+			FlushInterval time.Duration `yaml:"flush_interval"`
+
+			// RetryCount - how many times to retry writing the failed batch.
+			RetryCount int `yaml:"retry_count"`
+
+			// RetryInterval - delay before the first retry, doubled for each next retry.
+			RetryInterval time.Duration `yaml:"retry_interval"`
+
+			// MaxRetryInterval - maximum delay between retries.
+			MaxRetryInterval time.Duration `yaml:"max_retry_interval"`
+		} `yaml:"batch"`
+
+		// Schemas - how the device data is written to InfluxDB, keyed by the device type.
+		//
+		// Remarks:
+		//  - Nested device data is flattened with "_" separator for the types without schema.
+		Schemas map[string]PointSchemaConfig `yaml:"schemas"`
+	} `yaml:"influxdb"`
+
+	Metrics struct {
+		// Disable to not expose the hub runtime metrics on /metrics.
+		Disable bool `yaml:"disable"`
+	} `yaml:"metrics"`
+
+	Prometheus struct {
+		// Enable to expose the latest device telemetry on /metrics in the OpenMetrics format.
+		Enable bool `yaml:"enable"`
+
+		// StaleInterval - device telemetry not updated within this interval isn't
+		// exposed, telemetry doesn't become stale if zero.
+		StaleInterval time.Duration `yaml:"stale_interval"`
+	} `yaml:"prometheus"`
+
+	Device struct {
+		// FetchInterval - how often to fetch data from the device.
+		FetchInterval time.Duration `yaml:"fetch_interval"`
+
+		// FetchTimeout - how long to wait for the response from the device.
+		FetchTimeout time.Duration `yaml:"fetch_timeout"`
+
+		// RegistrationInterval - how often to fetch the device registration data.
+		//
+		// Remarks:
+		//  - Registration is fetched with the telemetry if zero.
+		//  - Registration is always fetched after the fetch failure.
+		RegistrationInterval time.Duration `yaml:"registration_interval"`
+
+		Polling struct {
+			// MaxBackoffInterval - maximum fetch interval after the consecutive failures,
+			// the interval is doubled after each failure, it isn't changed if zero.
+			MaxBackoffInterval time.Duration `yaml:"max_backoff_interval"`
+
+			// StartJitter - maximum random delay before the first fetch.
+			StartJitter time.Duration `yaml:"start_jitter"`
+
+			// Jitter - maximum random delay added to each fetch interval.
+			Jitter time.Duration `yaml:"jitter"`
+
+			// BreakerThreshold - number of consecutive failures to open the circuit
+			// breaker, circuit breaker is disabled if zero.
+			//
+			// Remarks:
+			//  - Device errors aren't logged while the breaker is open.
+			//  - Breaker is reset once the device host is resolved over mDNS.
+			BreakerThreshold int `yaml:"breaker_threshold"`
+
+			// BreakerInterval - how often to fetch data while the circuit breaker is open.
+			BreakerInterval time.Duration `yaml:"breaker_interval"`
+		} `yaml:"polling"`
+
+		TimeSync struct {
+			// Disable to disable automatic device time synchronization.
+			Disable bool `yaml:"disable"`
+
+			// MaxDriftInterval is a maximum allowed time difference between local
+			// and device UNIX time, drift isn't checked if zero.
+			MaxDriftInterval time.Duration `yaml:"max_drift_interval"`
+
+			// RestoreInterval - how often to perform the timestamp restoring procedure.
+			RestoreInterval time.Duration `yaml:"restore_interval"`
+		} `yaml:"time_sync"`
+
+		AliveMonitor struct {
+			// Disable to keep inactive devices in the store.
+			Disable bool `yaml:"disable"`
+
+			// DegradedInterval - how long a device can be inactive before it's degraded,
+			// device isn't degraded if zero.
+			DegradedInterval time.Duration `yaml:"degraded_interval"`
+
+			// InactiveInterval - how long a device can be inactive before it's offline.
+			InactiveInterval time.Duration `yaml:"inactive_interval"`
+
+			// Action - what to do with the offline device: offline, pause or remove.
+			Action string `yaml:"action"`
+
+			// PausedInterval - how often to fetch data from the paused device, to
+			// detect that it's active again, paused device isn't fetched if zero.
+			PausedInterval time.Duration `yaml:"paused_interval"`
+
+			// UpdateInterval - how often to check for inactive devices.
+			UpdateInterval time.Duration `yaml:"update_interval"`
+		} `yaml:"alive_monitor"`
+
+		Diagnostics struct {
+			// HistorySize - how many payloads, errors and time synchronizations to keep
+			// for each device.
+			HistorySize int `yaml:"history_size"`
+		} `yaml:"diagnostics"`
+
+		Scheduler struct {
+			// Workers - maximum number of device tasks run at the same time.
+			//
+			// Remarks:
+			//  - Each device task is run in the standalone goroutine if zero.
+			Workers int `yaml:"workers"`
+		} `yaml:"scheduler"`
+
+		Watchdog struct {
+			// Deadline - maximum duration of a single device polling, the stalled polling
+			// is interrupted and reported.
+			//
+			// Remarks:
+			//  - Should be greater than fetch_timeout.
+			//  - Polling duration isn't limited if zero.
+			Deadline time.Duration `yaml:"deadline"`
+		} `yaml:"watchdog"`
+
+		MQTT struct {
+			// Timeout - how long to wait for the MQTT broker to acknowledge the operation.
+			Timeout time.Duration `yaml:"timeout"`
+		} `yaml:"mqtt"`
+
+		// Profiles - device profiles keyed by the device type.
+		//
+		// Remarks:
+		//  - The control-components profile is used for the types without profile.
+		Profiles map[string]ProfileConfig `yaml:"profiles"`
+	} `yaml:"device"`
+
+	Mdns struct {
+		Server struct {
+			// Disable to disable the mDNS server.
+			Disable bool `yaml:"disable"`
+
+			// Hostname is the mDNS hostname, without the ".local" suffix.
+			Hostname string `yaml:"hostname"`
+
+			// Instance is the mDNS service instance name.
+			Instance string `yaml:"instance"`
+
+			// Ifaces are network interfaces names to announce the mDNS services on.
+			//
+			// Remarks:
+			//  - All multicast interfaces are used if empty.
+			Ifaces []string `yaml:"ifaces"`
+		} `yaml:"server"`
+
+		Browser struct {
+			// Disable to disable the mDNS browser.
+			//
+			// Remarks:
+			//  - mDNS devices can't be resolved if the browser is disabled.
+			Disable bool `yaml:"disable"`
+
+			// Interval - how often to browse the local network.
+			Interval time.Duration `yaml:"interval"`
+
+			// Timeout - how long a single browsing operation lasts.
+			Timeout time.Duration `yaml:"timeout"`
+
+			// Autodiscovery to automatically add devices discovered over mDNS.
+			Autodiscovery bool `yaml:"autodiscovery"`
+		} `yaml:"browser"`
+	} `yaml:"mdns"`
+}
+
+// ProfileConfig describes how to communicate with the devices of a particular type.
+//
+// Remarks:
+//   - The control-components profile values are used for the missed options.
+type ProfileConfig struct {
+	HTTP struct {
+		Registration EndpointConfig `yaml:"registration"`
+		Telemetry    EndpointConfig `yaml:"telemetry"`
+
+		// TimePath - path to get/set the device UNIX time, time sync is disabled if empty.
+		TimePath *string `yaml:"time_path"`
+
+		// RegistrationInterval - overrides device.registration_interval if positive.
+		RegistrationInterval time.Duration `yaml:"registration_interval"`
+
+		// Streams - additional device data streams.
+		Streams []StreamConfig `yaml:"streams"`
+	} `yaml:"http"`
+
+	Schema struct {
+		// IDPath - dot-separated path to the device ID in the registration data.
+		IDPath string `yaml:"id_path"`
+
+		// TimestampPath - dot-separated path to the UNIX timestamp in the device data.
+		TimestampPath string `yaml:"timestamp_path"`
+
+		// TimestampUnit - unit of the UNIX timestamp: s, ms or us.
+		TimestampUnit string `yaml:"timestamp_unit"`
+	} `yaml:"schema"`
+
+	// TLS - TLS settings of the https:// devices, overridden by the device settings.
+	TLS struct {
+		// CAPath - PEM encoded CA bundle to verify the device certificate.
+		CAPath string `yaml:"ca_path"`
+
+		// Fingerprint - hex encoded SHA-256 fingerprint of the device certificate.
+		Fingerprint string `yaml:"fingerprint"`
+
+		// CertPath - PEM encoded client certificate presented to the device.
+		CertPath string `yaml:"cert_path"`
+
+		// KeyPath - PEM encoded client private key.
+		KeyPath string `yaml:"key_path"`
+
+		// ServerName - name to verify the device certificate, URI hostname if empty.
+		ServerName string `yaml:"server_name"`
+	} `yaml:"tls"`
+}
+
+// PointSchemaConfig describes how the device data is converted to the InfluxDB point.
+type PointSchemaConfig struct {
+	// Separator - separator of the flattened nested keys, "_" is used if empty.
+	Separator string `yaml:"separator"`
+
+	// Tags - flattened fields promoted to tags, e.g. firmware version.
+	Tags []string `yaml:"tags"`
+
+	// TypeTag to store the device type in the "type" tag.
+	TypeTag bool `yaml:"type_tag"`
+
+	// DescTag to store the device description in the "desc" tag.
+	DescTag bool `yaml:"desc_tag"`
+
+	// Fields - types of the flattened fields: float, int, bool or string.
+	Fields map[string]string `yaml:"fields"`
+}
+
+func (c *PointSchemaConfig) toPointSchema() (stinfluxdb.PointSchema, error) {
+	schema := stinfluxdb.DefaultPointSchema()
+
+	if c.Separator != "" {
+		schema.Separator = c.Separator
+	}
+
+	schema.Tags = c.Tags
+	schema.TypeTag = c.TypeTag
+	schema.DescTag = c.DescTag
+
+	if len(c.Fields) > 0 {
+		schema.Fields = make(map[string]stinfluxdb.FieldType)
+	}
+
+	for field, typ := range c.Fields {
+		fieldType, err := stinfluxdb.ParseFieldType(typ)
+		if err != nil {
+			return schema, fmt.Errorf("invalid field: field=%s err=%w", field, err)
+		}
+
+		schema.Fields[field] = fieldType
+	}
+
+	if err := schema.Validate(); err != nil {
+		return schema, err
+	}
+
+	return schema, nil
+}
+
+// EndpointConfig is a device HTTP resource.
+type EndpointConfig struct {
+	Path   string `yaml:"path"`
+	Method string `yaml:"method"`
+}
+
+// StreamConfig is an additional device data stream.
+type StreamConfig struct {
+	EndpointConfig `yaml:",inline"`
+
+	// Name - unique stream name, e.g. diagnostics.
+	Name string `yaml:"name"`
+
+	// Interval - how often to fetch the stream, device.fetch_interval is used if zero.
+	Interval time.Duration `yaml:"interval"`
+
+	// Measurement - where the stream data is stored, stream name is used if empty.
+	Measurement string `yaml:"measurement"`
+}
+
+func (c *EndpointConfig) apply(endpoint *devstore.HTTPEndpoint) {
+	if c.Path != "" {
+		endpoint.Path = c.Path
+	}
+	if c.Method != "" {
+		endpoint.Method = strings.ToUpper(c.Method)
+	}
+}
+
+func (c *ProfileConfig) toDeviceProfile() (devstore.DeviceProfile, error) {
+	profile := devstore.DefaultDeviceProfile()
+
+	c.HTTP.Registration.apply(&profile.HTTP.Registration)
+	c.HTTP.Telemetry.apply(&profile.HTTP.Telemetry)
+
+	if c.HTTP.TimePath != nil {
+		profile.HTTP.TimePath = *c.HTTP.TimePath
+	}
+
+	profile.HTTP.RegistrationInterval = c.HTTP.RegistrationInterval
+
+	for _, streamConfig := range c.HTTP.Streams {
+		stream := devstore.HTTPStream{
+			HTTPEndpoint: devstore.HTTPEndpoint{Method: http.MethodGet},
+			Name:         streamConfig.Name,
+			Interval:     streamConfig.Interval,
+			Measurement:  streamConfig.Measurement,
+		}
+		streamConfig.apply(&stream.HTTPEndpoint)
+
+		profile.HTTP.Streams = append(profile.HTTP.Streams, stream)
+	}
+
+	if c.Schema.IDPath != "" {
+		profile.Schema.IDPath = c.Schema.IDPath
+	}
+	if c.Schema.TimestampPath != "" {
+		profile.Schema.TimestampPath = c.Schema.TimestampPath
+	}
+
+	unit, err := devcore.ParseTimestampUnit(c.Schema.TimestampUnit)
+	if err != nil {
+		return profile, err
+	}
+	profile.Schema.TimestampUnit = unit
+
+	profile.TLS = devstore.DeviceTLS{
+		CAPath:      c.TLS.CAPath,
+		Fingerprint: c.TLS.Fingerprint,
+		CertPath:    c.TLS.CertPath,
+		KeyPath:     c.TLS.KeyPath,
+		ServerName:  c.TLS.ServerName,
+	}
+
+	if profile.TLS.Fingerprint != "" {
+		if _, err := htcore.ParseFingerprint(profile.TLS.Fingerprint); err != nil {
+			return profile, err
+		}
+	}
+	if (profile.TLS.CertPath == "") != (profile.TLS.KeyPath == "") {
+		return profile, fmt.Errorf("tls: both cert_path and key_path should be set")
+	}
+
+	return profile, nil
+}
+
+// loadConfig reads the configuration file from the provided path.
+//
+// Remarks:
+//   - Default values are used for options missed in the file.
+func loadConfig(path string) (*Config, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseConfig(buf)
+}
+
+// parseConfig parses YAML configuration.
+func parseConfig(buf []byte) (*Config, error) {
+	config := newDefaultConfig()
+
+	decoder := yaml.NewDecoder(bytes.NewReader(buf))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return config, nil
+}
+
+func newDefaultConfig() *Config {
+	config := &Config{}
+
+	config.Lifecycle.StopTimeout = time.Second * 10
+
+	config.HTTP.Port = 12345
+	config.HTTP.TLS.SelfSignedValidity = time.Hour * 24 * 365
+	config.HTTP.TLS.ReloadInterval = time.Minute
+	config.HTTP.TLS.ClientAuth.Scope = string(htauth.ScopeDeviceAdmin)
+
+	config.Storage.Backend = storageBackendInfluxDB
+	config.Storage.Embedded.Retention = time.Hour * 24 * 30
+	config.Storage.Embedded.DownsampleAfter = time.Hour * 24 * 7
+	config.Storage.Embedded.DownsampleInterval = time.Minute * 5
+	config.Storage.Embedded.CompactInterval = time.Hour
+
+	config.Storage.Queue.MaxAge = time.Hour * 24
+	config.Storage.Queue.MaxSize = 64 * 1024 * 1024
+	config.Storage.Queue.ReplayInterval = time.Second * 10
+
+	config.InfluxDB.TimestampRestoreRange = 30
+	config.InfluxDB.Batch.Size = 500
+	config.InfluxDB.Batch.BufferSize = 10000
+	config.InfluxDB.Batch.FlushInterval = time.Second
+	config.InfluxDB.Batch.RetryCount = 3
+	config.InfluxDB.Batch.RetryInterval = time.Second
+	config.InfluxDB.Batch.MaxRetryInterval = time.Second * 30
+
+	config.Prometheus.StaleInterval = time.Minute * 5
+
+	config.Device.FetchInterval = time.Second * 5
+	config.Device.FetchTimeout = time.Second * 5
+	config.Device.Polling.MaxBackoffInterval = time.Minute
+	config.Device.Polling.StartJitter = time.Second * 5
+	config.Device.Polling.Jitter = time.Millisecond * 500
+	config.Device.Polling.BreakerThreshold = 10
+	config.Device.Polling.BreakerInterval = time.Minute * 5
+	config.Device.TimeSync.MaxDriftInterval = time.Second * 5
+	config.Device.TimeSync.RestoreInterval = time.Second * 10
+	config.Device.AliveMonitor.DegradedInterval = time.Minute
+	config.Device.AliveMonitor.InactiveInterval = time.Minute * 2
+	config.Device.AliveMonitor.Action = string(devstore.InactiveActionRemove)
+	config.Device.AliveMonitor.UpdateInterval = time.Second * 10
+	config.Device.AliveMonitor.PausedInterval = time.Minute * 5
+	config.Device.Diagnostics.HistorySize = 10
+	config.Device.Watchdog.Deadline = time.Minute
+	config.Device.MQTT.Timeout = time.Second * 5
+
+	config.Mdns.Server.Hostname = "device-hub"
+	config.Mdns.Server.Instance = "Device Hub HTTP Server"
+	config.Mdns.Browser.Interval = time.Second * 40
+	config.Mdns.Browser.Timeout = time.Second * 30
+
+	return config
+}
+
+func (c *Config) validateTLS() error {
+	if c.HTTP.TLS.CertPath == "" {
+		return fmt.Errorf("http.tls.cert_path: should be set")
+	}
+	if c.HTTP.TLS.KeyPath == "" {
+		return fmt.Errorf("http.tls.key_path: should be set")
+	}
+	if c.HTTP.TLS.SelfSigned && c.HTTP.TLS.SelfSignedValidity <= 0 {
+		return fmt.Errorf("http.tls.self_signed_validity: should be positive")
+	}
+	if c.HTTP.TLS.ReloadInterval <= 0 {
+		return fmt.Errorf("http.tls.reload_interval: should be positive")
+	}
+
+	if c.HTTP.TLS.ClientAuth.CAPath != "" {
+		if _, err := htauth.ParseScope(c.HTTP.TLS.ClientAuth.Scope); err != nil {
+			return fmt.Errorf("http.tls.client_auth.scope: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validate() error {
+	if c.Lifecycle.StopTimeout < 0 {
+		return fmt.Errorf("lifecycle.stop_timeout: should be non-negative")
+	}
+
+	if c.HTTP.Port < 0 || c.HTTP.Port > 65535 {
+		return fmt.Errorf("http.port: out of range: %d", c.HTTP.Port)
+	}
+	if c.HTTP.Auth.Enable && c.Storage.Path == "" {
+		return fmt.Errorf("http.auth.enable: requires storage.path")
+	}
+	if c.HTTP.Auth.IngestToken && !c.HTTP.Auth.Enable {
+		return fmt.Errorf("http.auth.ingest_token: requires http.auth.enable")
+	}
+	if c.HTTP.TLS.Enable {
+		if err := c.validateTLS(); err != nil {
+			return err
+		}
+	}
+
+	switch c.Storage.Backend {
+	case storageBackendInfluxDB:
+		if err := c.validateInfluxDB(); err != nil {
+			return err
+		}
+
+	case storageBackendEmbedded:
+		if err := c.validateEmbedded(); err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("storage.backend: unknown backend: %s", c.Storage.Backend)
+	}
+
+	if c.Prometheus.StaleInterval < 0 {
+		return fmt.Errorf("prometheus.stale_interval: should be non-negative")
+	}
+
+	if c.Device.FetchInterval <= 0 {
+		return fmt.Errorf("device.fetch_interval: should be positive")
+	}
+	if c.Device.FetchTimeout <= 0 {
+		return fmt.Errorf("device.fetch_timeout: should be positive")
+	}
+	if c.Device.RegistrationInterval < 0 {
+		return fmt.Errorf("device.registration_interval: should be non-negative")
+	}
+	if err := c.validatePolling(); err != nil {
+		return err
+	}
+	if c.Device.TimeSync.MaxDriftInterval < 0 {
+		return fmt.Errorf("device.time_sync.max_drift_interval: should be non-negative")
+	}
+	if c.Device.TimeSync.RestoreInterval <= 0 {
+		return fmt.Errorf("device.time_sync.restore_interval: should be positive")
+	}
+
+	if !c.Device.AliveMonitor.Disable {
+		if c.Device.AliveMonitor.InactiveInterval <= 0 {
+			return fmt.Errorf("device.alive_monitor.inactive_interval: should be positive")
+		}
+		if c.Device.AliveMonitor.DegradedInterval < 0 ||
+			c.Device.AliveMonitor.DegradedInterval >= c.Device.AliveMonitor.InactiveInterval {
+			return fmt.Errorf("device.alive_monitor.degraded_interval: should be" +
+				" non-negative and less than inactive_interval")
+		}
+
+		switch devstore.InactiveAction(c.Device.AliveMonitor.Action) {
+		case devstore.InactiveActionOffline, devstore.InactiveActionPause,
+			devstore.InactiveActionRemove:
+		default:
+			return fmt.Errorf("device.alive_monitor.action: unsupported action: %s",
+				c.Device.AliveMonitor.Action)
+		}
+		if c.Device.AliveMonitor.UpdateInterval <= 0 {
+			return fmt.Errorf("device.alive_monitor.update_interval: should be positive")
+		}
+		if c.Device.AliveMonitor.PausedInterval < 0 {
+			return fmt.Errorf("device.alive_monitor.paused_interval: should be non-negative")
+		}
+	}
+
+	if c.Device.Diagnostics.HistorySize < 0 {
+		return fmt.Errorf("device.diagnostics.history_size: should be non-negative")
+	}
+
+	if c.Device.Scheduler.Workers < 0 {
+		return fmt.Errorf("device.scheduler.workers: should be non-negative")
+	}
+
+	if deadline := c.Device.Watchdog.Deadline; deadline != 0 &&
+		deadline <= c.Device.FetchTimeout {
+		return fmt.Errorf("device.watchdog.deadline: should be zero or greater than" +
+			" device.fetch_timeout")
+	}
+
+	if c.Device.MQTT.Timeout <= 0 {
+		return fmt.Errorf("device.mqtt.timeout: should be positive")
+	}
+
+	if _, err := c.buildProfiles(); err != nil {
+		return err
+	}
+
+	if !c.Mdns.Server.Disable {
+		if c.Mdns.Server.Hostname == "" {
+			return fmt.Errorf("mdns.server.hostname: missed")
+		}
+		if c.Mdns.Server.Instance == "" {
+			return fmt.Errorf("mdns.server.instance: missed")
+		}
+	}
+
+	if !c.Mdns.Browser.Disable {
+		if c.Mdns.Browser.Interval <= 0 {
+			return fmt.Errorf("mdns.browser.interval: should be positive")
+		}
+		if c.Mdns.Browser.Timeout <= 0 {
+			return fmt.Errorf("mdns.browser.timeout: should be positive")
+		}
+	}
+
+	if c.Mdns.Browser.Disable && c.Mdns.Browser.Autodiscovery {
+		return fmt.Errorf("mdns.browser.autodiscovery: requires mDNS browser")
+	}
+
+	return nil
+}
+
+func (c *Config) validatePolling() error {
+	if c.Device.Polling.MaxBackoffInterval < 0 {
+		return fmt.Errorf("device.polling.max_backoff_interval: should be non-negative")
+	}
+	if c.Device.Polling.StartJitter < 0 {
+		return fmt.Errorf("device.polling.start_jitter: should be non-negative")
+	}
+	if c.Device.Polling.Jitter < 0 {
+		return fmt.Errorf("device.polling.jitter: should be non-negative")
+	}
+	if c.Device.Polling.BreakerThreshold < 0 {
+		return fmt.Errorf("device.polling.breaker_threshold: should be non-negative")
+	}
+	if c.Device.Polling.BreakerThreshold > 0 && c.Device.Polling.BreakerInterval <= 0 {
+		return fmt.Errorf("device.polling.breaker_interval: should be positive")
+	}
+
+	return nil
+}
+
+func (c *Config) validateInfluxDB() error {
+	if !c.Storage.Queue.Disable {
+		if c.Storage.Queue.MaxAge < 0 {
+			return fmt.Errorf("storage.queue.max_age: should be non-negative")
+		}
+		if c.Storage.Queue.MaxSize < 0 {
+			return fmt.Errorf("storage.queue.max_size: should be non-negative")
+		}
+		if c.Storage.Queue.ReplayInterval <= 0 {
+			return fmt.Errorf("storage.queue.replay_interval: should be positive")
+		}
+		if c.Storage.Queue.MaxAttempts < 0 {
+			return fmt.Errorf("storage.queue.max_attempts: should be non-negative")
+		}
+	}
+
+	if c.InfluxDB.URL == "" {
+		return fmt.Errorf("influxdb.url: missed")
+	}
+	if c.InfluxDB.Bucket == "" {
+		return fmt.Errorf("influxdb.bucket: missed")
+	}
+	if c.InfluxDB.TimestampRestoreRange <= 0 {
+		return fmt.Errorf("influxdb.timestamp_restore_range: should be positive")
+	}
+
+	if c.InfluxDB.Batch.Enable {
+		if c.InfluxDB.Batch.Size <= 0 {
+			return fmt.Errorf("influxdb.batch.size: should be positive")
+		}
+		if c.InfluxDB.Batch.BufferSize < c.InfluxDB.Batch.Size {
+			return fmt.Errorf("influxdb.batch.buffer_size: should be at least batch size")
+		}
+		if c.InfluxDB.Batch.FlushInterval <= 0 {
+			return fmt.Errorf("influxdb.batch.flush_interval: should be positive")
+		}
+		if c.InfluxDB.Batch.RetryCount < 0 {
+			return fmt.Errorf("influxdb.batch.retry_count: should be non-negative")
+		}
+		if c.InfluxDB.Batch.RetryInterval <= 0 {
+			return fmt.Errorf("influxdb.batch.retry_interval: should be positive")
+		}
+		if c.InfluxDB.Batch.MaxRetryInterval < c.InfluxDB.Batch.RetryInterval {
+			return fmt.Errorf("influxdb.batch.max_retry_interval: should be at least" +
+				" retry interval")
+		}
+	}
+
+	if _, err := c.buildPointSchemas(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Config) validateEmbedded() error {
+	if c.Storage.Path == "" {
+		return fmt.Errorf("storage.backend: embedded backend requires storage.path")
+	}
+	if c.Storage.Embedded.Retention < 0 {
+		return fmt.Errorf("storage.embedded.retention: should be non-negative")
+	}
+	if c.Storage.Embedded.DownsampleAfter < 0 {
+		return fmt.Errorf("storage.embedded.downsample_after: should be non-negative")
+	}
+	if c.Storage.Embedded.DownsampleAfter > 0 &&
+		c.Storage.Embedded.DownsampleInterval < time.Second {
+		return fmt.Errorf("storage.embedded.downsample_interval: should be at least 1s")
+	}
+	if c.Storage.Embedded.CompactInterval <= 0 {
+		return fmt.Errorf("storage.embedded.compact_interval: should be positive")
+	}
+
+	return nil
+}
+
+func (c *Config) buildProfiles() (*devstore.ProfileRegistry, error) {
+	registry := devstore.NewProfileRegistry()
+
+	for typ, profileConfig := range c.Device.Profiles {
+		profile, err := profileConfig.toDeviceProfile()
+		if err != nil {
+			return nil, fmt.Errorf("device.profiles.%s: %w", typ, err)
+		}
+
+		if err := registry.Register(typ, profile); err != nil {
+			return nil, fmt.Errorf("device.profiles.%s: %w", typ, err)
+		}
+	}
+
+	return registry, nil
+}
+
+func (c *Config) buildPointSchemas() (map[string]stinfluxdb.PointSchema, error) {
+	schemas := make(map[string]stinfluxdb.PointSchema)
+
+	for typ, schemaConfig := range c.InfluxDB.Schemas {
+		schema, err := schemaConfig.toPointSchema()
+		if err != nil {
+			return nil, fmt.Errorf("influxdb.schemas.%s: %w", typ, err)
+		}
+
+		schemas[typ] = schema
+	}
+
+	return schemas, nil
+}
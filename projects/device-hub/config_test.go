@@ -0,0 +1,562 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendry-lab/device-hub/components/device/devcore"
+	"github.com/tendry-lab/device-hub/components/device/devstore"
+	"github.com/tendry-lab/device-hub/components/storage/stinfluxdb"
+)
+
+func TestConfigParseDefaults(t *testing.T) {
+	config, err := parseConfig([]byte(`
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`))
+	require.Nil(t, err)
+
+	defaultConfig := newDefaultConfig()
+	defaultConfig.InfluxDB.URL = "http://localhost:8086"
+	defaultConfig.InfluxDB.Bucket = "device-hub"
+
+	require.Equal(t, defaultConfig, config)
+}
+
+func TestConfigParse(t *testing.T) {
+	config, err := parseConfig([]byte(`
+log:
+  path: /var/log/device-hub.log
+lifecycle:
+  stop_timeout: 30s
+http:
+  host: 127.0.0.1
+  port: 8080
+  auth:
+    enable: true
+    audit_log_path: /var/log/device-hub-audit.log
+    ingest_token: true
+  tls:
+    enable: true
+    cert_path: /etc/device-hub/cert.pem
+    key_path: /etc/device-hub/key.pem
+    reload_interval: 30s
+    client_auth:
+      ca_path: /etc/device-hub/ca.pem
+      scope: system-admin
+storage:
+  path: /var/lib/device-hub/bbolt.db
+  key_path: /etc/device-hub/storage.key
+  queue:
+    max_age: 48h
+    max_size: 1048576
+    replay_interval: 30s
+    max_attempts: 5
+influxdb:
+  url: http://localhost:8086
+  org: home
+  token: secret
+  bucket: device-hub
+  timestamp_restore_range: 7
+  batch:
+    enable: true
+    size: 100
+    buffer_size: 1000
+    flush_interval: 2s
+    retry_count: 5
+    retry_interval: 500ms
+    max_retry_interval: 10s
+metrics:
+  disable: true
+prometheus:
+  enable: true
+  stale_interval: 1m
+device:
+  fetch_interval: 10s
+  fetch_timeout: 2s
+  registration_interval: 10m
+  polling:
+    max_backoff_interval: 2m
+    start_jitter: 10s
+    jitter: 1s
+    breaker_threshold: 5
+    breaker_interval: 10m
+  time_sync:
+    disable: true
+    max_drift_interval: 1m
+    restore_interval: 15s
+  alive_monitor:
+    degraded_interval: 2m
+    inactive_interval: 5m
+    action: pause
+    update_interval: 30s
+    paused_interval: 15m
+  diagnostics:
+    history_size: 5
+  scheduler:
+    workers: 64
+  watchdog:
+    deadline: 90s
+  mqtt:
+    timeout: 3s
+mdns:
+  server:
+    hostname: bonsai-hub
+    instance: Bonsai Hub
+    ifaces: [wlan0, eth0]
+  browser:
+    interval: 1m
+    timeout: 20s
+    autodiscovery: true
+`))
+	require.Nil(t, err)
+
+	require.Equal(t, "/var/log/device-hub.log", config.Log.Path)
+	require.Equal(t, time.Second*30, config.Lifecycle.StopTimeout)
+	require.Equal(t, "127.0.0.1", config.HTTP.Host)
+	require.Equal(t, 8080, config.HTTP.Port)
+	require.True(t, config.HTTP.Auth.Enable)
+	require.Equal(t, "/var/log/device-hub-audit.log", config.HTTP.Auth.AuditLogPath)
+	require.True(t, config.HTTP.Auth.IngestToken)
+	require.True(t, config.HTTP.TLS.Enable)
+	require.Equal(t, "/etc/device-hub/cert.pem", config.HTTP.TLS.CertPath)
+	require.Equal(t, "/etc/device-hub/key.pem", config.HTTP.TLS.KeyPath)
+	require.False(t, config.HTTP.TLS.SelfSigned)
+	require.Equal(t, time.Second*30, config.HTTP.TLS.ReloadInterval)
+	require.Equal(t, "/etc/device-hub/ca.pem", config.HTTP.TLS.ClientAuth.CAPath)
+	require.Equal(t, "system-admin", config.HTTP.TLS.ClientAuth.Scope)
+	require.Equal(t, "/var/lib/device-hub/bbolt.db", config.Storage.Path)
+	require.Equal(t, "/etc/device-hub/storage.key", config.Storage.KeyPath)
+	require.False(t, config.Storage.Queue.Disable)
+	require.Equal(t, time.Hour*48, config.Storage.Queue.MaxAge)
+	require.Equal(t, int64(1048576), config.Storage.Queue.MaxSize)
+	require.Equal(t, time.Second*30, config.Storage.Queue.ReplayInterval)
+	require.Equal(t, 5, config.Storage.Queue.MaxAttempts)
+	require.Equal(t, "home", config.InfluxDB.Org)
+	require.Equal(t, "secret", config.InfluxDB.Token)
+	require.Equal(t, 7, config.InfluxDB.TimestampRestoreRange)
+	require.True(t, config.InfluxDB.Batch.Enable)
+	require.Equal(t, 100, config.InfluxDB.Batch.Size)
+	require.Equal(t, 1000, config.InfluxDB.Batch.BufferSize)
+	require.Equal(t, time.Second*2, config.InfluxDB.Batch.FlushInterval)
+	require.Equal(t, 5, config.InfluxDB.Batch.RetryCount)
+	require.Equal(t, time.Millisecond*500, config.InfluxDB.Batch.RetryInterval)
+	require.Equal(t, time.Second*10, config.InfluxDB.Batch.MaxRetryInterval)
+	require.True(t, config.Metrics.Disable)
+	require.True(t, config.Prometheus.Enable)
+	require.Equal(t, time.Minute, config.Prometheus.StaleInterval)
+	require.Equal(t, time.Second*10, config.Device.FetchInterval)
+	require.Equal(t, time.Second*2, config.Device.FetchTimeout)
+	require.Equal(t, time.Minute*10, config.Device.RegistrationInterval)
+	require.Equal(t, time.Minute*2, config.Device.Polling.MaxBackoffInterval)
+	require.Equal(t, time.Second*10, config.Device.Polling.StartJitter)
+	require.Equal(t, time.Second, config.Device.Polling.Jitter)
+	require.Equal(t, 5, config.Device.Polling.BreakerThreshold)
+	require.Equal(t, time.Minute*10, config.Device.Polling.BreakerInterval)
+	require.True(t, config.Device.TimeSync.Disable)
+	require.Equal(t, time.Minute, config.Device.TimeSync.MaxDriftInterval)
+	require.Equal(t, time.Second*15, config.Device.TimeSync.RestoreInterval)
+	require.Equal(t, time.Minute*2, config.Device.AliveMonitor.DegradedInterval)
+	require.Equal(t, time.Minute*5, config.Device.AliveMonitor.InactiveInterval)
+	require.Equal(t, "pause", config.Device.AliveMonitor.Action)
+	require.Equal(t, time.Second*30, config.Device.AliveMonitor.UpdateInterval)
+	require.Equal(t, time.Minute*15, config.Device.AliveMonitor.PausedInterval)
+	require.Equal(t, 5, config.Device.Diagnostics.HistorySize)
+	require.Equal(t, 64, config.Device.Scheduler.Workers)
+	require.Equal(t, time.Second*90, config.Device.Watchdog.Deadline)
+	require.Equal(t, time.Second*3, config.Device.MQTT.Timeout)
+	require.Equal(t, "bonsai-hub", config.Mdns.Server.Hostname)
+	require.Equal(t, "Bonsai Hub", config.Mdns.Server.Instance)
+	require.Equal(t, []string{"wlan0", "eth0"}, config.Mdns.Server.Ifaces)
+	require.Equal(t, time.Minute, config.Mdns.Browser.Interval)
+	require.Equal(t, time.Second*20, config.Mdns.Browser.Timeout)
+	require.True(t, config.Mdns.Browser.Autodiscovery)
+}
+
+func TestConfigParseEmbedded(t *testing.T) {
+	config, err := parseConfig([]byte(`
+storage:
+  path: /var/lib/device-hub/bbolt.db
+  backend: embedded
+  embedded:
+    retention: 48h
+    downsample_after: 0s
+    compact_interval: 10m
+`))
+	require.Nil(t, err)
+
+	require.Equal(t, storageBackendEmbedded, config.Storage.Backend)
+	require.Equal(t, time.Hour*48, config.Storage.Embedded.Retention)
+	require.Equal(t, time.Duration(0), config.Storage.Embedded.DownsampleAfter)
+	require.Equal(t, time.Minute*5, config.Storage.Embedded.DownsampleInterval)
+	require.Equal(t, time.Minute*10, config.Storage.Embedded.CompactInterval)
+}
+
+func TestConfigParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown field", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+  unknown: foo
+`},
+		{"missed tls cert path", `
+http:
+  tls:
+    enable: true
+    key_path: /etc/device-hub/key.pem
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"ingest token without auth", `
+http:
+  auth:
+    ingest_token: true
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"invalid tls client auth scope", `
+http:
+  tls:
+    enable: true
+    cert_path: /etc/device-hub/cert.pem
+    key_path: /etc/device-hub/key.pem
+    client_auth:
+      ca_path: /etc/device-hub/ca.pem
+      scope: root
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"negative stop timeout", `
+lifecycle:
+  stop_timeout: -1s
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"negative scheduler workers", `
+device:
+  scheduler:
+    workers: -1
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"watchdog deadline less than fetch timeout", `
+device:
+  fetch_timeout: 10s
+  watchdog:
+    deadline: 5s
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"missed influxdb url", `
+influxdb:
+  bucket: device-hub
+`},
+		{"invalid duration", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  fetch_interval: foo
+`},
+		{"negative interval", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  fetch_interval: -1s
+`},
+		{"invalid profile timestamp unit", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  profiles:
+    third-party:
+      schema:
+        timestamp_unit: ns
+`},
+		{"invalid profile method", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  profiles:
+    third-party:
+      http:
+        telemetry:
+          method: DELETE
+`},
+		{"duplicate profile stream", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  profiles:
+    third-party:
+      http:
+        streams:
+          - name: diagnostics
+            path: /diagnostics
+          - name: diagnostics
+            path: /diag
+`},
+		{"non-positive queue replay interval", `
+storage:
+  queue:
+    replay_interval: 0s
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"unknown storage backend", `
+storage:
+  backend: sqlite
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"embedded backend without storage path", `
+storage:
+  backend: embedded
+`},
+		{"embedded downsample interval too small", `
+storage:
+  path: /var/lib/device-hub/bbolt.db
+  backend: embedded
+  embedded:
+    downsample_interval: 500ms
+`},
+		{"degraded interval not less than inactive interval", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  alive_monitor:
+    degraded_interval: 2m
+    inactive_interval: 2m
+`},
+		{"unknown alive monitor action", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  alive_monitor:
+    action: forget
+`},
+		{"negative registration interval", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  registration_interval: -1s
+`},
+		{"missed breaker interval", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  polling:
+    breaker_threshold: 5
+    breaker_interval: 0s
+`},
+		{"batch buffer smaller than batch", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+  batch:
+    enable: true
+    size: 100
+    buffer_size: 10
+`},
+		{"invalid point schema field type", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+  schemas:
+    bonsai-growlab:
+      fields:
+        soil_moisture: double
+`},
+		{"reserved point schema tag", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+  schemas:
+    bonsai-growlab:
+      tags: [device_id]
+`},
+		{"invalid profile tls fingerprint", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  profiles:
+    third-party:
+      tls:
+        fingerprint: abcd
+`},
+		{"profile tls cert without key", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  profiles:
+    third-party:
+      tls:
+        cert_path: /etc/device-hub/client.pem
+`},
+		{"auth without storage path", `
+http:
+  auth:
+    enable: true
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+`},
+		{"autodiscovery without browser", `
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+mdns:
+  browser:
+    disable: true
+    autodiscovery: true
+`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := parseConfig([]byte(test.yaml))
+			require.NotNil(t, err)
+			require.Nil(t, config)
+		})
+	}
+}
+
+func TestConfigParseProfiles(t *testing.T) {
+	config, err := parseConfig([]byte(`
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+device:
+  profiles:
+    third-party:
+      http:
+        registration:
+          path: /api/info
+          method: post
+        telemetry:
+          path: /api/data
+        time_path: ""
+        registration_interval: 30m
+        streams:
+          - name: diagnostics
+            path: /api/diagnostics
+            interval: 1m
+          - name: network
+            path: /api/network
+            method: post
+            measurement: network_stats
+      schema:
+        id_path: meta.id
+        timestamp_path: meta.ts
+        timestamp_unit: ms
+      tls:
+        ca_path: /etc/device-hub/devices-ca.pem
+        cert_path: /etc/device-hub/client.pem
+        key_path: /etc/device-hub/client-key.pem
+        server_name: sensor.local
+    bonsai-growlab: {}
+`))
+	require.Nil(t, err)
+
+	profiles, err := config.buildProfiles()
+	require.Nil(t, err)
+
+	profile := profiles.Get("third-party")
+	require.Equal(t, devstore.HTTPEndpoint{Path: "/api/info", Method: http.MethodPost},
+		profile.HTTP.Registration)
+	require.Equal(t, devstore.HTTPEndpoint{Path: "/api/data", Method: http.MethodGet},
+		profile.HTTP.Telemetry)
+	require.Equal(t, "", profile.HTTP.TimePath)
+	require.Equal(t, time.Minute*30, profile.HTTP.RegistrationInterval)
+	require.Equal(t, []devstore.HTTPStream{
+		{
+			HTTPEndpoint: devstore.HTTPEndpoint{Path: "/api/diagnostics", Method: http.MethodGet},
+			Name:         "diagnostics",
+			Interval:     time.Minute,
+		},
+		{
+			HTTPEndpoint: devstore.HTTPEndpoint{Path: "/api/network", Method: http.MethodPost},
+			Name:         "network",
+			Measurement:  "network_stats",
+		},
+	}, profile.HTTP.Streams)
+	require.Equal(t, devcore.DataSchema{
+		IDPath:        "meta.id",
+		TimestampPath: "meta.ts",
+		TimestampUnit: devcore.TimestampUnitMillisecond,
+	}, profile.Schema)
+	require.Equal(t, devstore.DeviceTLS{
+		CAPath:     "/etc/device-hub/devices-ca.pem",
+		CertPath:   "/etc/device-hub/client.pem",
+		KeyPath:    "/etc/device-hub/client-key.pem",
+		ServerName: "sensor.local",
+	}, profile.TLS)
+
+	require.Equal(t, devstore.DefaultDeviceProfile(), profiles.Get("bonsai-growlab"))
+	require.Equal(t, devstore.DefaultDeviceProfile(), profiles.Get("unknown"))
+}
+
+func TestConfigParsePointSchemas(t *testing.T) {
+	config, err := parseConfig([]byte(`
+influxdb:
+  url: http://localhost:8086
+  bucket: device-hub
+  schemas:
+    bonsai-growlab:
+      separator: .
+      tags: [firmware.version]
+      type_tag: true
+      desc_tag: true
+      fields:
+        sensors.soil.moisture: float
+        uptime: int
+    third-party: {}
+`))
+	require.Nil(t, err)
+
+	schemas, err := config.buildPointSchemas()
+	require.Nil(t, err)
+
+	require.Equal(t, stinfluxdb.PointSchema{
+		Separator: ".",
+		Tags:      []string{"firmware.version"},
+		TypeTag:   true,
+		DescTag:   true,
+		Fields: map[string]stinfluxdb.FieldType{
+			"sensors.soil.moisture": stinfluxdb.FieldTypeFloat,
+			"uptime":                stinfluxdb.FieldTypeInt,
+		},
+	}, schemas["bonsai-growlab"])
+	require.Equal(t, stinfluxdb.DefaultPointSchema(), schemas["third-party"])
+}
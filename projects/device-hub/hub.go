@@ -0,0 +1,780 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/tendry-lab/device-hub/components/device/devstore"
+	"github.com/tendry-lab/device-hub/components/http/htauth"
+	"github.com/tendry-lab/device-hub/components/http/htcore"
+	"github.com/tendry-lab/device-hub/components/http/hthandler"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+	"github.com/tendry-lab/device-hub/components/storage/stembedded"
+	"github.com/tendry-lab/device-hub/components/storage/stinfluxdb"
+	"github.com/tendry-lab/device-hub/components/storage/stprometheus"
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+	"github.com/tendry-lab/device-hub/components/system/sysmdns"
+	"github.com/tendry-lab/device-hub/components/system/sysmetrics"
+	"github.com/tendry-lab/device-hub/components/system/sysnet"
+	"github.com/tendry-lab/device-hub/components/system/syssched"
+)
+
+// systemTimeStartPoint is the earliest valid UNIX time for the local clock.
+//
+// Remarks:
+//   - Local time before this point is reported as invalid, e.g. if the hub
+//     is running on a board without the RTC.
+var systemTimeStartPoint = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// tokenBucket is the bbolt bucket to persist the API tokens.
+const tokenBucket = "token_bucket"
+
+// hub wires all device-hub components together.
+type hub struct {
+	components []hubComponent
+	lifecycle  *syssched.Lifecycle
+	metrics    *sysmetrics.Registry
+	watchdog   *syssched.Watchdog
+}
+
+type hubComponent struct {
+	id      string
+	starter syssched.Starter
+	stopper syssched.Stopper
+}
+
+// newHub builds all device-hub components from the configuration.
+//
+// Remarks:
+//   - ctx should be canceled before calling stop().
+func newHub(ctx context.Context, config *Config) (*hub, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	h := &hub{
+		lifecycle: syssched.NewLifecycle(syssched.LifecycleParams{
+			StopTimeout: config.Lifecycle.StopTimeout,
+			Cancel:      cancel,
+		}),
+	}
+
+	if err := h.build(ctx, config); err != nil {
+		cancel()
+		h.release()
+
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// start starts all components in the order of their dependencies.
+//
+// Remarks:
+//   - Started components are stopped if any component fails to start.
+func (h *hub) start() error {
+	return h.lifecycle.Start()
+}
+
+// stop stops the started components in the reverse order.
+//
+// Remarks:
+//   - Components which fail to stop in the configured timeout are reported.
+func (h *hub) stop() error {
+	return h.lifecycle.Stop()
+}
+
+// release frees resources of components that don't require starting.
+func (h *hub) release() {
+	for i := len(h.components) - 1; i >= 0; i-- {
+		c := h.components[i]
+		if c.starter != nil || c.stopper == nil {
+			continue
+		}
+
+		if err := c.stopper.Stop(); err != nil {
+			syscore.LogErr.Printf("failed to release component: id=%s err=%v", c.id, err)
+		}
+	}
+}
+
+// add adds the component, which depends on the components with the provided IDs.
+func (h *hub) add(
+	id string,
+	starter syssched.Starter,
+	stopper syssched.Stopper,
+	deps ...string,
+) {
+	h.components = append(h.components, hubComponent{
+		id:      id,
+		starter: starter,
+		stopper: stopper,
+	})
+
+	h.lifecycle.Add(syssched.LifecycleComponent{
+		ID:        id,
+		Starter:   starter,
+		Stopper:   stopper,
+		DependsOn: deps,
+	})
+}
+
+func (h *hub) build(ctx context.Context, config *Config) error {
+	localClock := &syscore.LocalSystemClock{}
+
+	if !config.Metrics.Disable {
+		h.metrics = sysmetrics.NewRegistry()
+	}
+
+	bboltDB, err := h.buildBboltDB(config)
+	if err != nil {
+		return err
+	}
+
+	var db stcore.DB = &stcore.NoopDB{}
+	if bboltDB != nil {
+		db = stcore.NewBboltDBBucket(bboltDB, "device_bucket")
+	}
+
+	var (
+		readerBuilder  devstore.SystemClockReaderBuilder
+		handlerBuilder devstore.DataHandlerBuilder
+		historyReader  stcore.HistoryReader
+		queue          stcore.Queue
+		storeDeps      []string
+	)
+
+	if bboltDB != nil {
+		storeDeps = append(storeDeps, "bbolt-db")
+	}
+
+	if config.Storage.Backend == storageBackendEmbedded {
+		pipeline, err := h.buildEmbeddedPipeline(ctx, config, bboltDB, localClock)
+		if err != nil {
+			return err
+		}
+
+		readerBuilder = pipeline
+		handlerBuilder = pipeline
+		historyReader = pipeline.BuildHistoryReader()
+	} else {
+		pipeline, err := h.buildInfluxDBPipeline(ctx, config)
+		if err != nil {
+			return err
+		}
+
+		readerBuilder = pipeline
+		handlerBuilder = pipeline
+		historyReader = pipeline.BuildHistoryReader()
+		storeDeps = append(storeDeps, "influxdb-pipeline")
+
+		if bboltDB != nil && !config.Storage.Queue.Disable {
+			queueBuilder, bboltQueue, err := h.buildQueue(
+				ctx, config, bboltDB, localClock, pipeline)
+			if err != nil {
+				return err
+			}
+
+			handlerBuilder = queueBuilder
+			queue = bboltQueue
+		}
+	}
+
+	var collector *stprometheus.Collector
+
+	if config.Prometheus.Enable {
+		collector = stprometheus.NewCollector(&syscore.LocalMonotonicClock{},
+			stprometheus.CollectorParams{
+				StaleInterval: config.Prometheus.StaleInterval,
+			})
+
+		fanoutBuilder := &devstore.FanoutDataHandlerBuilder{}
+		fanoutBuilder.Add(handlerBuilder)
+		fanoutBuilder.Add(collector)
+
+		handlerBuilder = fanoutBuilder
+	}
+
+	resolveStore := sysnet.NewResolveStore()
+	resolveStore.SetMetrics(h.metrics)
+
+	storeParams := devstore.CacheStoreParams{}
+	storeParams.HTTP.FetchInterval = config.Device.FetchInterval
+	storeParams.HTTP.FetchTimeout = config.Device.FetchTimeout
+	storeParams.HTTP.RegistrationInterval = config.Device.RegistrationInterval
+	storeParams.HTTP.MaxBackoffInterval = config.Device.Polling.MaxBackoffInterval
+	storeParams.HTTP.StartJitter = config.Device.Polling.StartJitter
+	storeParams.HTTP.Jitter = config.Device.Polling.Jitter
+	storeParams.HTTP.BreakerThreshold = config.Device.Polling.BreakerThreshold
+	storeParams.HTTP.BreakerInterval = config.Device.Polling.BreakerInterval
+	if !config.Device.AliveMonitor.Disable {
+		storeParams.HTTP.PausedInterval = config.Device.AliveMonitor.PausedInterval
+	}
+	storeParams.TimeSync.Disable = config.Device.TimeSync.Disable
+	storeParams.TimeSync.MaxDriftInterval = config.Device.TimeSync.MaxDriftInterval
+	storeParams.TimeSync.RestoreInterval = config.Device.TimeSync.RestoreInterval
+	storeParams.MQTT.Timeout = config.Device.MQTT.Timeout
+	storeParams.Diagnostics.HistorySize = config.Device.Diagnostics.HistorySize
+	storeParams.StopTimeout = config.Lifecycle.StopTimeout
+
+	profiles, err := config.buildProfiles()
+	if err != nil {
+		return err
+	}
+	storeParams.Profiles = profiles
+	storeParams.Metrics = h.metrics
+
+	if workers := config.Device.Scheduler.Workers; workers > 0 {
+		scheduler := syssched.NewScheduler(ctx, syssched.SchedulerParams{
+			Workers: workers,
+		})
+		scheduler.SetMetrics(h.metrics)
+		h.add("scheduler", scheduler, scheduler)
+
+		storeParams.Scheduler = scheduler
+		storeDeps = append(storeDeps, "scheduler")
+	}
+
+	if deadline := config.Device.Watchdog.Deadline; deadline > 0 {
+		h.watchdog = syssched.NewWatchdog(syssched.WatchdogParams{
+			Deadline: deadline,
+		})
+		h.watchdog.SetMetrics(h.metrics)
+
+		storeParams.Watchdog = h.watchdog
+	}
+
+	cipher, err := h.buildCipher(config)
+	if err != nil {
+		return err
+	}
+	storeParams.Cipher = cipher
+
+	cacheStore := devstore.NewCacheStore(
+		ctx,
+		localClock,
+		readerBuilder,
+		handlerBuilder,
+		db,
+		resolveStore,
+		storeParams,
+	)
+	h.add("cache-store", cacheStore, cacheStore, storeDeps...)
+
+	var store devstore.Store = cacheStore
+
+	if !config.Device.AliveMonitor.Disable {
+		aliveMonitor := devstore.NewStoreAliveMonitor(
+			&syscore.LocalMonotonicClock{},
+			cacheStore,
+			devstore.StoreAliveMonitorParams{
+				DegradedInterval: config.Device.AliveMonitor.DegradedInterval,
+				OfflineInterval:  config.Device.AliveMonitor.InactiveInterval,
+				Action: devstore.InactiveAction(
+					config.Device.AliveMonitor.Action),
+			},
+		)
+		aliveMonitor.SetPauser(cacheStore)
+		cacheStore.SetAliveMonitor(aliveMonitor)
+
+		aliveMonitorRunner := syssched.NewAsyncTaskRunner(
+			ctx,
+			aliveMonitor,
+			aliveMonitor,
+			syssched.AsyncTaskRunnerParams{
+				UpdateInterval: config.Device.AliveMonitor.UpdateInterval,
+			},
+		)
+		aliveMonitorRunner.SetMetrics(h.metrics, "store-alive-monitor")
+		h.add("store-alive-monitor", aliveMonitorRunner, aliveMonitorRunner,
+			"cache-store")
+
+		store = aliveMonitor
+	}
+
+	var browserRunner *syssched.AsyncTaskRunner
+
+	if !config.Mdns.Browser.Disable {
+		serviceHandler := &sysmdns.FanoutServiceHandler{}
+		serviceHandler.Add(sysmdns.NewResolveServiceHandler(resolveStore))
+		serviceHandler.Add(sysmdns.NewResolveServiceHandler(cacheStore))
+
+		if config.Mdns.Browser.Autodiscovery {
+			serviceHandler.Add(devstore.NewStoreMdnsHandler(store))
+		}
+
+		browser := sysmdns.NewZeroconfBrowser(
+			ctx,
+			serviceHandler,
+			sysmdns.ZeroconfBrowserParams{
+				Service: sysmdns.ServiceName(sysmdns.ServiceTypeHTTP, sysmdns.ProtoTCP),
+				Domain:  "local",
+				Timeout: config.Mdns.Browser.Timeout,
+			},
+		)
+		browser.SetMetrics(h.metrics)
+
+		browserRunner = syssched.NewAsyncTaskRunner(
+			ctx,
+			browser,
+			browser,
+			syssched.AsyncTaskRunnerParams{
+				UpdateInterval: config.Mdns.Browser.Interval,
+			},
+		)
+		browserRunner.SetMetrics(h.metrics, "mdns-browser")
+
+		// Newly added mDNS devices should be resolved as soon as possible.
+		store = devstore.NewAwakeStore(browserRunner, store)
+	}
+
+	var metricsHandler *sysmetrics.HTTPHandler
+
+	if h.metrics != nil || collector != nil {
+		metricsHandler = &sysmetrics.HTTPHandler{}
+
+		if h.metrics != nil {
+			metricsHandler.Add(h.metrics)
+		}
+		if collector != nil {
+			metricsHandler.Add(stprometheus.NewMetricsSource(collector, store))
+		}
+	}
+
+	var tokenStore *htauth.TokenStore
+	if config.HTTP.Auth.Enable {
+		tokenStore = htauth.NewTokenStore(stcore.NewBboltDBBucket(bboltDB, tokenBucket))
+	}
+
+	var tlsConfig *tls.Config
+	if config.HTTP.TLS.Enable {
+		tlsConfig, err = h.buildTLSConfig(ctx, config)
+		if err != nil {
+			return err
+		}
+	}
+
+	server, err := h.buildServer(config, localClock, store, cacheStore, queue,
+		historyReader, metricsHandler, tokenStore, tlsConfig)
+	if err != nil {
+		return err
+	}
+	h.add("http-server", server, server, "cache-store")
+
+	if !config.Mdns.Server.Disable {
+		mdnsServer, err := h.buildMdnsServer(config, server.Port(), tlsConfig != nil)
+		if err != nil {
+			return err
+		}
+		h.add("mdns-server", mdnsServer, mdnsServer, "http-server")
+	}
+
+	if browserRunner != nil {
+		h.add("mdns-browser", browserRunner, browserRunner, "cache-store")
+	}
+
+	return nil
+}
+
+func (h *hub) buildInfluxDBPipeline(
+	ctx context.Context,
+	config *Config,
+) (*stinfluxdb.Pipeline, error) {
+	dbParams := stinfluxdb.DBParams{
+		URL:                   config.InfluxDB.URL,
+		Org:                   config.InfluxDB.Org,
+		Token:                 config.InfluxDB.Token,
+		Bucket:                config.InfluxDB.Bucket,
+		TimestampRestoreRange: config.InfluxDB.TimestampRestoreRange,
+	}
+
+	if config.InfluxDB.Batch.Enable {
+		dbParams.Batch = stinfluxdb.BatchWriterParams{
+			BatchSize:        config.InfluxDB.Batch.Size,
+			BufferSize:       config.InfluxDB.Batch.BufferSize,
+			FlushInterval:    config.InfluxDB.Batch.FlushInterval,
+			RetryCount:       config.InfluxDB.Batch.RetryCount,
+			RetryInterval:    config.InfluxDB.Batch.RetryInterval,
+			MaxRetryInterval: config.InfluxDB.Batch.MaxRetryInterval,
+		}
+	}
+
+	pipeline := stinfluxdb.NewPipeline(ctx, dbParams)
+	h.add("influxdb-pipeline", pipeline, pipeline)
+
+	schemas, err := config.buildPointSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	for typ, schema := range schemas {
+		if err := pipeline.RegisterSchema(typ, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	return pipeline, nil
+}
+
+func (h *hub) buildQueue(
+	ctx context.Context,
+	config *Config,
+	bboltDB *bbolt.DB,
+	clock syscore.SystemClock,
+	builder devstore.DataHandlerBuilder,
+) (*devstore.QueueHandlerBuilder, *stcore.BboltQueue, error) {
+	bboltQueue, err := stcore.NewBboltQueue(bboltDB, "queue_bucket", clock,
+		stcore.BboltQueueParams{
+			MaxAge:  config.Storage.Queue.MaxAge,
+			MaxSize: config.Storage.Queue.MaxSize,
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	queueBuilder := devstore.NewQueueHandlerBuilder(builder, bboltQueue,
+		devstore.QueueHandlerBuilderParams{
+			MaxAttempts: config.Storage.Queue.MaxAttempts,
+		})
+
+	queueRunner := syssched.NewAsyncTaskRunner(
+		ctx,
+		queueBuilder,
+		queueBuilder,
+		syssched.AsyncTaskRunnerParams{
+			UpdateInterval: config.Storage.Queue.ReplayInterval,
+		},
+	)
+	queueRunner.SetMetrics(h.metrics, "queue-replayer")
+	h.add("queue-replayer", queueRunner, queueRunner, "influxdb-pipeline", "bbolt-db")
+
+	return queueBuilder, bboltQueue, nil
+}
+
+func (h *hub) buildEmbeddedPipeline(
+	ctx context.Context,
+	config *Config,
+	bboltDB *bbolt.DB,
+	clock syscore.SystemClock,
+) (*stembedded.Pipeline, error) {
+	store, err := stembedded.NewStore(bboltDB, "embedded_bucket")
+	if err != nil {
+		return nil, err
+	}
+
+	compactor := stembedded.NewCompactor(store, clock, stembedded.CompactorParams{
+		Retention:          config.Storage.Embedded.Retention,
+		DownsampleAfter:    config.Storage.Embedded.DownsampleAfter,
+		DownsampleInterval: config.Storage.Embedded.DownsampleInterval,
+	})
+
+	compactorRunner := syssched.NewAsyncTaskRunner(
+		ctx,
+		compactor,
+		compactor,
+		syssched.AsyncTaskRunnerParams{
+			UpdateInterval: config.Storage.Embedded.CompactInterval,
+		},
+	)
+	compactorRunner.SetMetrics(h.metrics, "embedded-compactor")
+	h.add("embedded-compactor", compactorRunner, compactorRunner, "bbolt-db")
+
+	return stembedded.NewPipeline(store), nil
+}
+
+func (h *hub) buildBboltDB(config *Config) (*bbolt.DB, error) {
+	if config.Storage.Path == "" {
+		syscore.LogWrn.Printf("storage path isn't configured, devices won't be persisted" +
+			" and device data won't be buffered")
+
+		return nil, nil
+	}
+
+	db, err := stcore.NewBboltDB(config.Storage.Path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DB: path=%s err=%w", config.Storage.Path, err)
+	}
+	h.add("bbolt-db", nil, syssched.FuncStopper(db.Close))
+
+	return db, nil
+}
+
+// buildCipher returns the cipher to encrypt the persisted device credentials.
+//
+// Remarks:
+//   - Random key is used if the storage path isn't configured, since the devices
+//     aren't persisted.
+func (*hub) buildCipher(config *Config) (stcore.Cipher, error) {
+	if config.Storage.Path == "" {
+		key := make([]byte, stcore.CipherKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+
+		return stcore.NewAESCipher(key)
+	}
+
+	keyPath := config.Storage.KeyPath
+	if keyPath == "" {
+		keyPath = config.Storage.Path + ".key"
+	}
+
+	key, err := stcore.LoadCipherKey(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cipher key: path=%s err=%w", keyPath, err)
+	}
+
+	return stcore.NewAESCipher(key)
+}
+
+func (h *hub) buildServer(
+	config *Config,
+	clock syscore.SystemClock,
+	store devstore.Store,
+	cacheStore *devstore.CacheStore,
+	queue stcore.Queue,
+	historyReader stcore.HistoryReader,
+	metricsHandler *sysmetrics.HTTPHandler,
+	tokenStore *htauth.TokenStore,
+	tlsConfig *tls.Config,
+) (*htcore.Server, error) {
+	mux := htauth.NewMiddleware(http.NewServeMux())
+
+	if tokenStore != nil {
+		mux.SetAuthenticator(tokenStore)
+	}
+
+	if tlsConfig != nil && tlsConfig.ClientCAs != nil {
+		// Scope is validated on the config parsing.
+		scope, _ := htauth.ParseScope(config.HTTP.TLS.ClientAuth.Scope)
+		mux.SetClientCertScope(scope)
+	}
+
+	if config.HTTP.Auth.AuditLogPath != "" {
+		auditLog, err := htauth.NewFileAuditLog(config.HTTP.Auth.AuditLogPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: path=%s err=%w",
+				config.HTTP.Auth.AuditLogPath, err)
+		}
+		h.add("audit-log", nil, auditLog)
+
+		mux.SetAuditLog(auditLog)
+	}
+
+	read := htauth.ReadPolicy(htauth.ScopeRead)
+	deviceAdmin := htauth.WritePolicy(htauth.ScopeDeviceAdmin)
+	systemAdmin := htauth.WritePolicy(htauth.ScopeSystemAdmin)
+
+	mux.Handle("/api/v1/system/time", systemTimePolicy, hthandler.NewSystemTimeHandler(
+		clock, systemTimeStartPoint))
+
+	if h.watchdog != nil {
+		mux.Handle("GET /api/v1/system/stalls", read,
+			hthandler.NewWatchdogHandler(h.watchdog))
+	}
+
+	storeHandler := devstore.NewStoreHTTPHandler(store)
+	mux.HandleFunc("/api/v1/device/add", deviceAdmin, storeHandler.HandleAdd)
+	mux.HandleFunc("/api/v1/device/update", deviceAdmin, storeHandler.HandleUpdate)
+	mux.HandleFunc("/api/v1/device/remove", deviceAdmin, storeHandler.HandleRemove)
+	mux.HandleFunc("/api/v1/device/list", read, storeHandler.HandleList)
+
+	// Probing doesn't change the hub state, but makes requests on behalf of the hub.
+	probeHandler := devstore.NewProbeHTTPHandler(cacheStore)
+	mux.HandleFunc("/api/v1/devices/probe", htauth.ReadPolicy(htauth.ScopeDeviceAdmin),
+		probeHandler.HandleProbe)
+
+	deviceHandler := devstore.NewDeviceHTTPHandler(store)
+	mux.HandleFunc("POST /api/v2/devices", deviceAdmin, deviceHandler.HandleCreate)
+	mux.HandleFunc("GET /api/v2/devices", read, deviceHandler.HandleList)
+	mux.HandleFunc("GET /api/v2/devices/{device}", read, deviceHandler.HandleGet)
+	mux.HandleFunc("PATCH /api/v2/devices/{device}", deviceAdmin, deviceHandler.HandleUpdate)
+	mux.HandleFunc("DELETE /api/v2/devices/{device}", deviceAdmin,
+		deviceHandler.HandleDelete)
+	mux.HandleFunc("GET /api/v2/openapi.json", htauth.PublicPolicy,
+		deviceHandler.HandleOpenAPI)
+
+	if tokenStore != nil {
+		tokenHandler := htauth.NewTokenHTTPHandler(tokenStore)
+		mux.HandleFunc("POST /api/v2/tokens", systemAdmin, tokenHandler.HandleCreate)
+		mux.HandleFunc("GET /api/v2/tokens", htauth.ReadPolicy(htauth.ScopeSystemAdmin),
+			tokenHandler.HandleList)
+		mux.HandleFunc("DELETE /api/v2/tokens/{name}", systemAdmin,
+			tokenHandler.HandleRemove)
+	}
+
+	// Devices push data without the API token, unless the ingest token is required.
+	// Pushed data isn't audited, since devices push it periodically.
+	ingest := htauth.PublicPolicy
+	if config.HTTP.Auth.IngestToken {
+		ingest = htauth.ReadPolicy(htauth.ScopeIngest)
+	}
+
+	pushHandler := devstore.NewPushHTTPHandler(cacheStore, clock)
+	mux.HandleFunc("/api/v1/ingest/{device_id}/registration", ingest,
+		pushHandler.HandleRegistration)
+	mux.HandleFunc("/api/v1/ingest/{device_id}/telemetry", ingest,
+		pushHandler.HandleTelemetry)
+
+	historyHandler := devstore.NewHistoryHTTPHandler(historyReader, clock)
+	mux.HandleFunc("/api/v1/devices/{device_id}/telemetry", read,
+		historyHandler.HandleTelemetry)
+
+	diagnosticsHandler := devstore.NewDiagnosticsHTTPHandler(cacheStore)
+	mux.HandleFunc("/api/v1/devices/{device}/diagnostics", read,
+		diagnosticsHandler.HandleDiagnostics)
+
+	if metricsHandler != nil {
+		mux.HandleFunc("/metrics", read, metricsHandler.HandleMetrics)
+	}
+
+	if queue != nil {
+		queueHandler := devstore.NewQueueHTTPHandler(queue)
+		mux.HandleFunc("/api/v1/queue/stats", read, queueHandler.HandleStats)
+	}
+
+	server, err := htcore.NewServer(hthandler.NewCrashHandler(mux), htcore.ServerParams{
+		Host:      config.HTTP.Host,
+		Port:      config.HTTP.Port,
+		TLSConfig: tlsConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP server: %w", err)
+	}
+
+	syscore.LogInf.Printf("HTTP server is listening on %s", server.URL())
+
+	return server, nil
+}
+
+func (h *hub) buildTLSConfig(ctx context.Context, config *Config) (*tls.Config, error) {
+	if config.HTTP.TLS.SelfSigned {
+		if err := htcore.EnsureSelfSignedCert(
+			config.HTTP.TLS.CertPath,
+			config.HTTP.TLS.KeyPath,
+			selfSignedHosts(config),
+			config.HTTP.TLS.SelfSignedValidity,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	reloader, err := htcore.NewCertReloader(config.HTTP.TLS.CertPath, config.HTTP.TLS.KeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reloaderRunner := syssched.NewAsyncTaskRunner(
+		ctx,
+		reloader,
+		reloader,
+		syssched.AsyncTaskRunnerParams{
+			UpdateInterval: config.HTTP.TLS.ReloadInterval,
+		},
+	)
+	reloaderRunner.SetMetrics(h.metrics, "tls-cert-reloader")
+	h.add("tls-cert-reloader", reloaderRunner, reloaderRunner)
+
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if config.HTTP.TLS.ClientAuth.CAPath != "" {
+		buf, err := os.ReadFile(config.HTTP.TLS.ClientAuth.CAPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: path=%s err=%w",
+				config.HTTP.TLS.ClientAuth.CAPath, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, fmt.Errorf("failed to parse client CA: path=%s",
+				config.HTTP.TLS.ClientAuth.CAPath)
+		}
+
+		// Client certificate is required only for the admin endpoints, which is
+		// checked by the middleware.
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		tlsConfig.ClientCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// selfSignedHosts returns the names the self-signed certificate is valid for.
+func selfSignedHosts(config *Config) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname)
+	}
+
+	if !config.Mdns.Server.Disable && config.Mdns.Server.Hostname != "" {
+		hosts = append(hosts, config.Mdns.Server.Hostname+".local")
+	}
+
+	if ip := net.ParseIP(config.HTTP.Host); ip != nil && !ip.IsUnspecified() {
+		hosts = append(hosts, config.HTTP.Host)
+	}
+
+	return slices.Compact(hosts)
+}
+
+// systemTimePolicy allows to read the hub UNIX time with any token, while only
+// the system admin can set it.
+func systemTimePolicy(r *http.Request) (htauth.Scope, bool) {
+	if r.URL.Query().Has("timestamp") {
+		return htauth.ScopeSystemAdmin, true
+	}
+
+	return htauth.ScopeRead, false
+}
+
+func (*hub) buildMdnsServer(
+	config *Config,
+	port int,
+	https bool,
+) (*sysmdns.ZeroconfServer, error) {
+	ifaces, err := sysnet.FilterInterfaces(func(iface net.Interface) bool {
+		if len(config.Mdns.Server.Ifaces) == 0 {
+			return iface.Flags&net.FlagMulticast != 0
+		}
+
+		return slices.Contains(config.Mdns.Server.Ifaces, iface.Name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to select network interfaces: %w", err)
+	}
+
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("no network interfaces available for mDNS server")
+	}
+
+	service := &sysmdns.Service{
+		Instance: config.Mdns.Server.Instance,
+		Name:     sysmdns.ServiceName(sysmdns.ServiceTypeHTTP, sysmdns.ProtoTCP),
+		Hostname: config.Mdns.Server.Hostname,
+		Port:     port,
+	}
+	service.AddTxtRecord("api_base_path", "/api/v1")
+	if https {
+		service.AddTxtRecord("scheme", "https")
+	}
+
+	return sysmdns.NewZeroconfServer([]*sysmdns.Service{service}, ifaces), nil
+}
@@ -0,0 +1,86 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/tendry-lab/device-hub/components/system/syscore"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "token" {
+		if err := runTokenCommand(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
+	configPath := flag.String("config", "", "path to the YAML configuration file")
+	flag.Parse()
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "error: missed -config flag")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*configPath); err != nil {
+		syscore.LogErr.Printf("device-hub failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string) error {
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	if config.Log.Path != "" {
+		if err := syscore.SetLogFile(config.Log.Path); err != nil {
+			return fmt.Errorf("failed to setup log file: path=%s err=%w", config.Log.Path, err)
+		}
+	}
+
+	signalCtx, signalStop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer signalStop()
+
+	ctx, cancel := context.WithCancel(signalCtx)
+	defer cancel()
+
+	h, err := newHub(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	startErr := h.start()
+	if startErr == nil {
+		syscore.LogInf.Printf("device-hub started")
+
+		<-ctx.Done()
+
+		syscore.LogInf.Printf("device-hub stopping: %v", context.Cause(ctx))
+	}
+
+	cancel()
+
+	stopErr := h.stop()
+
+	if startErr != nil {
+		return startErr
+	}
+
+	return stopErr
+}
@@ -0,0 +1,112 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"github.com/tendry-lab/device-hub/components/http/htauth"
+	"github.com/tendry-lab/device-hub/components/storage/stcore"
+)
+
+// tokenDBTimeout - how long to wait for the database lock, the database is locked
+// while device-hub is running.
+const tokenDBTimeout = time.Second
+
+const tokenCommandUsage = `usage:
+  device-hub token create -config <path> -name <name> -scope <scope>
+  device-hub token list -config <path>
+  device-hub token remove -config <path> -name <name>`
+
+// runTokenCommand manages the API tokens persisted in the device-hub database.
+//
+// Remarks:
+//   - device-hub should be stopped, use the HTTP API to manage tokens while it's running.
+func runTokenCommand(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missed token command\n%s", tokenCommandUsage)
+	}
+
+	flags := flag.NewFlagSet("token "+args[0], flag.ContinueOnError)
+	configPath := flags.String("config", "", "path to the YAML configuration file")
+	name := flags.String("name", "", "token name")
+	scope := flags.String("scope", "", "token scope: ingest, read, device-admin or system-admin")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *configPath == "" {
+		return fmt.Errorf("missed -config flag\n%s", tokenCommandUsage)
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	if config.Storage.Path == "" {
+		return fmt.Errorf("storage.path isn't configured, tokens can't be persisted")
+	}
+
+	db, err := stcore.NewBboltDB(config.Storage.Path, &bbolt.Options{Timeout: tokenDBTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open DB, stop device-hub or use the HTTP API:"+
+			" path=%s err=%w", config.Storage.Path, err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "error: failed to close DB: %v\n", err)
+		}
+	}()
+
+	store := htauth.NewTokenStore(stcore.NewBboltDBBucket(db, tokenBucket))
+
+	switch args[0] {
+	case "create":
+		parsedScope, err := htauth.ParseScope(*scope)
+		if err != nil {
+			return err
+		}
+
+		token, _, err := store.Create(*name, parsedScope)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out, token)
+
+	case "list":
+		infos, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tSCOPE\tCREATED AT")
+
+		for _, info := range infos {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", info.Name, info.Scope, info.CreatedAt)
+		}
+
+		return writer.Flush()
+
+	case "remove":
+		return store.Remove(*name)
+
+	default:
+		return fmt.Errorf("unknown token command: %s\n%s", args[0], tokenCommandUsage)
+	}
+
+	return nil
+}
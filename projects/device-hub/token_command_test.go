@@ -0,0 +1,56 @@
+/*
+ * SPDX-FileCopyrightText: 2025 Tendry Lab
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	configPath := filepath.Join(dir, "device-hub.yml")
+	require.Nil(t, os.WriteFile(configPath, []byte(`
+storage:
+  path: `+filepath.Join(dir, "bbolt.db")+`
+  backend: embedded
+`), 0600))
+
+	var out bytes.Buffer
+
+	require.Nil(t, runTokenCommand([]string{
+		"create", "-config", configPath, "-name", "admin", "-scope", "system-admin",
+	}, &out))
+	require.True(t, strings.HasPrefix(out.String(), "dh_"))
+
+	require.NotNil(t, runTokenCommand([]string{
+		"create", "-config", configPath, "-name", "admin", "-scope", "read",
+	}, &out))
+	require.NotNil(t, runTokenCommand([]string{
+		"create", "-config", configPath, "-name", "grafana", "-scope", "root",
+	}, &out))
+
+	out.Reset()
+	require.Nil(t, runTokenCommand([]string{"list", "-config", configPath}, &out))
+	require.Contains(t, out.String(), "admin")
+	require.Contains(t, out.String(), "system-admin")
+
+	require.Nil(t, runTokenCommand([]string{
+		"remove", "-config", configPath, "-name", "admin",
+	}, &out))
+	require.NotNil(t, runTokenCommand([]string{
+		"remove", "-config", configPath, "-name", "admin",
+	}, &out))
+
+	require.NotNil(t, runTokenCommand([]string{"rotate", "-config", configPath}, &out))
+	require.NotNil(t, runTokenCommand([]string{}, &out))
+}